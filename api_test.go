@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	"github.com/OpenFero/openfero/pkg/alertstore/memory"
+	"github.com/OpenFero/openfero/pkg/handlers"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	"github.com/OpenFero/openfero/pkg/models"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+const testDefinition = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: disk-cleanup
+spec:
+  template:
+    spec:
+      containers:
+      - name: cleanup
+        image: busybox:1.37
+      restartPolicy: Never
+`
+
+// newAPIServer returns a server with one cluster that holds a definition and two jobs, the
+// older one with a pod and triggered by an alert
+func newAPIServer(t *testing.T) *handlers.Server {
+	t.Helper()
+	exitCode := int32(0)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "disk-cleanup-old-x7k2p", Namespace: "openfero", Labels: map[string]string{batchv1.JobNameLabel: "disk-cleanup-old"}},
+		Spec:       corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{{Name: "cleanup", Image: "busybox:1.37"}}},
+		Status: corev1.PodStatus{Phase: corev1.PodSucceeded, ContainerStatuses: []corev1.ContainerStatus{{
+			Name: "cleanup", RestartCount: 1,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: "Completed"}},
+		}}},
+	}
+	client := &kubernetes.Client{
+		Name:                    "local",
+		Clientset:               fake.NewClientset(pod),
+		ConfigmapNamespace:      "openfero",
+		JobDestinationNamespace: "openfero",
+		ConfigMapStore:          cache.NewStore(cache.MetaNamespaceKeyFunc),
+		JobStore:                cache.NewStore(cache.MetaNamespaceKeyFunc),
+		LabelSelector:           &metav1.LabelSelector{MatchLabels: map[string]string{"app": "openfero"}},
+	}
+	objects := []struct {
+		store cache.Store
+		obj   interface{}
+	}{
+		{client.ConfigMapStore, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "openfero-diskfull-firing", Namespace: "openfero", Annotations: map[string]string{kubernetes.RateLimitAnnotation: "3/1h"}},
+			Data:       map[string]string{"DiskFull": testDefinition},
+		}},
+		{client.JobStore, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "disk-cleanup-old", Namespace: "openfero", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+				Annotations: map[string]string{kubernetes.DefinitionAnnotation: "openfero-diskfull-firing", kubernetes.AlertnameAnnotation: "DiskFull"}},
+			Status: batchv1.JobStatus{
+				StartTime:      &metav1.Time{Time: time.Now().Add(-time.Hour)},
+				CompletionTime: &metav1.Time{Time: time.Now().Add(-time.Hour + 90*time.Second)},
+				Succeeded:      1,
+				Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+			},
+		}},
+		{client.JobStore, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "disk-cleanup-new", Namespace: "openfero", CreationTimestamp: metav1.NewTime(time.Now())},
+			Status:     batchv1.JobStatus{Active: 1},
+		}},
+	}
+	for _, o := range objects {
+		if err := o.store.Add(o.obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store := memory.NewMemoryStore(10)
+	alert := alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull", "instance": "node-1"}}
+	if err := store.SaveAlertWithJobInfo(alert, "firing", &alertstore.JobInfo{JobName: "disk-cleanup-old"}); err != nil {
+		t.Fatal(err)
+	}
+	return &handlers.Server{Clusters: kubernetes.NewClusterSet(client, ""), AlertStore: store}
+}
+
+// get serves a request with the routes of the API
+func get(server *handlers.Server, url string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/alerts", server.AlertListGetHandler)
+	mux.HandleFunc("GET /api/v1/definitions", server.DefinitionsGetHandler)
+	mux.HandleFunc("GET /api/v1/definitions/{configmap}/{key}", server.DefinitionGetHandler)
+	mux.HandleFunc("GET /api/v1/jobs", server.JobsGetHandler)
+	mux.HandleFunc("GET /api/v1/jobs/{name}", server.JobGetHandler)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", url, nil))
+	return rr
+}
+
+func TestAPIDefinitions(t *testing.T) {
+	server := newAPIServer(t)
+
+	var definitions []models.JobInfo
+	if err := json.NewDecoder(get(server, "/api/v1/definitions").Body).Decode(&definitions); err != nil {
+		t.Fatal(err)
+	}
+	if len(definitions) != 1 || definitions[0].JobName != "DiskFull" || definitions[0].RateLimit != "3/1h0m0s" {
+		t.Errorf("definitions = %+v; want DiskFull with its rate limit", definitions)
+	}
+
+	rr := get(server, "/api/v1/definitions/openfero-diskfull-firing/DiskFull?label=namespace=payments")
+	var definition models.Definition
+	if err := json.NewDecoder(rr.Body).Decode(&definition); err != nil {
+		t.Fatal(err)
+	}
+	if definition.Job == nil || definition.Job.Name != "disk-cleanup" || definition.Image != "busybox:1.37" {
+		t.Errorf("definition = %+v; want the parsed job", definition)
+	}
+	// The manifest is rendered like the job OpenFero creates for the alert
+	for _, want := range []string{"OPENFERO_NAMESPACE", "payments", "app: openfero", "openfero.io/definition: openfero-diskfull-firing", "ttlSecondsAfterFinished"} {
+		if !strings.Contains(definition.Manifest, want) {
+			t.Errorf("manifest misses %q:\n%s", want, definition.Manifest)
+		}
+	}
+}
+
+func TestAPIJobs(t *testing.T) {
+	server := newAPIServer(t)
+
+	var jobs []models.Job
+	if err := json.NewDecoder(get(server, "/api/v1/jobs").Body).Decode(&jobs); err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].Name != "disk-cleanup-new" || jobs[0].State != "running" || jobs[1].State != "succeeded" {
+		t.Fatalf("jobs = %+v; want the running job before the succeeded one", jobs)
+	}
+
+	var job models.Job
+	if err := json.NewDecoder(get(server, "/api/v1/jobs/disk-cleanup-old").Body).Decode(&job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Cluster != "local" || job.Definition != "openfero-diskfull-firing" || job.Alertname != "DiskFull" {
+		t.Errorf("job = %+v; want the provenance of the job", job)
+	}
+	if job.DurationSeconds != 90 || len(job.Conditions) != 1 || job.Conditions[0].Type != "Complete" {
+		t.Errorf("job = %+v; want the duration and the Complete condition", job)
+	}
+	if job.Alert == nil || job.Alert.Alert.Labels["instance"] != "node-1" {
+		t.Errorf("alert = %+v; want the entry that triggered the job", job.Alert)
+	}
+	if len(job.Pods) != 1 || job.Pods[0].Node != "node-1" || len(job.Pods[0].Containers) != 1 {
+		t.Fatalf("pods = %+v; want the pod of the job", job.Pods)
+	}
+	container := job.Pods[0].Containers[0]
+	if container.State != "terminated" || container.ExitCode == nil || *container.ExitCode != 0 || container.RestartCount != 1 {
+		t.Errorf("container = %+v; want terminated with exit code 0 after a restart", container)
+	}
+
+	// Jobs without pods or alert are listed too
+	if jobs[0].Alert != nil || len(jobs[0].Pods) != 0 || jobs[1].Alert == nil || len(jobs[1].Pods) != 1 {
+		t.Errorf("jobs = %+v; want the pod and alert of the older job only", jobs)
+	}
+}
+
+func TestAPIAlerts(t *testing.T) {
+	server := newAPIServer(t)
+	alert := alertstore.Alert{Labels: map[string]string{"alertname": "KubeQuotaExceeded"}}
+	if err := server.AlertStore.SaveAlert(alert, "firing"); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := get(server, "/api/v1/alerts?limit=1")
+	var page alertstore.Page
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Alerts) != 1 || page.NextCursor == "" || rr.Header().Get("X-Next-Cursor") != page.NextCursor {
+		t.Fatalf("page = %+v; want one alert and the cursor in the body and header", page)
+	}
+
+	cursor := page.NextCursor
+	page = alertstore.Page{}
+	if err := json.NewDecoder(get(server, "/api/v1/alerts?limit=1&cursor="+url.QueryEscape(cursor)).Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Alerts) != 1 || page.NextCursor != "" || page.Alerts[0].Alert.Labels["alertname"] != "DiskFull" {
+		t.Errorf("last page = %+v; want the older alert without a cursor", page)
+	}
+}
+
+func TestAPIImportLimit(t *testing.T) {
+	server := newAPIServer(t)
+	line := `{"alert":{"labels":{"alertname":"Imported"}},"status":"firing","timestamp":"2025-06-01T02:00:00Z"}` + "\n"
+	server.MaxImportBytes = int64(len(line))
+
+	for body, status := range map[string]int{
+		line:                    http.StatusOK,
+		strings.Repeat(line, 2): http.StatusRequestEntityTooLarge,
+	} {
+		rr := httptest.NewRecorder()
+		server.AlertsImportPostHandler(rr, httptest.NewRequest(http.MethodPost, "/api/v1/alerts/import", strings.NewReader(body)))
+		if rr.Code != status {
+			t.Errorf("import of %d bytes = %d %s; want %d", len(body), rr.Code, rr.Body.String(), status)
+		}
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	server := newAPIServer(t)
+
+	for url, status := range map[string]int{
+		"/api/v1/jobs/missing":                                                  http.StatusNotFound,
+		"/api/v1/jobs?cluster=remote":                                           http.StatusNotFound,
+		"/api/v1/definitions/openfero-diskfull-firing/Up":                       http.StatusNotFound,
+		"/api/v1/definitions/openfero-diskfull-firing/DiskFull?label=namespace": http.StatusBadRequest,
+		"/api/v1/alerts?limit=0":                                                http.StatusBadRequest,
+		"/api/v1/alerts?q=%7Bnamespace%7D":                                      http.StatusBadRequest,
+	} {
+		rr := get(server, url)
+		var body models.APIError
+		if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+			t.Errorf("%s: body is no error: %v", url, err)
+			continue
+		}
+		if rr.Code != status || body.Status != status || body.Error != http.StatusText(status) || body.Message == "" {
+			t.Errorf("%s = %d %+v; want %d with a message", url, rr.Code, body, status)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/handlers"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// addPendingJob adds a job waiting for approval to the local cluster of the server
+func addPendingJob(t *testing.T, server *handlers.Server, name string) {
+	t.Helper()
+	client, _ := server.Clusters.Get("local")
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openfero", Annotations: map[string]string{}}}
+	kubernetes.AddApprovalGate(job, time.Now().Add(time.Hour))
+	if _, err := client.Clientset.BatchV1().Jobs("openfero").Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.JobStore.Add(job); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApprovalsRequireApproverHeader(t *testing.T) {
+	tests := []struct {
+		name           string
+		approverHeader string
+		headers        map[string]string
+		form           string
+		wantCode       int
+	}{
+		{"approvals disabled", "", map[string]string{"X-Forwarded-User": "jane"}, "", http.StatusForbidden},
+		{"header missing", "X-Forwarded-User", nil, "", http.StatusForbidden},
+		{"form value ignored", "X-Forwarded-User", nil, "approver=jane", http.StatusForbidden},
+		{"other header ignored", "X-Forwarded-User", map[string]string{"X-Auth-Request-User": "jane"}, "", http.StatusForbidden},
+		{"configured header", "X-Forwarded-User", map[string]string{"X-Forwarded-User": "jane"}, "", http.StatusSeeOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newAPIServer(t)
+			server.ApproverHeader = tt.approverHeader
+			addPendingJob(t, server, "disk-cleanup-pending")
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /approvals/{cluster}/{job}/approve", server.ApprovePostHandler)
+			req := httptest.NewRequest(http.MethodPost, "/approvals/local/disk-cleanup-pending/approve", strings.NewReader(tt.form))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			for header, value := range tt.headers {
+				req.Header.Set(header, value)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d; want %d (%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
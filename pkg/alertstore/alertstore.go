@@ -1,9 +1,14 @@
 package alertstore
 
 import (
+	"context"
+	"errors"
 	"time"
 )
 
+// ErrNotFound is returned when an entry to update does not exist in the store
+var ErrNotFound = errors.New("alert entry not found")
+
 // AlertEntry represents a single alert in the store
 type AlertEntry struct {
 	Alert     Alert     `json:"alert"`
@@ -18,13 +23,48 @@ type Alert struct {
 	Annotations map[string]string `json:"annotations"`
 	StartsAt    string            `json:"startsAt,omitempty"`
 	EndsAt      string            `json:"endsAt,omitempty"`
+	Fingerprint string            `json:"fingerprint,omitempty"`
 }
 
 // JobInfo contains information about a triggered job
 type JobInfo struct {
-	ConfigMapName string `json:"configMapName,omitempty"`
-	JobName       string `json:"jobName,omitempty"`
-	Image         string `json:"image,omitempty"`
+	ConfigMapName  string          `json:"configMapName,omitempty"`
+	JobName        string          `json:"jobName,omitempty"`
+	Image          string          `json:"image,omitempty"`
+	Cluster        string          `json:"cluster,omitempty"`
+	DryRun         bool            `json:"dryRun,omitempty"`         // Job was only validated with a server-side dry-run
+	Manifest       string          `json:"manifest,omitempty"`       // Rendered job manifest of a dry-run
+	Approval       *Approval       `json:"approval,omitempty"`       // Approval state of a job that requires approval
+	SkipReason     string          `json:"skipReason,omitempty"`     // Why no job was created, e.g. rate-limited
+	LockKey        string          `json:"lockKey,omitempty"`        // Rendered lock key of the job
+	ReplacedJobs   []string        `json:"replacedJobs,omitempty"`   // Running jobs deleted to make room for this job
+	CancelledAt    time.Time       `json:"cancelledAt,omitzero"`     // Time the job was deleted because its alert resolved
+	CreateAttempts []CreateAttempt `json:"createAttempts,omitempty"` // Attempts to create the job
+	FailureReason  string          `json:"failureReason,omitempty"`  // Kubernetes status reason if the job could not be created
+	StartedAt      time.Time       `json:"startedAt,omitzero"`       // Time the job started running
+	CompletedAt    time.Time       `json:"completedAt,omitzero"`     // Time the job succeeded or failed
+	Outcome        string          `json:"outcome,omitempty"`        // succeeded or failed once the job finished
+}
+
+// Job outcomes
+const (
+	JobOutcomeSucceeded = "succeeded"
+	JobOutcomeFailed    = "failed"
+)
+
+// CreateAttempt records one attempt to create a job
+type CreateAttempt struct {
+	Time   time.Time `json:"time"`
+	Error  string    `json:"error,omitempty"`
+	Reason string    `json:"reason,omitempty"` // Kubernetes status reason of the error, e.g. TooManyRequests
+}
+
+// Approval contains the approval state of a job
+type Approval struct {
+	State     string    `json:"state"` // pending, approved, rejected or expired
+	ExpiresAt time.Time `json:"expiresAt"`
+	DecidedBy string    `json:"decidedBy,omitempty"`
+	DecidedAt time.Time `json:"decidedAt,omitzero"`
 }
 
 // Store defines the interface for alert storage implementations
@@ -35,12 +75,31 @@ type Store interface {
 	// SaveAlertWithJobInfo saves an alert to the store with job information
 	SaveAlertWithJobInfo(alert Alert, status string, jobInfo *JobInfo) error
 
+	// UpdateJobInfo applies update to the job information of the entry that triggered jobName
+	UpdateJobInfo(jobName string, update func(*JobInfo)) error
+
 	// GetAlerts retrieves alerts, optionally filtered by query
 	GetAlerts(query string, limit int) ([]AlertEntry, error)
 
+	// ListAlerts retrieves a page of alerts
+	ListAlerts(opts ListOptions) (*Page, error)
+
+	// ImportAlerts adds entries with their timestamps, skipping entries the store already
+	// holds by EntryKey, and returns the number of added entries
+	ImportAlerts(entries []AlertEntry) (int, error)
+
+	// Subscribe returns the events of the store until ctx is done
+	Subscribe(ctx context.Context) <-chan Event
+
 	// Initialize prepares the store for use
 	Initialize() error
 
 	// Close cleans up any resources
 	Close() error
 }
+
+// ReadinessChecker is implemented by stores that are not ready right after Initialize
+type ReadinessChecker interface {
+	// Ready returns why the store is not ready, nil once it is
+	Ready() error
+}
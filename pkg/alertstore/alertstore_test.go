@@ -0,0 +1,24 @@
+package alertstore
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobInfoOmitsZeroTimes(t *testing.T) {
+	info := JobInfo{JobName: "disk-cleanup", Approval: &Approval{State: "pending", ExpiresAt: time.Now()}}
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "0001-01-01") {
+		t.Errorf("JSON = %s; want no zero times", data)
+	}
+	for _, field := range []string{"cancelledAt", "startedAt", "completedAt", "decidedAt"} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("JSON = %s; want %s omitted", data, field)
+		}
+	}
+}
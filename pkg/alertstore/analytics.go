@@ -0,0 +1,220 @@
+package alertstore
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// analyticsPageSize is the number of entries read from the store per page while collecting analytics
+const analyticsPageSize = 1000
+
+// Analytics are remediation statistics of a time window
+type Analytics struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Total sums up all remediations
+	Total RemediationStats `json:"total"`
+	// Remediations are the statistics per alert and job definition, most runs first
+	Remediations []RemediationStats `json:"remediations"`
+}
+
+// RemediationStats are the statistics of the jobs one job definition ran for one alert
+type RemediationStats struct {
+	Alertname  string `json:"alertname,omitempty"`
+	Definition string `json:"definition,omitempty"` // Name of the ConfigMap of the job definition
+	// Runs are the created jobs, including jobs that could not be created
+	Runs      int `json:"runs"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	// SuccessRate and FailureRate are the shares of the finished runs, zero without any
+	SuccessRate float64 `json:"successRate"`
+	FailureRate float64 `json:"failureRate"`
+	// Duration is the time from the start to the end of finished jobs
+	Duration DurationStats `json:"duration"`
+	// TimeToStart is the time from the start of the alert to the start of the job
+	TimeToStart DurationStats `json:"timeToStart"`
+	// TimeToResolve is the time from the success of the job to the resolution of the alert
+	TimeToResolve DurationStats `json:"timeToResolve"`
+
+	durations, toStart, toResolve []float64
+}
+
+// DurationStats are percentiles of durations in seconds
+type DurationStats struct {
+	Count      int     `json:"count"`
+	P50Seconds float64 `json:"p50Seconds"`
+	P95Seconds float64 `json:"p95Seconds"`
+}
+
+// CollectAnalytics computes the analytics of the runs triggered by entries matching query from from to to.
+// Resolutions are looked up until now, so jobs near the end of the window get their time to resolve.
+// The query only selects the runs, resolutions are taken from all entries.
+func CollectAnalytics(store Store, query *Query, from, to time.Time) (*Analytics, error) {
+	opts := ListOptions{Limit: analyticsPageSize, From: from, Order: OrderAsc}
+	var entries []AlertEntry
+	for {
+		page, err := store.ListAlerts(opts)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page.Alerts...)
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	analytics := Analyze(entries, query, from, to)
+	return &analytics, nil
+}
+
+// Analyze computes the analytics of the runs triggered by entries matching query, nil matches all,
+// from from, inclusive, to to, exclusive. All entries count as resolutions, also those after the window.
+func Analyze(entries []AlertEntry, query *Query, from, to time.Time) Analytics {
+	sorted := append([]AlertEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	// Resolutions per incident key, oldest first
+	resolutions := make(map[string][]AlertEntry)
+	for _, entry := range sorted {
+		if isResolved(entry) {
+			key := IncidentKey(entry.Alert)
+			resolutions[key] = append(resolutions[key], entry)
+		}
+	}
+
+	type group struct{ alertname, definition string }
+	stats := make(map[group]*RemediationStats)
+	total := &RemediationStats{}
+	for _, entry := range sorted {
+		if entry.Timestamp.Before(from) || !entry.Timestamp.Before(to) || !isRun(entry.JobInfo) {
+			continue
+		}
+		if query != nil && !query.Matches(entry) {
+			continue
+		}
+		g := group{alertname: entry.Alert.Labels["alertname"], definition: entry.JobInfo.ConfigMapName}
+		s := stats[g]
+		if s == nil {
+			s = &RemediationStats{Alertname: g.alertname, Definition: g.definition}
+			stats[g] = s
+		}
+		run := measureRun(entry, resolutions[IncidentKey(entry.Alert)])
+		s.add(run)
+		total.add(run)
+	}
+
+	analytics := Analytics{From: from, To: to, Total: total.finish(), Remediations: make([]RemediationStats, 0, len(stats))}
+	for _, s := range stats {
+		analytics.Remediations = append(analytics.Remediations, s.finish())
+	}
+	sort.Slice(analytics.Remediations, func(i, j int) bool {
+		a, b := analytics.Remediations[i], analytics.Remediations[j]
+		if a.Runs != b.Runs {
+			return a.Runs > b.Runs
+		}
+		if a.Alertname != b.Alertname {
+			return a.Alertname < b.Alertname
+		}
+		return a.Definition < b.Definition
+	})
+	return analytics
+}
+
+// isRun reports whether a job was created or attempted to, other than dry-runs, skipped and pending jobs
+func isRun(jobInfo *JobInfo) bool {
+	switch JobState(jobInfo) {
+	case JobStateCreated, JobStateFailed, JobStateSucceeded, JobStateCancelled:
+		return true
+	}
+	return false
+}
+
+// run holds the measurements of one job, durations are negative where unknown
+type run struct {
+	state                        string
+	duration, toStart, toResolve float64
+}
+
+// measureRun measures the job of an entry. resolutions are the resolved entries of its alert, oldest first.
+func measureRun(entry AlertEntry, resolutions []AlertEntry) run {
+	jobInfo := entry.JobInfo
+	r := run{state: JobState(jobInfo), duration: -1, toStart: -1, toResolve: -1}
+	if !jobInfo.StartedAt.IsZero() {
+		alertStart := entry.Timestamp
+		if startsAt, err := time.Parse(time.RFC3339, entry.Alert.StartsAt); err == nil && !startsAt.IsZero() {
+			alertStart = startsAt
+		}
+		r.toStart = seconds(jobInfo.StartedAt.Sub(alertStart))
+		if !jobInfo.CompletedAt.IsZero() {
+			r.duration = seconds(jobInfo.CompletedAt.Sub(jobInfo.StartedAt))
+		}
+	}
+	if r.state != JobStateSucceeded || jobInfo.CompletedAt.IsZero() {
+		return r
+	}
+	for _, resolved := range resolutions {
+		if !resolved.Timestamp.After(entry.Timestamp) {
+			continue
+		}
+		resolvedAt := resolved.Timestamp
+		if endsAt, err := time.Parse(time.RFC3339, resolved.Alert.EndsAt); err == nil && !endsAt.IsZero() {
+			resolvedAt = endsAt
+		}
+		r.toResolve = seconds(resolvedAt.Sub(jobInfo.CompletedAt))
+		break
+	}
+	return r
+}
+
+// seconds converts a duration to seconds, clock skew between Alertmanager and Kubernetes is clamped to zero
+func seconds(d time.Duration) float64 {
+	return math.Max(d.Seconds(), 0)
+}
+
+func (s *RemediationStats) add(r run) {
+	s.Runs++
+	switch r.state {
+	case JobStateSucceeded:
+		s.Succeeded++
+	case JobStateFailed:
+		s.Failed++
+	}
+	if r.duration >= 0 {
+		s.durations = append(s.durations, r.duration)
+	}
+	if r.toStart >= 0 {
+		s.toStart = append(s.toStart, r.toStart)
+	}
+	if r.toResolve >= 0 {
+		s.toResolve = append(s.toResolve, r.toResolve)
+	}
+}
+
+// finish computes the rates and percentiles
+func (s *RemediationStats) finish() RemediationStats {
+	if finished := s.Succeeded + s.Failed; finished > 0 {
+		s.SuccessRate = float64(s.Succeeded) / float64(finished)
+		s.FailureRate = float64(s.Failed) / float64(finished)
+	}
+	s.Duration = durationStats(s.durations)
+	s.TimeToStart = durationStats(s.toStart)
+	s.TimeToResolve = durationStats(s.toResolve)
+	result := *s
+	result.durations, result.toStart, result.toResolve = nil, nil, nil
+	return result
+}
+
+// durationStats computes nearest-rank percentiles
+func durationStats(values []float64) DurationStats {
+	if len(values) == 0 {
+		return DurationStats{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p * float64(len(sorted))))
+		return sorted[max(rank, 1)-1]
+	}
+	return DurationStats{Count: len(sorted), P50Seconds: percentile(0.5), P95Seconds: percentile(0.95)}
+}
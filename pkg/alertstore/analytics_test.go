@@ -0,0 +1,105 @@
+package alertstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyze(t *testing.T) {
+	start := time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+	disk := Alert{Labels: map[string]string{"alertname": "DiskFull"}, Fingerprint: "d1", StartsAt: "2025-06-01T01:59:00Z"}
+	secondEpisode := Alert{Labels: map[string]string{"alertname": "DiskFull"}, Fingerprint: "d1", StartsAt: "2025-06-01T03:00:00Z"}
+	quota := Alert{Labels: map[string]string{"alertname": "KubeQuotaExceeded"}}
+	finished := func(outcome string, startedAt time.Time, took time.Duration) *JobInfo {
+		return &JobInfo{ConfigMapName: "cleanup", JobName: "cleanup", StartedAt: startedAt, CompletedAt: startedAt.Add(took), Outcome: outcome}
+	}
+
+	entries := []AlertEntry{
+		// Before the window
+		{Alert: disk, Status: "firing", Timestamp: start.Add(-time.Hour), JobInfo: finished(JobOutcomeSucceeded, start, time.Minute)},
+		{Alert: disk, Status: "firing", Timestamp: start, JobInfo: finished(JobOutcomeSucceeded, start.Add(time.Minute), 2*time.Minute)},
+		{Alert: quota, Status: "firing", Timestamp: start.Add(time.Minute), JobInfo: &JobInfo{ConfigMapName: "quota", FailureReason: "Forbidden"}},
+		{Alert: quota, Status: "firing", Timestamp: start.Add(2 * time.Minute), JobInfo: &JobInfo{ConfigMapName: "quota", SkipReason: "rate-limited"}},
+		{Alert: disk, Status: "resolved", Timestamp: start.Add(10 * time.Minute)},
+		{Alert: secondEpisode, Status: "firing", Timestamp: start.Add(time.Hour), JobInfo: finished(JobOutcomeFailed, start.Add(time.Hour), 10*time.Minute)},
+		{Alert: secondEpisode, Status: "firing", Timestamp: start.Add(61 * time.Minute), JobInfo: &JobInfo{ConfigMapName: "cleanup", JobName: "cleanup", DryRun: true}},
+		{Alert: secondEpisode, Status: "firing", Timestamp: start.Add(62 * time.Minute), JobInfo: &JobInfo{ConfigMapName: "cleanup", JobName: "cleanup"}},
+		// After the window, only a resolution
+		{Alert: Alert{Labels: disk.Labels, Fingerprint: "d1", EndsAt: "2025-06-01T04:30:00Z"}, Status: "resolved", Timestamp: start.Add(3 * time.Hour)},
+	}
+
+	analytics := Analyze(entries, nil, start, start.Add(2*time.Hour))
+	if len(analytics.Remediations) != 2 {
+		t.Fatalf("got %d remediations; want 2: %+v", len(analytics.Remediations), analytics.Remediations)
+	}
+	disks, quotas := analytics.Remediations[0], analytics.Remediations[1]
+
+	// Dry-runs and skipped jobs are no runs, running jobs are
+	if disks.Alertname != "DiskFull" || disks.Definition != "cleanup" || disks.Runs != 3 || disks.Succeeded != 1 || disks.Failed != 1 {
+		t.Errorf("disk remediation = %+v; want 3 runs, 1 succeeded and 1 failed", disks)
+	}
+	if disks.SuccessRate != 0.5 || disks.FailureRate != 0.5 {
+		t.Errorf("disk rates = %v and %v; want half of the finished runs each", disks.SuccessRate, disks.FailureRate)
+	}
+	if disks.Duration != (DurationStats{Count: 2, P50Seconds: 120, P95Seconds: 600}) {
+		t.Errorf("disk durations = %+v; want 2m and 10m", disks.Duration)
+	}
+	// From startsAt of the alert to the start of the job
+	if disks.TimeToStart != (DurationStats{Count: 2, P50Seconds: 0, P95Seconds: 120}) {
+		t.Errorf("disk time to start = %+v; want 2m and 0s", disks.TimeToStart)
+	}
+	// Only succeeded jobs resolve, from their completion to the first following resolution
+	if disks.TimeToResolve != (DurationStats{Count: 1, P50Seconds: 420, P95Seconds: 420}) {
+		t.Errorf("disk time to resolve = %+v; want 7m", disks.TimeToResolve)
+	}
+
+	if quotas.Runs != 1 || quotas.Failed != 1 || quotas.FailureRate != 1 || quotas.Duration.Count != 0 {
+		t.Errorf("quota remediation = %+v; want one failed run without durations", quotas)
+	}
+	if analytics.Total.Runs != 4 || analytics.Total.Succeeded != 1 || analytics.Total.Failed != 2 {
+		t.Errorf("total = %+v; want 4 runs, 1 succeeded and 2 failed", analytics.Total)
+	}
+}
+
+func TestAnalyzeQueryOnlySelectsRuns(t *testing.T) {
+	start := time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+	disk := Alert{Labels: map[string]string{"alertname": "DiskFull"}, Fingerprint: "d1"}
+	entries := []AlertEntry{
+		{Alert: disk, Status: "firing", Timestamp: start, JobInfo: &JobInfo{ConfigMapName: "cleanup", JobName: "cleanup", StartedAt: start, CompletedAt: start.Add(time.Minute), Outcome: JobOutcomeSucceeded}},
+		{Alert: disk, Status: "firing", Timestamp: start.Add(time.Minute), JobInfo: &JobInfo{ConfigMapName: "cleanup", FailureReason: "Forbidden"}},
+		{Alert: disk, Status: "resolved", Timestamp: start.Add(5 * time.Minute)},
+	}
+
+	for _, input := range []string{"job=succeeded", "status=firing"} {
+		query, err := ParseQuery(input, start)
+		if err != nil {
+			t.Fatal(err)
+		}
+		analytics := Analyze(entries, query, start, start.Add(time.Hour))
+		// The resolution does not match the query, but still resolves the run
+		if analytics.Total.TimeToResolve != (DurationStats{Count: 1, P50Seconds: 240, P95Seconds: 240}) {
+			t.Errorf("%s: time to resolve = %+v; want 4m", input, analytics.Total.TimeToResolve)
+		}
+	}
+
+	query, err := ParseQuery("job=succeeded", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analytics := Analyze(entries, query, start, start.Add(time.Hour)); analytics.Total.Runs != 1 {
+		t.Errorf("runs = %d; want only the succeeded run", analytics.Total.Runs)
+	}
+}
+
+func TestDurationStats(t *testing.T) {
+	values := make([]float64, 100)
+	for i := range values {
+		values[len(values)-1-i] = float64(i + 1)
+	}
+	if stats := durationStats(values); stats != (DurationStats{Count: 100, P50Seconds: 50, P95Seconds: 95}) {
+		t.Errorf("stats = %+v; want the nearest ranks 50 and 95", stats)
+	}
+	if stats := durationStats(nil); stats != (DurationStats{}) {
+		t.Errorf("stats of no values = %+v; want zero", stats)
+	}
+}
@@ -0,0 +1,751 @@
+package boltstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/metadata"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+const (
+	// maintenanceInterval is how often entries older than the retention are deleted
+	// and the file is checked for compaction
+	maintenanceInterval = 10 * time.Minute
+
+	// compactMinSize is the file size below which the file is never compacted
+	compactMinSize = 16 << 20
+
+	// compactTxMaxSize bounds the size of a single transaction while compacting
+	compactTxMaxSize = 64 << 20
+)
+
+var (
+	// alertsBucket maps time+ID keys to JSON encoded entries, so a cursor walks them in chronological order
+	alertsBucket = []byte("alerts")
+	// alertnameBucket indexes entries as alertname + 0x00 + entry key
+	alertnameBucket = []byte("idx_alertname")
+	// statusBucket indexes entries as lower case status + 0x00 + entry key
+	statusBucket = []byte("idx_status")
+	// jobsBucket maps a job name to the key of the entry that triggered it
+	jobsBucket = []byte("jobs")
+)
+
+// BoltStore implements alertstore.Store in an embedded bbolt file
+type BoltStore struct {
+	path            string
+	maxSize         int
+	maxPerAlertname int
+	retention       time.Duration
+	now             func() time.Time
+
+	// mutex guards db, compaction swaps it while holding the write lock
+	mutex sync.RWMutex
+	db    *bolt.DB
+	// count is the number of entries, only changed in write transactions which bbolt serializes
+	count int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+	feed alertstore.Feed
+}
+
+// NewBoltStore creates a new bbolt alert store in the file at path. It keeps at most
+// maxSize entries and deletes entries older than retention, zero disables either limit.
+func NewBoltStore(path string, maxSize int, retention time.Duration) (*BoltStore, error) {
+	return NewBoltStoreWithRetention(path, alertstore.RetentionPolicy{MaxAge: retention, MaxEntries: maxSize})
+}
+
+// NewBoltStoreWithRetention creates a new bbolt alert store in the file at path that applies
+// all rules of the retention policy
+func NewBoltStoreWithRetention(path string, policy alertstore.RetentionPolicy) (*BoltStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("a file path is required for the bolt alert store")
+	}
+	return &BoltStore{
+		path:            path,
+		maxSize:         policy.MaxEntries,
+		maxPerAlertname: policy.MaxPerAlertname,
+		retention:       policy.MaxAge,
+		now:             time.Now,
+	}, nil
+}
+
+// Initialize opens the file, compacts it if worthwhile and starts the maintenance loop
+func (s *BoltStore) Initialize() error {
+	db, err := s.open(s.path)
+	if err != nil {
+		return err
+	}
+	s.db = db
+
+	s.recount()
+	s.maintain()
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go s.maintenanceLoop()
+
+	log.Info("Bolt alert store initialized",
+		zap.String("path", s.path),
+		zap.Int("entries", s.count),
+		zap.Int("maxSize", s.maxSize),
+		zap.Int("alertnameQuota", s.maxPerAlertname),
+		zap.Duration("retention", s.retention))
+	return nil
+}
+
+// open opens a bbolt file and creates the buckets
+func (s *BoltStore) open(path string) (*bolt.DB, error) {
+	// A timeout fails fast if another process holds the file lock instead of blocking forever
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt alert store %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{alertsBucket, alertnameBucket, statusBucket, jobsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+	return db, nil
+}
+
+// Close stops the maintenance loop and closes the file
+func (s *BoltStore) Close() error {
+	if s.stop != nil {
+		close(s.stop)
+		s.wg.Wait()
+		s.stop = nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.db == nil {
+		return nil
+	}
+	err := s.db.Close()
+	s.db = nil
+	return err
+}
+
+// SaveAlert saves an alert to the store
+func (s *BoltStore) SaveAlert(alert alertstore.Alert, status string) error {
+	return s.SaveAlertWithJobInfo(alert, status, nil)
+}
+
+// SaveAlertWithJobInfo saves an alert with job information and drops the oldest entries above the
+// alertname quota and maxSize. Every write is a bbolt transaction that is synced to disk before it returns.
+func (s *BoltStore) SaveAlertWithJobInfo(alert alertstore.Alert, status string, jobInfo *alertstore.JobInfo) error {
+	entry := alertstore.AlertEntry{
+		Alert:     alert,
+		Status:    status,
+		Timestamp: s.now(),
+		JobInfo:   jobInfo,
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var quotaDeleted, deleted int
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		alerts := tx.Bucket(alertsBucket)
+		id, err := alerts.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := entryKey(entry.Timestamp, id)
+		if err := alerts.Put(key, data); err != nil {
+			return err
+		}
+		if err := putIndexes(tx, key, entry); err != nil {
+			return err
+		}
+		s.count++
+
+		quotaDeleted, deleted = 0, 0
+		quotaDeleted, err = s.enforceQuota(tx, alert.Labels["alertname"])
+		if err != nil {
+			return err
+		}
+		deleted, err = s.enforceMaxSize(tx)
+		return err
+	})
+	if err != nil {
+		s.recount()
+		return err
+	}
+	recordEvictions(quotaDeleted, deleted)
+	s.feed.Publish(alertstore.Event{Type: alertstore.EventAlert, Entry: entry})
+	return nil
+}
+
+// ImportAlerts adds entries with their timestamps, skipping entries the store already holds
+func (s *BoltStore) ImportAlerts(entries []alertstore.AlertEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	from, to := entries[0].Timestamp, entries[0].Timestamp
+	for _, entry := range entries {
+		if entry.Timestamp.Before(from) {
+			from = entry.Timestamp
+		}
+		if entry.Timestamp.After(to) {
+			to = entry.Timestamp
+		}
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var imported, quotaDeleted, deleted int
+	alertnames := make(map[string]bool)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		alerts := tx.Bucket(alertsBucket)
+
+		// Keys start with the timestamp, so only the time range of the batch can hold duplicates
+		existing := make(map[string]bool)
+		end := entryKey(to, math.MaxUint64)
+		c := alerts.Cursor()
+		for k, v := c.Seek(entryKey(from, 0)); k != nil && bytes.Compare(k, end) <= 0; k, v = c.Next() {
+			var entry alertstore.AlertEntry
+			if err := json.Unmarshal(v, &entry); err == nil {
+				existing[alertstore.EntryKey(entry)] = true
+			}
+		}
+
+		imported = 0
+		for _, entry := range alertstore.NewEntries(entries, existing) {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return fmt.Errorf("failed to marshal alert: %w", err)
+			}
+			id, err := alerts.NextSequence()
+			if err != nil {
+				return err
+			}
+			key := entryKey(entry.Timestamp, id)
+			if err := alerts.Put(key, data); err != nil {
+				return err
+			}
+			if err := putIndexes(tx, key, entry); err != nil {
+				return err
+			}
+			alertnames[entry.Alert.Labels["alertname"]] = true
+			imported++
+		}
+
+		s.count += imported
+
+		quotaDeleted, deleted = 0, 0
+		for alertname := range alertnames {
+			n, err := s.enforceQuota(tx, alertname)
+			if err != nil {
+				return err
+			}
+			quotaDeleted += n
+		}
+		var err error
+		deleted, err = s.enforceMaxSize(tx)
+		return err
+	})
+	if err != nil {
+		s.recount()
+		return 0, err
+	}
+	recordEvictions(quotaDeleted, deleted)
+	return imported, nil
+}
+
+// UpdateJobInfo updates the job information of the entry that triggered jobName
+func (s *BoltStore) UpdateJobInfo(jobName string, update func(*alertstore.JobInfo)) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var entry alertstore.AlertEntry
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		key := tx.Bucket(jobsBucket).Get([]byte(jobName))
+		if key == nil {
+			return alertstore.ErrNotFound
+		}
+		alerts := tx.Bucket(alertsBucket)
+		data := alerts.Get(key)
+		if data == nil {
+			return alertstore.ErrNotFound
+		}
+
+		entry = alertstore.AlertEntry{}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("failed to unmarshal alert: %w", err)
+		}
+		if entry.JobInfo == nil {
+			entry.JobInfo = &alertstore.JobInfo{JobName: jobName}
+		}
+		update(entry.JobInfo)
+
+		updated, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert: %w", err)
+		}
+		// The key is copied, bbolt memory is only valid until the transaction ends
+		return alerts.Put(append([]byte(nil), key...), updated)
+	})
+	if err != nil {
+		return err
+	}
+	s.feed.Publish(alertstore.Event{Type: alertstore.EventJob, Entry: entry})
+	return nil
+}
+
+// Subscribe returns the saved entries and job updates until ctx is done
+func (s *BoltStore) Subscribe(ctx context.Context) <-chan alertstore.Event {
+	return s.feed.Subscribe(ctx)
+}
+
+// GetAlerts retrieves alerts newest first, optionally filtered by query
+func (s *BoltStore) GetAlerts(query string, limit int) ([]alertstore.AlertEntry, error) {
+	page, err := s.ListAlerts(alertstore.ListOptions{Query: query, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return page.Alerts, nil
+}
+
+// ListAlerts retrieves a page of alerts. An alertname or status filter walks the index of
+// that value instead of every entry, and the walk starts at the window or cursor.
+func (s *BoltStore) ListAlerts(opts alertstore.ListOptions) (*alertstore.Page, error) {
+	list, err := alertstore.NewList(opts, s.now())
+	if err != nil {
+		return nil, err
+	}
+	start, err := startKey(list)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	err = s.db.View(func(tx *bolt.Tx) error {
+		alerts := tx.Bucket(alertsBucket)
+		next := s.entryIterator(tx, list.Query, list.Desc(), start)
+		for key := next(); key != nil; key = next() {
+			data := alerts.Get(key)
+			if data == nil {
+				continue
+			}
+			var entry alertstore.AlertEntry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return fmt.Errorf("failed to unmarshal alert: %w", err)
+			}
+			if !list.Add(keyPosition(key, entry), entry) {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Page(), nil
+}
+
+// startKey returns the entry key where a walk in the order of the list starts, nil starts at the end.
+// Keys start with the time, so seeking to a key skips everything before the window or cursor.
+func startKey(list *alertstore.List) ([]byte, error) {
+	var after []byte
+	if list.After != nil {
+		id, err := strconv.ParseUint(list.After.ID, 16, 64)
+		if err != nil {
+			return nil, alertstore.ErrInvalidCursor
+		}
+		after = entryKey(list.After.Time, id)
+	}
+
+	if list.Desc() {
+		start := after
+		if !list.To.IsZero() {
+			if to := entryKey(list.To, 0); start == nil || bytes.Compare(to, start) < 0 {
+				start = to
+			}
+		}
+		return start, nil
+	}
+	start := after
+	if !list.From.IsZero() {
+		if from := entryKey(list.From, 0); start == nil || bytes.Compare(from, start) > 0 {
+			start = from
+		}
+	}
+	return start, nil
+}
+
+// keyPosition returns the position of an entry, the sequence number orders entries with equal times
+func keyPosition(key []byte, entry alertstore.AlertEntry) alertstore.Position {
+	return alertstore.Position{Time: entry.Timestamp, ID: fmt.Sprintf("%016x", binary.BigEndian.Uint64(key[8:]))}
+}
+
+// entryIterator returns a function that yields entry keys in the given order from start on, from an
+// index if the query allows it. A newest first walk yields keys before start, the other keys from start on.
+func (s *BoltStore) entryIterator(tx *bolt.Tx, q *alertstore.Query, desc bool, start []byte) func() []byte {
+	var index []byte
+	var value string
+	if alertname, ok := q.EqualMatcher("alertname"); ok {
+		index, value = alertnameBucket, alertname
+	} else if q.Status != "" {
+		index, value = statusBucket, strings.ToLower(q.Status)
+	}
+
+	var prefix []byte
+	var cursor *bolt.Cursor
+	if index == nil {
+		cursor = tx.Bucket(alertsBucket).Cursor()
+	} else {
+		prefix = append([]byte(value), 0)
+		cursor = tx.Bucket(index).Cursor()
+	}
+
+	var k []byte
+	switch {
+	case desc && start == nil:
+		// Seek to the first key after the prefix and step back into it
+		if k, _ = cursor.Seek(append(append([]byte(nil), prefix...), 0xff)); k == nil {
+			k, _ = cursor.Last()
+		} else {
+			k, _ = cursor.Prev()
+		}
+	case desc:
+		if k, _ = cursor.Seek(append(append([]byte(nil), prefix...), start...)); k == nil {
+			k, _ = cursor.Last()
+		} else {
+			k, _ = cursor.Prev()
+		}
+	default:
+		k, _ = cursor.Seek(append(append([]byte(nil), prefix...), start...))
+	}
+
+	return func() []byte {
+		if k == nil || !bytes.HasPrefix(k, prefix) {
+			return nil
+		}
+		key := k[len(prefix):]
+		if desc {
+			k, _ = cursor.Prev()
+		} else {
+			k, _ = cursor.Next()
+		}
+		return key
+	}
+}
+
+// Prune deletes entries older than the retention, then entries above the alertname quota and
+// maxSize, so that lowered limits also apply to a file written before. It returns how many were deleted.
+func (s *BoltStore) Prune() (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var ageDeleted, quotaDeleted, deleted int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		ageDeleted, quotaDeleted, deleted = 0, 0, 0
+		if s.retention > 0 {
+			cutoff := entryKey(s.now().Add(-s.retention), 0)
+			// Keys start with the time, so the oldest entries come first
+			ageDeleted, err = deleteOldest(tx, func(k []byte, _ int) bool { return bytes.Compare(k, cutoff) < 0 })
+			if err != nil {
+				return err
+			}
+			s.count -= ageDeleted
+		}
+		if s.maxPerAlertname > 0 {
+			for _, alertname := range indexedAlertnames(tx) {
+				n, err := s.enforceQuota(tx, alertname)
+				if err != nil {
+					return err
+				}
+				quotaDeleted += n
+			}
+		}
+		deleted, err = s.enforceMaxSize(tx)
+		return err
+	})
+	if err != nil {
+		s.recount()
+		return 0, err
+	}
+	metadata.AlertStoreEvictionsTotal.WithLabelValues(alertstore.RetentionRuleAge).Add(float64(ageDeleted))
+	recordEvictions(quotaDeleted, deleted)
+	return ageDeleted + quotaDeleted + deleted, nil
+}
+
+// enforceQuota deletes the oldest entries of an alertname above the alertname quota
+func (s *BoltStore) enforceQuota(tx *bolt.Tx, alertname string) (int, error) {
+	if s.maxPerAlertname <= 0 {
+		return 0, nil
+	}
+	// Index keys of an alertname are ordered by entry key, so by time
+	prefix := indexKey(alertname, nil)
+	var keys [][]byte
+	cursor := tx.Bucket(alertnameBucket).Cursor()
+	for k, _ := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cursor.Next() {
+		keys = append(keys, append([]byte(nil), k[len(prefix):]...))
+	}
+	if len(keys) <= s.maxPerAlertname {
+		return 0, nil
+	}
+
+	alerts := tx.Bucket(alertsBucket)
+	deleted := 0
+	for _, key := range keys[:len(keys)-s.maxPerAlertname] {
+		data := alerts.Get(key)
+		if data == nil {
+			continue
+		}
+		if err := deleteIndexes(tx, key, append([]byte(nil), data...)); err != nil {
+			return deleted, err
+		}
+		if err := alerts.Delete(key); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	s.count -= deleted
+	return deleted, nil
+}
+
+// enforceMaxSize deletes the oldest entries above maxSize
+func (s *BoltStore) enforceMaxSize(tx *bolt.Tx) (int, error) {
+	if s.maxSize <= 0 || s.count <= s.maxSize {
+		return 0, nil
+	}
+	excess := s.count - s.maxSize
+	deleted, err := deleteOldest(tx, func(k []byte, n int) bool { return n < excess })
+	s.count -= deleted
+	return deleted, err
+}
+
+// indexedAlertnames returns the alertnames of the alertname index
+func indexedAlertnames(tx *bolt.Tx) []string {
+	var alertnames []string
+	cursor := tx.Bucket(alertnameBucket).Cursor()
+	for k, _ := cursor.First(); k != nil; {
+		alertname, _, _ := bytes.Cut(k, []byte{0})
+		alertnames = append(alertnames, string(alertname))
+		// Skip to the first key after all keys of this alertname
+		k, _ = cursor.Seek(append(append([]byte(nil), alertname...), 1))
+	}
+	return alertnames
+}
+
+// recordEvictions counts the entries evicted by the alertname quota and the count
+func recordEvictions(quotaDeleted int, deleted int) {
+	if quotaDeleted > 0 {
+		metadata.AlertStoreEvictionsTotal.WithLabelValues(alertstore.RetentionRuleAlertname).Add(float64(quotaDeleted))
+	}
+	if deleted > 0 {
+		metadata.AlertStoreEvictionsTotal.WithLabelValues(alertstore.RetentionRuleCount).Add(float64(deleted))
+	}
+}
+
+// recount counts the entries again after a failed write transaction may have changed count.
+// The caller must hold the read lock.
+func (s *BoltStore) recount() {
+	// An update transaction is used to exclude concurrent writers
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		s.count = tx.Bucket(alertsBucket).Stats().KeyN
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to count entries of alert store", zap.Error(err))
+	}
+}
+
+// Compact rewrites the file without free pages. bbolt never shrinks a file on its own,
+// so without compaction it keeps the size of its largest history.
+func (s *BoltStore) Compact() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tmpPath := s.path + ".compact"
+	_ = os.Remove(tmpPath)
+	dst, err := bolt.Open(tmpPath, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return fmt.Errorf("failed to create compacted file: %w", err)
+	}
+	if err := bolt.Compact(dst, s.db, compactTxMaxSize); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact bolt alert store: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	// Open the compacted file before the swap, so a failure leaves the old file in use
+	db, err := s.open(tmpPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	// The rename is atomic, a crash leaves either the old or the compacted file in place.
+	// Open files keep working across the rename, so the old file stays usable if it fails.
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = db.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace bolt alert store with compacted file: %w", err)
+	}
+	old := s.db
+	s.db = db
+	if err := old.Close(); err != nil {
+		log.Warn("Failed to close bolt alert store file replaced by compaction", zap.Error(err))
+	}
+	return nil
+}
+
+// needsCompaction reports whether more than half of a large file is free pages
+func (s *BoltStore) needsCompaction() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil || info.Size() < compactMinSize {
+		return false
+	}
+	stats := s.db.Stats()
+	free := int64(stats.FreePageN+stats.PendingPageN) * int64(s.db.Info().PageSize)
+	return free*2 > info.Size()
+}
+
+// maintain prunes old entries and compacts the file if needed
+func (s *BoltStore) maintain() {
+	deleted, err := s.Prune()
+	if err != nil {
+		log.Error("Failed to prune alert store", zap.Error(err))
+	} else if deleted > 0 {
+		log.Info("Pruned alert store", zap.Int("deleted", deleted), zap.Duration("retention", s.retention))
+	}
+
+	if s.needsCompaction() {
+		start := time.Now()
+		if err := s.Compact(); err != nil {
+			log.Error("Failed to compact alert store", zap.Error(err))
+			return
+		}
+		log.Info("Compacted alert store", zap.String("path", s.path), zap.Duration("duration", time.Since(start)))
+	}
+}
+
+// maintenanceLoop runs maintain periodically until the store is closed
+func (s *BoltStore) maintenanceLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(maintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.maintain()
+		}
+	}
+}
+
+// entryKey builds the key of an entry from its time and a unique sequence number
+func entryKey(t time.Time, id uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key[:8], uint64(t.UnixNano()))
+	binary.BigEndian.PutUint64(key[8:], id)
+	return key
+}
+
+// indexKey builds the key of an index entry
+func indexKey(value string, key []byte) []byte {
+	indexed := make([]byte, 0, len(value)+1+len(key))
+	indexed = append(indexed, value...)
+	indexed = append(indexed, 0)
+	return append(indexed, key...)
+}
+
+// putIndexes adds an entry to the secondary indexes
+func putIndexes(tx *bolt.Tx, key []byte, entry alertstore.AlertEntry) error {
+	if err := tx.Bucket(alertnameBucket).Put(indexKey(entry.Alert.Labels["alertname"], key), nil); err != nil {
+		return err
+	}
+	if err := tx.Bucket(statusBucket).Put(indexKey(strings.ToLower(entry.Status), key), nil); err != nil {
+		return err
+	}
+	if entry.JobInfo != nil && entry.JobInfo.JobName != "" {
+		return tx.Bucket(jobsBucket).Put([]byte(entry.JobInfo.JobName), key)
+	}
+	return nil
+}
+
+// deleteOldest deletes entries from the oldest on while more returns true for the key
+// and the number of entries deleted so far, and returns the number of deleted entries
+func deleteOldest(tx *bolt.Tx, more func(key []byte, deleted int) bool) (int, error) {
+	alerts := tx.Bucket(alertsBucket)
+
+	// Deleting while iterating moves the cursor, so the keys are collected first
+	var keys, values [][]byte
+	cursor := alerts.Cursor()
+	for k, v := cursor.First(); k != nil && more(k, len(keys)); k, v = cursor.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+		values = append(values, append([]byte(nil), v...))
+	}
+
+	for i, key := range keys {
+		if err := deleteIndexes(tx, key, values[i]); err != nil {
+			return i, err
+		}
+		if err := alerts.Delete(key); err != nil {
+			return i, err
+		}
+	}
+	return len(keys), nil
+}
+
+// deleteIndexes removes an entry from the secondary indexes
+func deleteIndexes(tx *bolt.Tx, key []byte, data []byte) error {
+	var entry alertstore.AlertEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		// Index entries of an unreadable entry are left behind, lookups skip them
+		return nil
+	}
+	if err := tx.Bucket(alertnameBucket).Delete(indexKey(entry.Alert.Labels["alertname"], key)); err != nil {
+		return err
+	}
+	if err := tx.Bucket(statusBucket).Delete(indexKey(strings.ToLower(entry.Status), key)); err != nil {
+		return err
+	}
+	if entry.JobInfo != nil && entry.JobInfo.JobName != "" {
+		jobs := tx.Bucket(jobsBucket)
+		if bytes.Equal(jobs.Get([]byte(entry.JobInfo.JobName)), key) {
+			return jobs.Delete([]byte(entry.JobInfo.JobName))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package boltstore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	// Initialize logger before running tests
+	if err := log.SetConfig(zap.NewDevelopmentConfig()); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func newTestStore(t *testing.T, path string, maxSize int, retention time.Duration) *BoltStore {
+	t.Helper()
+	store, err := NewBoltStore(path, maxSize, retention)
+	if err != nil {
+		t.Fatalf("NewBoltStore failed: %v", err)
+	}
+	if err := store.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func saveAlert(t *testing.T, store *BoltStore, alertname string, status string) {
+	t.Helper()
+	if err := store.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": alertname}}, status); err != nil {
+		t.Fatalf("SaveAlert failed: %v", err)
+	}
+}
+
+func alertnames(entries []alertstore.AlertEntry) []string {
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Alert.Labels["alertname"])
+	}
+	return names
+}
+
+func TestBoltStoreSearchAndIndexes(t *testing.T) {
+	store := newTestStore(t, filepath.Join(t.TempDir(), "alerts.db"), 0, 0)
+	saveAlert(t, store, "DiskFull", "firing")
+	saveAlert(t, store, "KubeQuotaExceeded", "firing")
+	saveAlert(t, store, "DiskFull", "resolved")
+	saveAlert(t, store, "DiskFullSoon", "FIRING")
+
+	entries, err := store.GetAlerts("", 0)
+	if err != nil || len(entries) != 4 || entries[0].Alert.Labels["alertname"] != "DiskFullSoon" {
+		t.Errorf("GetAlerts = %v (err %v); want 4 entries newest first", alertnames(entries), err)
+	}
+	if entries, _ := store.GetAlerts("diskfull", 2); len(entries) != 2 {
+		t.Errorf("GetAlerts(diskfull, 2) returned %d entries; want 2", len(entries))
+	}
+
+	// The alertname index matches exactly, DiskFullSoon shares the prefix but not the value
+	entries, err = store.GetAlerts(`{alertname="DiskFull"}`, 0)
+	if err != nil || len(entries) != 2 || entries[0].Status != "resolved" {
+		t.Errorf("GetAlerts by alertname = %+v (err %v); want 2 DiskFull entries newest first", entries, err)
+	}
+	entries, err = store.GetAlerts("status=firing", 0)
+	if err != nil || len(entries) != 3 {
+		t.Errorf("GetAlerts by status = %v (err %v); want 3 firing entries", alertnames(entries), err)
+	}
+}
+
+func TestBoltStoreRetentionByCount(t *testing.T) {
+	store := newTestStore(t, filepath.Join(t.TempDir(), "alerts.db"), 2, 0)
+	saveAlert(t, store, "First", "firing")
+	saveAlert(t, store, "Second", "firing")
+	saveAlert(t, store, "Third", "firing")
+
+	entries, err := store.GetAlerts("", 0)
+	if err != nil || len(entries) != 2 || entries[1].Alert.Labels["alertname"] != "Second" {
+		t.Errorf("GetAlerts = %v (err %v); want [Third Second]", alertnames(entries), err)
+	}
+	if entries, _ := store.GetAlerts(`{alertname="First"}`, 0); len(entries) != 0 {
+		t.Errorf("index still references the dropped entry")
+	}
+}
+
+func TestBoltStoreAlertnameQuota(t *testing.T) {
+	store, err := NewBoltStoreWithRetention(filepath.Join(t.TempDir(), "alerts.db"), alertstore.RetentionPolicy{MaxPerAlertname: 2})
+	if err != nil {
+		t.Fatalf("NewBoltStoreWithRetention failed: %v", err)
+	}
+	if err := store.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	saveAlert(t, store, "Rare", "firing")
+	saveAlert(t, store, "Noisy", "firing")
+	saveAlert(t, store, "Noisy", "resolved")
+	saveAlert(t, store, "Noisy", "firing")
+
+	entries, err := store.GetAlerts("", 0)
+	if got := alertnames(entries); err != nil || len(got) != 3 || got[2] != "Rare" {
+		t.Errorf("GetAlerts = %v (err %v); want [Noisy Noisy Rare]", got, err)
+	}
+	if entries, _ := store.GetAlerts(`{alertname="Noisy"}`, 0); len(entries) != 2 || entries[1].Status != "resolved" {
+		t.Errorf("GetAlerts(Noisy) = %+v; want the 2 newest entries", entries)
+	}
+
+	// A lowered quota also applies to the entries already in the file
+	store.maxPerAlertname = 1
+	if deleted, err := store.Prune(); err != nil || deleted != 1 {
+		t.Errorf("Prune = (%d, %v); want (1, nil)", deleted, err)
+	}
+}
+
+func TestBoltStoreRetentionByAge(t *testing.T) {
+	store := newTestStore(t, filepath.Join(t.TempDir(), "alerts.db"), 0, time.Hour)
+	now := time.Now()
+
+	store.now = func() time.Time { return now.Add(-2 * time.Hour) }
+	saveAlert(t, store, "Old", "firing")
+	store.now = func() time.Time { return now }
+	saveAlert(t, store, "New", "firing")
+
+	deleted, err := store.Prune()
+	if err != nil || deleted != 1 {
+		t.Fatalf("Prune = (%d, %v); want (1, nil)", deleted, err)
+	}
+	entries, err := store.GetAlerts("", 0)
+	if err != nil || len(entries) != 1 || entries[0].Alert.Labels["alertname"] != "New" {
+		t.Errorf("GetAlerts after prune = %v (err %v); want [New]", alertnames(entries), err)
+	}
+}
+
+func TestBoltStoreUpdateJobInfo(t *testing.T) {
+	store := newTestStore(t, filepath.Join(t.TempDir(), "alerts.db"), 0, 0)
+	alert := alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull"}}
+	if err := store.SaveAlertWithJobInfo(alert, "firing", &alertstore.JobInfo{JobName: "cleanup-abcde"}); err != nil {
+		t.Fatalf("SaveAlertWithJobInfo failed: %v", err)
+	}
+
+	err := store.UpdateJobInfo("cleanup-abcde", func(info *alertstore.JobInfo) { info.FailureReason = "Forbidden" })
+	if err != nil {
+		t.Fatalf("UpdateJobInfo failed: %v", err)
+	}
+	entries, _ := store.GetAlerts("", 0)
+	if len(entries) != 1 || entries[0].JobInfo.FailureReason != "Forbidden" {
+		t.Errorf("GetAlerts = %+v; want the updated job info", entries)
+	}
+	if err := store.UpdateJobInfo("unknown", func(*alertstore.JobInfo) {}); err != alertstore.ErrNotFound {
+		t.Errorf("UpdateJobInfo of an unknown job = %v; want ErrNotFound", err)
+	}
+}
+
+func TestBoltStoreSurvivesRestartAndCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.db")
+
+	store, err := NewBoltStore(path, 0, 0)
+	if err != nil {
+		t.Fatalf("NewBoltStore failed: %v", err)
+	}
+	if err := store.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	saveAlert(t, store, "DiskFull", "firing")
+	saveAlert(t, store, "DiskFull", "resolved")
+	if err := store.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	saveAlert(t, store, "KubeQuotaExceeded", "firing")
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	store = newTestStore(t, path, 0, 0)
+	entries, err := store.GetAlerts("", 0)
+	if err != nil || len(entries) != 3 {
+		t.Errorf("GetAlerts after restart = %v (err %v); want 3 entries", alertnames(entries), err)
+	}
+	if entries, _ := store.GetAlerts(`{alertname="DiskFull"}`, 0); len(entries) != 2 {
+		t.Errorf("alertname index lost entries across compaction and restart, got %d", len(entries))
+	}
+	if store.count != 3 {
+		t.Errorf("count = %d after restart; want 3", store.count)
+	}
+}
+
+func TestBoltStoreListAlerts(t *testing.T) {
+	store := newTestStore(t, filepath.Join(t.TempDir(), "alerts.db"), 0, 0)
+	start := time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+	for i, alertname := range []string{"A", "B", "A", "B", "A", "B"} {
+		// Pairs of entries share a timestamp, the sequence number orders them
+		at := start.Add(time.Duration(i/2) * time.Minute)
+		store.now = func() time.Time { return at }
+		saveAlert(t, store, alertname, "firing")
+	}
+	store.now = time.Now
+
+	walk := func(opts alertstore.ListOptions) []string {
+		var names []string
+		for {
+			page, err := store.ListAlerts(opts)
+			if err != nil {
+				t.Fatalf("ListAlerts(%+v) failed: %v", opts, err)
+			}
+			for _, entry := range page.Alerts {
+				names = append(names, entry.Alert.Labels["alertname"]+entry.Timestamp.Format("04"))
+			}
+			if page.NextCursor == "" {
+				return names
+			}
+			opts.Cursor = page.NextCursor
+		}
+	}
+
+	tests := []struct {
+		opts alertstore.ListOptions
+		want string
+	}{
+		{alertstore.ListOptions{Limit: 4}, "[B02 A02 B01 A01 B00 A00]"},
+		{alertstore.ListOptions{Limit: 1, Order: alertstore.OrderAsc}, "[A00 B00 A01 B01 A02 B02]"},
+		{alertstore.ListOptions{Limit: 1, From: start.Add(time.Minute), To: start.Add(2 * time.Minute)}, "[B01 A01]"},
+		{alertstore.ListOptions{Limit: 1, From: start.Add(time.Minute), Order: alertstore.OrderAsc}, "[A01 B01 A02 B02]"},
+		// The alertname index is walked from the window and cursor on
+		{alertstore.ListOptions{Query: `{alertname="A"}`, Limit: 1, To: start.Add(2 * time.Minute)}, "[A01 A00]"},
+		{alertstore.ListOptions{Query: `{alertname="B"}`, Limit: 2, From: start.Add(time.Minute), Order: alertstore.OrderAsc}, "[B01 B02]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(walk(tt.opts)); got != tt.want {
+			t.Errorf("ListAlerts(%+v) = %s; want %s", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestBoltStoreImportAlerts(t *testing.T) {
+	store := newTestStore(t, filepath.Join(t.TempDir(), "alerts.db"), 3, 0)
+	saveAlert(t, store, "Current", "firing")
+
+	start := time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+	var entries []alertstore.AlertEntry
+	for i, alertname := range []string{"A", "B", "C"} {
+		entries = append(entries, alertstore.AlertEntry{
+			Alert:     alertstore.Alert{Labels: map[string]string{"alertname": alertname}},
+			Status:    "firing",
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	// The batch repeats an entry, the second import repeats all of them
+	imported, err := store.ImportAlerts(append(entries, entries[1]))
+	if err != nil || imported != 3 {
+		t.Fatalf("ImportAlerts = %d, %v; want 3", imported, err)
+	}
+	if imported, err := store.ImportAlerts(entries[1:]); err != nil || imported != 0 {
+		t.Errorf("second ImportAlerts = %d, %v; want no duplicates", imported, err)
+	}
+
+	// The oldest imported entry is beyond the size, the indexes hold the others
+	all, _ := store.GetAlerts("", 0)
+	if got := fmt.Sprint(alertnames(all)); got != "[Current C B]" {
+		t.Errorf("entries after import = %s; want [Current C B]", got)
+	}
+	if found, _ := store.GetAlerts(`{alertname="B"}`, 0); len(found) != 1 || !found[0].Timestamp.Equal(start.Add(time.Minute)) {
+		t.Errorf("alertname index = %+v; want B with its original timestamp", found)
+	}
+}
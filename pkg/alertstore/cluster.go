@@ -0,0 +1,37 @@
+package alertstore
+
+import "time"
+
+// ClusterStatus describes the cluster of a replicated store
+type ClusterStatus struct {
+	LocalNode string          `json:"localNode"`
+	Members   []ClusterMember `json:"members"`
+	// BroadcastQueue is the number of updates waiting to be gossiped
+	BroadcastQueue int `json:"broadcastQueue"`
+	// HealthScore is 0 while this node is healthy, it grows with missed probes
+	HealthScore int `json:"healthScore"`
+	// Alerts counts the entries of the local store by the node that created them
+	Alerts map[string]int `json:"alerts"`
+}
+
+// ClusterMember is a node of the cluster
+type ClusterMember struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	State   string `json:"state"` // alive, suspect, dead or left
+	Local   bool   `json:"local"`
+	// LastSeen is the time of the last successful probe, zero if the node was never probed
+	LastSeen time.Time `json:"lastSeen,omitzero"`
+	// RTTSeconds is the round trip time of the last successful probe
+	RTTSeconds float64 `json:"rttSeconds,omitempty"`
+	Version    string  `json:"version,omitempty"`
+	Commit     string  `json:"commit,omitempty"`
+	// Alerts is the number of entries created by the node in the local store
+	Alerts int `json:"alerts"`
+}
+
+// ClusterReporter is implemented by stores that replicate between nodes
+type ClusterReporter interface {
+	// ClusterStatus returns the current membership as seen by this node
+	ClusterStatus() ClusterStatus
+}
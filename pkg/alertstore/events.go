@@ -0,0 +1,66 @@
+package alertstore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/OpenFero/openfero/pkg/metadata"
+)
+
+// Event types
+const (
+	EventAlert = "alert" // A new entry was saved
+	EventJob   = "job"   // The job information of an entry changed
+)
+
+// subscriberBuffer is the number of events a subscriber may fall behind before events are dropped
+const subscriberBuffer = 64
+
+// Event is a change of the store
+type Event struct {
+	Type  string     `json:"type"`
+	Entry AlertEntry `json:"entry"`
+}
+
+// Feed hands the events of a store to its subscribers. The zero value is ready to use.
+type Feed struct {
+	mutex       sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+// Subscribe returns a channel that receives the events published until ctx is done.
+// The channel is closed afterwards.
+func (f *Feed) Subscribe(ctx context.Context) <-chan Event {
+	ch := make(chan Event, subscriberBuffer)
+	f.mutex.Lock()
+	if f.subscribers == nil {
+		f.subscribers = make(map[chan Event]struct{})
+	}
+	f.subscribers[ch] = struct{}{}
+	f.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		f.mutex.Lock()
+		delete(f.subscribers, ch)
+		f.mutex.Unlock()
+		close(ch)
+	}()
+	return ch
+}
+
+// Publish hands events to all subscribers without blocking. Events for subscribers
+// that fell behind are dropped and counted in the dropped events metric.
+func (f *Feed) Publish(events ...Event) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for ch := range f.subscribers {
+		for _, event := range events {
+			select {
+			case ch <- event:
+			default:
+				metadata.AlertStoreEventsDroppedTotal.Inc()
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package alertstore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFeed(t *testing.T) {
+	var feed Feed
+	ctx, cancel := context.WithCancel(context.Background())
+	events := feed.Subscribe(ctx)
+	other := feed.Subscribe(context.Background())
+
+	feed.Publish(Event{Type: EventAlert, Entry: AlertEntry{Status: "firing"}}, Event{Type: EventJob})
+	if event := <-events; event.Type != EventAlert || event.Entry.Status != "firing" {
+		t.Errorf("first event = %+v; want the alert", event)
+	}
+	if event := <-events; event.Type != EventJob {
+		t.Errorf("second event = %+v; want the job update", event)
+	}
+	if event := <-other; event.Type != EventAlert {
+		t.Errorf("other subscriber got %+v; want the alert", event)
+	}
+
+	// The channel is closed once the context is done
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("received an event after the subscription ended")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed")
+	}
+}
+
+func TestFeedDropsEventsOfSlowSubscribers(t *testing.T) {
+	var feed Feed
+	events := feed.Subscribe(context.Background())
+	for i := 0; i < subscriberBuffer+10; i++ {
+		feed.Publish(Event{Type: EventAlert})
+	}
+	if len(events) != subscriberBuffer {
+		t.Errorf("subscriber holds %d events; want the %d buffered ones", len(events), subscriberBuffer)
+	}
+}
@@ -0,0 +1,142 @@
+package alertstore
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Incident is one episode of an alert from its first firing notification to its resolution
+type Incident struct {
+	// ID is the incident key and the start time in Unix seconds
+	ID string `json:"id"`
+	// Key is the fingerprint of the alert, or a hash of its labels
+	Key         string            `json:"key"`
+	Alertname   string            `json:"alertname"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"` // Annotations of the latest notification
+	Status      string            `json:"status"`                // firing or resolved
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt,omitzero"` // Zero while firing
+	// DurationSeconds is the time from start to end, or to now while firing
+	DurationSeconds int64 `json:"durationSeconds"`
+	// Timeline holds the notifications of the incident oldest first
+	Timeline []AlertEntry `json:"timeline"`
+	// Jobs are the jobs the notifications triggered
+	Jobs []IncidentJob `json:"jobs,omitempty"`
+}
+
+// IncidentJob is a job triggered during an incident with its outcome
+type IncidentJob struct {
+	Time    time.Time `json:"time"`
+	State   string    `json:"state"` // One of the job states, see JobState
+	JobInfo *JobInfo  `json:"jobInfo"`
+}
+
+// Duration returns the duration of the incident
+func (i Incident) Duration() time.Duration {
+	return time.Duration(i.DurationSeconds) * time.Second
+}
+
+// IncidentKey returns the fingerprint of an alert, or the fingerprint of its labels if it has none.
+// Entries stored before alerts got a fingerprint then share the key of later entries.
+func IncidentKey(alert Alert) string {
+	if alert.Fingerprint != "" {
+		return alert.Fingerprint
+	}
+	return LabelsFingerprint(alert.Labels)
+}
+
+// LabelsFingerprint returns a stable hash of the sorted labels of an alert
+func LabelsFingerprint(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := fnv.New64a()
+	for _, name := range names {
+		// The separators can not appear in valid label names
+		hash.Write([]byte(name))
+		hash.Write([]byte{0xff})
+		hash.Write([]byte(labels[name]))
+		hash.Write([]byte{0xff})
+	}
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// GroupIncidents correlates entries by IncidentKey into incidents, newest first. A firing notification
+// after the resolution, or with another startsAt, starts a new incident. Ongoing incidents last until now.
+func GroupIncidents(entries []AlertEntry, now time.Time) []Incident {
+	sorted := append([]AlertEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	var incidents []*Incident
+	current := make(map[string]*Incident)
+	for _, entry := range sorted {
+		key := IncidentKey(entry.Alert)
+		incident := current[key]
+		if incident == nil || startsNewIncident(incident, entry) {
+			incident = &Incident{
+				Key:       key,
+				Alertname: entry.Alert.Labels["alertname"],
+				Labels:    entry.Alert.Labels,
+				Status:    "firing",
+				StartsAt:  entry.Timestamp,
+			}
+			if startsAt, err := time.Parse(time.RFC3339, entry.Alert.StartsAt); err == nil && !startsAt.IsZero() {
+				incident.StartsAt = startsAt
+			}
+			incident.ID = fmt.Sprintf("%s-%d", key, incident.StartsAt.Unix())
+			incidents = append(incidents, incident)
+			current[key] = incident
+		}
+
+		incident.Timeline = append(incident.Timeline, entry)
+		if len(entry.Alert.Annotations) > 0 {
+			incident.Annotations = entry.Alert.Annotations
+		}
+		if entry.JobInfo != nil {
+			incident.Jobs = append(incident.Jobs, IncidentJob{Time: entry.Timestamp, State: JobState(entry.JobInfo), JobInfo: entry.JobInfo})
+		}
+		if isResolved(entry) {
+			incident.Status = "resolved"
+			incident.EndsAt = entry.Timestamp
+			if endsAt, err := time.Parse(time.RFC3339, entry.Alert.EndsAt); err == nil && !endsAt.IsZero() {
+				incident.EndsAt = endsAt
+			}
+		}
+	}
+
+	result := make([]Incident, 0, len(incidents))
+	for _, incident := range incidents {
+		end := incident.EndsAt
+		if end.IsZero() {
+			end = now
+		}
+		incident.DurationSeconds = int64(end.Sub(incident.StartsAt) / time.Second)
+		result = append(result, *incident)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].StartsAt.After(result[j].StartsAt) })
+	return result
+}
+
+// startsNewIncident reports whether an entry belongs to a new episode of the alert of the incident
+func startsNewIncident(incident *Incident, entry AlertEntry) bool {
+	if isResolved(entry) {
+		// A repeated resolution stays with the incident it resolved
+		return false
+	}
+	if incident.Status == "resolved" {
+		return true
+	}
+	last := incident.Timeline[len(incident.Timeline)-1].Alert.StartsAt
+	return last != "" && entry.Alert.StartsAt != "" && last != entry.Alert.StartsAt
+}
+
+func isResolved(entry AlertEntry) bool {
+	return strings.EqualFold(entry.Status, "resolved")
+}
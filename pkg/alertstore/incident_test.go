@@ -0,0 +1,87 @@
+package alertstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupIncidents(t *testing.T) {
+	start := time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+	now := start.Add(3 * time.Hour)
+	disk := Alert{Labels: map[string]string{"alertname": "DiskFull"}, Fingerprint: "d1", StartsAt: "2025-06-01T01:59:00Z"}
+	secondEpisode := Alert{Labels: map[string]string{"alertname": "DiskFull"}, Fingerprint: "d1", StartsAt: "2025-06-01T02:30:00Z"}
+	quota := Alert{Labels: map[string]string{"alertname": "KubeQuotaExceeded", "namespace": "payments"}}
+
+	entries := []AlertEntry{
+		{Alert: disk, Status: "firing", Timestamp: start, JobInfo: &JobInfo{JobName: "cleanup-1"}},
+		{Alert: quota, Status: "firing", Timestamp: start.Add(5 * time.Minute), JobInfo: &JobInfo{FailureReason: "Forbidden"}},
+		{Alert: disk, Status: "firing", Timestamp: start.Add(10 * time.Minute)},
+		{Alert: disk, Status: "resolved", Timestamp: start.Add(20 * time.Minute)},
+		{Alert: secondEpisode, Status: "firing", Timestamp: start.Add(30 * time.Minute), JobInfo: &JobInfo{JobName: "cleanup-2", DryRun: true}},
+	}
+	// Stores return entries newest first
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	incidents := GroupIncidents(entries, now)
+	if len(incidents) != 3 {
+		t.Fatalf("got %d incidents; want 3: %+v", len(incidents), incidents)
+	}
+
+	second, quotaIncident, first := incidents[0], incidents[1], incidents[2]
+	if first.ID != "d1-1748743140" || first.Status != "resolved" || len(first.Timeline) != 3 {
+		t.Errorf("first incident = %s %s with %d entries; want d1-1748743140 resolved with 3 entries", first.ID, first.Status, len(first.Timeline))
+	}
+	if first.Duration() != 21*time.Minute {
+		t.Errorf("first incident lasted %s; want 21m from startsAt to the resolution", first.Duration())
+	}
+	if len(first.Jobs) != 1 || first.Jobs[0].State != JobStateCreated || first.Jobs[0].JobInfo.JobName != "cleanup-1" {
+		t.Errorf("first incident jobs = %+v; want cleanup-1 created", first.Jobs)
+	}
+
+	// A new startsAt starts a new incident, which lasts until now while firing
+	if second.Status != "firing" || !second.EndsAt.IsZero() || second.Duration() != now.Sub(start.Add(30*time.Minute)) {
+		t.Errorf("second incident = %s ending %s after %s; want firing until now", second.Status, second.EndsAt, second.Duration())
+	}
+	if len(second.Jobs) != 1 || second.Jobs[0].State != JobStateDryRun {
+		t.Errorf("second incident jobs = %+v; want a dry-run", second.Jobs)
+	}
+
+	// Without a fingerprint the labels are hashed
+	if quotaIncident.Key != IncidentKey(quota) || len(quotaIncident.Key) != 16 || quotaIncident.Jobs[0].State != JobStateFailed {
+		t.Errorf("quota incident = %+v; want a label hash key and a failed job", quotaIncident)
+	}
+	if !quotaIncident.StartsAt.Equal(start.Add(5 * time.Minute)) {
+		t.Errorf("quota incident started %s; want the time of its first entry", quotaIncident.StartsAt)
+	}
+}
+
+func TestIncidentKeyIsStable(t *testing.T) {
+	a := Alert{Labels: map[string]string{"alertname": "A", "severity": "critical", "namespace": "prod"}}
+	b := Alert{Labels: map[string]string{"namespace": "prod", "severity": "critical", "alertname": "A"}}
+	c := Alert{Labels: map[string]string{"alertname": "A", "severity": "warning", "namespace": "prod"}}
+	if IncidentKey(a) != IncidentKey(b) {
+		t.Error("the key depends on the order of the labels")
+	}
+	if IncidentKey(a) == IncidentKey(c) {
+		t.Error("alerts with different labels share a key")
+	}
+	// Entries stored without a fingerprint share the key of entries with the computed one
+	if stored := (Alert{Labels: a.Labels, Fingerprint: LabelsFingerprint(a.Labels)}); IncidentKey(a) != IncidentKey(stored) {
+		t.Error("an entry without fingerprint splits from the entries with one")
+	}
+}
+
+func TestGroupIncidentsResolvedFirst(t *testing.T) {
+	// The firing entries were evicted, the resolution still forms an incident
+	now := time.Now()
+	alert := Alert{Labels: map[string]string{"alertname": "A"}, Fingerprint: "a1"}
+	incidents := GroupIncidents([]AlertEntry{
+		{Alert: alert, Status: "resolved", Timestamp: now.Add(-time.Minute)},
+		{Alert: alert, Status: "firing", Timestamp: now},
+	}, now)
+	if len(incidents) != 2 || incidents[1].Status != "resolved" || incidents[0].Status != "firing" {
+		t.Errorf("incidents = %+v; want a resolved and a new firing incident", incidents)
+	}
+}
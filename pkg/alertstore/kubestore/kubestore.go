@@ -0,0 +1,600 @@
+package kubestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/utils"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// HistoryLabel marks the ConfigMap shards that hold the alert history
+	HistoryLabel = "openfero.io/alert-history"
+
+	// shardPrefix is the name prefix of a shard, followed by its creation time and a random suffix
+	shardPrefix = "openfero-history-"
+
+	// defaultShardEntries is the number of entries after which a new shard is started
+	defaultShardEntries = 100
+
+	// shardMaxBytes keeps a shard well below the 1 MiB limit of a ConfigMap
+	shardMaxBytes = 768 << 10
+
+	// gcInterval is how often entries beyond the count or age limit are deleted
+	gcInterval = 5 * time.Minute
+)
+
+// KubernetesStore implements alertstore.Store with rolling ConfigMap shards in the OpenFero namespace.
+// Reads are served from an informer, writes go to the newest shard until it is full.
+type KubernetesStore struct {
+	clientset    kubernetes.Interface
+	namespace    string
+	policy       alertstore.RetentionPolicy
+	shardEntries int
+	now          func() time.Time
+
+	shards cache.Store
+	stop   chan struct{}
+	wg     sync.WaitGroup
+
+	// writeMutex serializes the writes of this replica, other replicas are handled with optimistic concurrency
+	writeMutex sync.Mutex
+	// active is the shard this replica writes to
+	active string
+
+	// decoded caches the entries of each shard object of the informer
+	decodedMutex sync.Mutex
+	decoded      map[string]decodedShard
+
+	feed alertstore.Feed
+}
+
+// decodedShard holds the decoded entries of one version of a shard. The informer
+// replaces the object on every change, so the pointer identifies the version.
+type decodedShard struct {
+	object  *corev1.ConfigMap
+	entries []keyedEntry
+}
+
+// keyedEntry is an entry with its shard and data key
+type keyedEntry struct {
+	shard string
+	key   string
+	entry alertstore.AlertEntry
+}
+
+// NewKubernetesStore creates a new alert store in the given namespace that deletes
+// the entries the retention policy evicts.
+func NewKubernetesStore(clientset kubernetes.Interface, namespace string, policy alertstore.RetentionPolicy) *KubernetesStore {
+	return &KubernetesStore{
+		clientset:    clientset,
+		namespace:    namespace,
+		policy:       policy,
+		shardEntries: defaultShardEntries,
+		now:          time.Now,
+		decoded:      make(map[string]decodedShard),
+	}
+}
+
+// Initialize starts the shard informer and the garbage collection
+func (s *KubernetesStore) Initialize() error {
+	s.stop = make(chan struct{})
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		s.clientset,
+		time.Hour*1,
+		informers.WithNamespace(s.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = HistoryLabel + "=true"
+		}),
+	)
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if cm, ok := obj.(*corev1.ConfigMap); ok && !isInInitialList {
+				s.publishChanges(nil, cm)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCM, oldOK := oldObj.(*corev1.ConfigMap)
+			newCM, newOK := newObj.(*corev1.ConfigMap)
+			if oldOK && newOK {
+				s.publishChanges(oldCM, newCM)
+			}
+		},
+		DeleteFunc: s.forgetShard,
+	}); err != nil {
+		return fmt.Errorf("failed to add alert history event handler: %w", err)
+	}
+
+	factory.Start(s.stop)
+	if !cache.WaitForCacheSync(s.stop, informer.HasSynced) {
+		return fmt.Errorf("failed to sync alert history cache in namespace %s", s.namespace)
+	}
+	s.shards = informer.GetStore()
+
+	s.wg.Add(1)
+	go s.gcLoop()
+
+	log.Info("Kubernetes alert store initialized",
+		zap.String("namespace", s.namespace),
+		zap.Int("shards", len(s.shards.List())),
+		zap.Int("maxSize", s.policy.MaxEntries),
+		zap.Int("alertnameQuota", s.policy.MaxPerAlertname),
+		zap.Duration("retention", s.policy.MaxAge))
+	return nil
+}
+
+// Close stops the informer and the garbage collection
+func (s *KubernetesStore) Close() error {
+	if s.stop != nil {
+		close(s.stop)
+		s.wg.Wait()
+		s.stop = nil
+	}
+	return nil
+}
+
+// SaveAlert saves an alert to the store
+func (s *KubernetesStore) SaveAlert(alert alertstore.Alert, status string) error {
+	return s.SaveAlertWithJobInfo(alert, status, nil)
+}
+
+// SaveAlertWithJobInfo adds an alert with job information to the active shard
+func (s *KubernetesStore) SaveAlertWithJobInfo(alert alertstore.Alert, status string, jobInfo *alertstore.JobInfo) error {
+	entry := alertstore.AlertEntry{
+		Alert:     alert,
+		Status:    status,
+		Timestamp: s.now(),
+		JobInfo:   jobInfo,
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	key := entryKey(entry.Timestamp)
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		shard, err := s.activeShard(len(data))
+		if err != nil {
+			return err
+		}
+		if shard == nil {
+			return s.createShard(map[string]string{key: string(data)})
+		}
+		shard = shard.DeepCopy()
+		if shard.Data == nil {
+			shard.Data = make(map[string]string)
+		}
+		shard.Data[key] = string(data)
+		_, err = s.clientset.CoreV1().ConfigMaps(s.namespace).Update(context.TODO(), shard, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// ImportAlerts adds entries with their timestamps, skipping entries the store already holds.
+// Duplicates are detected in the informer cache, entries written by other replicas at the
+// same time may be imported twice.
+func (s *KubernetesStore) ImportAlerts(entries []alertstore.AlertEntry) (int, error) {
+	existing := make(map[string]bool)
+	for _, cached := range s.entries() {
+		existing[alertstore.EntryKey(cached.entry)] = true
+	}
+	fresh := alertstore.NewEntries(entries, existing)
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	for written := 0; written < len(fresh); {
+		n, err := s.writeBatch(fresh[written:])
+		if err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return len(fresh), nil
+}
+
+// writeBatch writes as many entries as fit into the active shard, or into a new one,
+// with a single request and returns their number. The caller must hold the write mutex.
+func (s *KubernetesStore) writeBatch(entries []alertstore.AlertEntry) (int, error) {
+	keys := make([]string, len(entries))
+	values := make([]string, len(entries))
+	for i, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal alert: %w", err)
+		}
+		keys[i], values[i] = entryKey(entry.Timestamp), string(data)
+	}
+
+	var written int
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		shard, err := s.activeShard(len(keys[0]) + len(values[0]))
+		if err != nil {
+			return err
+		}
+		data := make(map[string]string)
+		if shard != nil {
+			shard = shard.DeepCopy()
+			if shard.Data != nil {
+				data = shard.Data
+			}
+		}
+		used := 0
+		for key, value := range data {
+			used += len(key) + len(value)
+		}
+
+		written = 0
+		for i := range keys {
+			size := len(keys[i]) + len(values[i])
+			if written > 0 && (len(data) >= s.shardEntries || used+size > shardMaxBytes) {
+				break
+			}
+			data[keys[i]] = values[i]
+			used += size
+			written++
+		}
+
+		if shard == nil {
+			return s.createShard(data)
+		}
+		shard.Data = data
+		_, err = s.clientset.CoreV1().ConfigMaps(s.namespace).Update(context.TODO(), shard, metav1.UpdateOptions{})
+		return err
+	})
+	return written, err
+}
+
+// activeShard returns the current version of the shard to write to, or nil if a new shard must be started
+func (s *KubernetesStore) activeShard(size int) (*corev1.ConfigMap, error) {
+	name := s.active
+	if name == "" {
+		name = s.newestShard()
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	// The informer may lag behind, writes always start from the current version
+	shard, err := s.clientset.CoreV1().ConfigMaps(s.namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		s.active = ""
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	used := 0
+	for key, value := range shard.Data {
+		used += len(key) + len(value)
+	}
+	if len(shard.Data) >= s.shardEntries || used+size > shardMaxBytes {
+		return nil, nil
+	}
+	s.active = name
+	return shard, nil
+}
+
+// createShard starts a new shard with the given entries
+func (s *KubernetesStore) createShard(data map[string]string) error {
+	shard := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      shardPrefix + strconv.FormatInt(s.now().Unix(), 10) + "-" + utils.StringWithCharset(5, utils.Charset),
+			Namespace: s.namespace,
+			Labels: map[string]string{
+				HistoryLabel:                  "true",
+				"app.kubernetes.io/component": "alert-history",
+				"app.kubernetes.io/part-of":   "openfero",
+			},
+		},
+		Data: data,
+	}
+	created, err := s.clientset.CoreV1().ConfigMaps(s.namespace).Create(context.TODO(), shard, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create alert history shard: %w", err)
+	}
+	log.Debug("Started new alert history shard", zap.String("shard", created.Name))
+	s.active = created.Name
+	return nil
+}
+
+// newestShard returns the name of the newest shard in the informer cache
+func (s *KubernetesStore) newestShard() string {
+	newest := ""
+	for _, name := range s.shards.ListKeys() {
+		_, name, _ = cache.SplitMetaNamespaceKey(name)
+		// Names contain the creation time with a fixed number of digits
+		if name > newest {
+			newest = name
+		}
+	}
+	return newest
+}
+
+// UpdateJobInfo updates the job information of the newest entry that triggered jobName
+func (s *KubernetesStore) UpdateJobInfo(jobName string, update func(*alertstore.JobInfo)) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	shardName, key := s.findJob(jobName)
+	if shardName == "" {
+		return alertstore.ErrNotFound
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		shard, err := s.clientset.CoreV1().ConfigMaps(s.namespace).Get(context.TODO(), shardName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return alertstore.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		data, ok := shard.Data[key]
+		if !ok {
+			return alertstore.ErrNotFound
+		}
+
+		var entry alertstore.AlertEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			return fmt.Errorf("failed to unmarshal alert: %w", err)
+		}
+		if entry.JobInfo == nil {
+			entry.JobInfo = &alertstore.JobInfo{JobName: jobName}
+		}
+		update(entry.JobInfo)
+
+		updated, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert: %w", err)
+		}
+		shard.Data[key] = string(updated)
+		_, err = s.clientset.CoreV1().ConfigMaps(s.namespace).Update(context.TODO(), shard, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// findJob returns the shard and key of the newest entry of a job. An entry written a moment ago
+// may not be in the informer cache yet, so the active shard is read from the API as a fallback.
+func (s *KubernetesStore) findJob(jobName string) (string, string) {
+	for _, e := range s.entries() {
+		if e.entry.JobInfo != nil && e.entry.JobInfo.JobName == jobName {
+			return e.shard, e.key
+		}
+	}
+	if s.active == "" {
+		return "", ""
+	}
+	shard, err := s.clientset.CoreV1().ConfigMaps(s.namespace).Get(context.TODO(), s.active, metav1.GetOptions{})
+	if err != nil {
+		return "", ""
+	}
+	for _, e := range decodeShard(shard) {
+		if e.entry.JobInfo != nil && e.entry.JobInfo.JobName == jobName {
+			return e.shard, e.key
+		}
+	}
+	return "", ""
+}
+
+// Subscribe returns the entries saved and updated by any replica until ctx is done
+func (s *KubernetesStore) Subscribe(ctx context.Context) <-chan alertstore.Event {
+	return s.feed.Subscribe(ctx)
+}
+
+// forgetShard drops the decoded entries of a deleted shard. Shards deleted while the watch
+// was down arrive as tombstones.
+func (s *KubernetesStore) forgetShard(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	s.decodedMutex.Lock()
+	delete(s.decoded, cm.Name)
+	s.decodedMutex.Unlock()
+}
+
+// publishChanges publishes the entries added to or changed in a shard, oldest first.
+// Entries of a new shard have no previous version.
+func (s *KubernetesStore) publishChanges(previous, shard *corev1.ConfigMap) {
+	keys := make([]string, 0, len(shard.Data))
+	for key, data := range shard.Data {
+		if previous == nil || previous.Data[key] != data {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	events := make([]alertstore.Event, 0, len(keys))
+	for _, key := range keys {
+		var entry alertstore.AlertEntry
+		if err := json.Unmarshal([]byte(shard.Data[key]), &entry); err != nil {
+			continue
+		}
+		eventType := alertstore.EventAlert
+		if previous != nil {
+			if _, ok := previous.Data[key]; ok {
+				eventType = alertstore.EventJob
+			}
+		}
+		events = append(events, alertstore.Event{Type: eventType, Entry: entry})
+	}
+	s.feed.Publish(events...)
+}
+
+// GetAlerts retrieves alerts newest first from the informer cache, optionally filtered by query
+func (s *KubernetesStore) GetAlerts(query string, limit int) ([]alertstore.AlertEntry, error) {
+	page, err := s.ListAlerts(alertstore.ListOptions{Query: query, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return page.Alerts, nil
+}
+
+// ListAlerts retrieves a page of alerts from the informer cache. The data key orders entries with equal timestamps.
+func (s *KubernetesStore) ListAlerts(opts alertstore.ListOptions) (*alertstore.Page, error) {
+	list, err := alertstore.NewList(opts, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := s.entries()
+	items := make([]alertstore.Item, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, alertstore.Item{
+			Position: alertstore.Position{Time: e.entry.Timestamp, ID: e.key},
+			Entry:    e.entry,
+		})
+	}
+	list.AddAll(items)
+	return list.Page(), nil
+}
+
+// entries returns all cached entries newest first
+func (s *KubernetesStore) entries() []keyedEntry {
+	s.decodedMutex.Lock()
+	defer s.decodedMutex.Unlock()
+
+	var all []keyedEntry
+	for _, obj := range s.shards.List() {
+		shard, ok := obj.(*corev1.ConfigMap)
+		if !ok {
+			continue
+		}
+		decoded, ok := s.decoded[shard.Name]
+		if !ok || decoded.object != shard {
+			decoded = decodedShard{object: shard, entries: decodeShard(shard)}
+			s.decoded[shard.Name] = decoded
+		}
+		all = append(all, decoded.entries...)
+	}
+
+	// Keys start with the zero-padded time, so they order like the entries
+	sort.Slice(all, func(i, j int) bool { return all[i].key > all[j].key })
+	return all
+}
+
+// gcLoop runs collectGarbage periodically until the store is closed
+func (s *KubernetesStore) gcLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(gcInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if deleted, err := s.collectGarbage(); err != nil {
+				log.Error("Failed to garbage collect alert history", zap.Error(err))
+			} else if deleted > 0 {
+				log.Info("Garbage collected alert history", zap.Int("deleted", deleted))
+			}
+		}
+	}
+}
+
+// collectGarbage deletes the entries the retention policy evicts. Shards that become
+// empty are deleted. Every replica may run it, deleting is idempotent.
+func (s *KubernetesStore) collectGarbage() (int, error) {
+	all := s.entries()
+	entries := make([]alertstore.AlertEntry, len(all))
+	for i, e := range all {
+		entries[i] = e.entry
+	}
+	evict, deleted := s.policy.Evict(entries, s.now())
+
+	expired := make(map[string][]string)
+	for i, e := range all {
+		if evict[i] {
+			expired[e.shard] = append(expired[e.shard], e.key)
+		}
+	}
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	for shardName, keys := range expired {
+		if err := s.removeEntries(shardName, keys); err != nil {
+			return 0, err
+		}
+	}
+	return deleted, nil
+}
+
+// removeEntries deletes keys from a shard, and the shard itself once it is empty
+func (s *KubernetesStore) removeEntries(shardName string, keys []string) error {
+	configMaps := s.clientset.CoreV1().ConfigMaps(s.namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		shard, err := configMaps.Get(context.TODO(), shardName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			delete(shard.Data, key)
+		}
+		if len(shard.Data) > 0 {
+			_, err = configMaps.Update(context.TODO(), shard, metav1.UpdateOptions{})
+			return err
+		}
+
+		// The precondition keeps entries another replica has just added
+		resourceVersion := shard.ResourceVersion
+		err = configMaps.Delete(context.TODO(), shardName, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion},
+		})
+		if err == nil && s.active == shardName {
+			s.active = ""
+		}
+		return err
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// decodeShard decodes the entries of a shard, unreadable entries are skipped
+func decodeShard(shard *corev1.ConfigMap) []keyedEntry {
+	entries := make([]keyedEntry, 0, len(shard.Data))
+	for key, data := range shard.Data {
+		var entry alertstore.AlertEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			log.Warn("Skipping unreadable alert history entry",
+				zap.String("shard", shard.Name),
+				zap.String("key", key),
+				zap.Error(err))
+			continue
+		}
+		entries = append(entries, keyedEntry{shard: shard.Name, key: key, entry: entry})
+	}
+	return entries
+}
+
+// entryKey builds a ConfigMap key that sorts by time and is unique across replicas
+func entryKey(t time.Time) string {
+	return fmt.Sprintf("%019d-%s", t.UnixNano(), utils.StringWithCharset(5, utils.Charset))
+}
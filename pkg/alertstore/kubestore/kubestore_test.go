@@ -0,0 +1,214 @@
+package kubestore
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestMain(m *testing.M) {
+	// Initialize logger before running tests
+	if err := log.SetConfig(zap.NewDevelopmentConfig()); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func newTestStore(t *testing.T, policy alertstore.RetentionPolicy) (*KubernetesStore, *fake.Clientset) {
+	t.Helper()
+	clientset := fake.NewClientset()
+	store := NewKubernetesStore(clientset, "openfero", policy)
+	store.shardEntries = 2
+	if err := store.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store, clientset
+}
+
+func saveAlert(t *testing.T, store *KubernetesStore, alertname string, jobInfo *alertstore.JobInfo) {
+	t.Helper()
+	if err := store.SaveAlertWithJobInfo(alertstore.Alert{Labels: map[string]string{"alertname": alertname}}, "firing", jobInfo); err != nil {
+		t.Fatalf("SaveAlert failed: %v", err)
+	}
+}
+
+// waitForAlerts waits until the informer cache holds want entries
+func waitForAlerts(t *testing.T, store *KubernetesStore, want int) []alertstore.AlertEntry {
+	t.Helper()
+	var entries []alertstore.AlertEntry
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		entries, _ = store.GetAlerts("", 0)
+		if len(entries) == want {
+			return entries
+		}
+	}
+	t.Fatalf("informer cache holds %d entries; want %d", len(entries), want)
+	return nil
+}
+
+func TestKubernetesStoreShards(t *testing.T) {
+	store, clientset := newTestStore(t, alertstore.RetentionPolicy{})
+	for _, alertname := range []string{"First", "Second", "Third"} {
+		saveAlert(t, store, alertname, nil)
+	}
+
+	shards, err := clientset.CoreV1().ConfigMaps("openfero").List(context.TODO(), metav1.ListOptions{LabelSelector: HistoryLabel + "=true"})
+	if err != nil || len(shards.Items) != 2 {
+		t.Fatalf("expected 2 shards, got %d (err %v)", len(shards.Items), err)
+	}
+
+	entries := waitForAlerts(t, store, 3)
+	if entries[0].Alert.Labels["alertname"] != "Third" || entries[2].Alert.Labels["alertname"] != "First" {
+		t.Errorf("entries are not ordered newest first: %+v", entries)
+	}
+	if entries, _ := store.GetAlerts("second", 0); len(entries) != 1 {
+		t.Errorf("GetAlerts(second) returned %d entries; want 1", len(entries))
+	}
+}
+
+func TestKubernetesStoreUpdateJobInfo(t *testing.T) {
+	store, _ := newTestStore(t, alertstore.RetentionPolicy{})
+	saveAlert(t, store, "DiskFull", &alertstore.JobInfo{JobName: "cleanup-abcde"})
+
+	// The entry is found through the active shard before the informer has seen it
+	err := store.UpdateJobInfo("cleanup-abcde", func(info *alertstore.JobInfo) { info.FailureReason = "Forbidden" })
+	if err != nil {
+		t.Fatalf("UpdateJobInfo failed: %v", err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		entries, _ := store.GetAlerts("", 0)
+		if len(entries) == 1 && entries[0].JobInfo.FailureReason == "Forbidden" {
+			break
+		}
+	}
+	entries, _ := store.GetAlerts("", 0)
+	if len(entries) != 1 || entries[0].JobInfo.FailureReason != "Forbidden" {
+		t.Errorf("GetAlerts = %+v; want the updated job info", entries)
+	}
+	if err := store.UpdateJobInfo("unknown", func(*alertstore.JobInfo) {}); err != alertstore.ErrNotFound {
+		t.Errorf("UpdateJobInfo of an unknown job = %v; want ErrNotFound", err)
+	}
+}
+
+func TestKubernetesStoreGarbageCollection(t *testing.T) {
+	store, clientset := newTestStore(t, alertstore.RetentionPolicy{MaxEntries: 2, MaxAge: time.Hour})
+	now := time.Now()
+
+	store.now = func() time.Time { return now.Add(-2 * time.Hour) }
+	saveAlert(t, store, "Old", nil)
+	for i, alertname := range []string{"First", "Second", "Third"} {
+		at := now.Add(time.Duration(i) * time.Second)
+		store.now = func() time.Time { return at }
+		saveAlert(t, store, alertname, nil)
+	}
+	waitForAlerts(t, store, 4)
+
+	// Old is expired, First is beyond the count, the shard of Old and First is deleted
+	deleted, err := store.collectGarbage()
+	if err != nil || deleted != 2 {
+		t.Fatalf("collectGarbage = (%d, %v); want (2, nil)", deleted, err)
+	}
+	entries := waitForAlerts(t, store, 2)
+	if entries[0].Alert.Labels["alertname"] != "Third" || entries[1].Alert.Labels["alertname"] != "Second" {
+		t.Errorf("entries after garbage collection = %+v; want Third and Second", entries)
+	}
+	shards, _ := clientset.CoreV1().ConfigMaps("openfero").List(context.TODO(), metav1.ListOptions{})
+	if len(shards.Items) != 1 {
+		t.Errorf("expected the empty shard to be deleted, %d shards left", len(shards.Items))
+	}
+}
+
+func TestKubernetesStoreForgetsTombstones(t *testing.T) {
+	store, _ := newTestStore(t, alertstore.RetentionPolicy{})
+	saveAlert(t, store, "DiskFull", nil)
+	waitForAlerts(t, store, 1)
+
+	store.decodedMutex.Lock()
+	var name string
+	for name = range store.decoded {
+	}
+	store.decodedMutex.Unlock()
+	if name == "" {
+		t.Fatal("no decoded shard")
+	}
+
+	// A shard deleted while the watch was down is delivered as a tombstone
+	shard := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openfero"}}
+	store.forgetShard(cache.DeletedFinalStateUnknown{Key: "openfero/" + name, Obj: shard})
+
+	store.decodedMutex.Lock()
+	defer store.decodedMutex.Unlock()
+	if _, ok := store.decoded[name]; ok {
+		t.Errorf("decoded entries of shard %s kept after its tombstone", name)
+	}
+}
+
+func TestKubernetesStoreImportAlerts(t *testing.T) {
+	store, clientset := newTestStore(t, alertstore.RetentionPolicy{})
+	saveAlert(t, store, "Current", nil)
+	waitForAlerts(t, store, 1)
+
+	start := time.Now().Add(-time.Hour)
+	var entries []alertstore.AlertEntry
+	for i, alertname := range []string{"A", "B", "C"} {
+		entries = append(entries, alertstore.AlertEntry{
+			Alert:     alertstore.Alert{Labels: map[string]string{"alertname": alertname}},
+			Status:    "firing",
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	imported, err := store.ImportAlerts(entries)
+	if err != nil || imported != 3 {
+		t.Fatalf("ImportAlerts = %d, %v; want 3", imported, err)
+	}
+
+	// The active shard is filled up before new shards of two entries are created
+	shards, err := clientset.CoreV1().ConfigMaps("openfero").List(context.TODO(), metav1.ListOptions{LabelSelector: HistoryLabel + "=true"})
+	if err != nil || len(shards.Items) != 2 {
+		t.Fatalf("expected 2 shards, got %d (err %v)", len(shards.Items), err)
+	}
+	all := waitForAlerts(t, store, 4)
+	if all[0].Alert.Labels["alertname"] != "Current" || all[3].Alert.Labels["alertname"] != "A" {
+		t.Errorf("entries are not ordered by their timestamps: %+v", all)
+	}
+
+	if imported, err := store.ImportAlerts(entries); err != nil || imported != 0 {
+		t.Errorf("second ImportAlerts = %d, %v; want no duplicates", imported, err)
+	}
+}
+
+func TestKubernetesStoreSubscribe(t *testing.T) {
+	store, _ := newTestStore(t, alertstore.RetentionPolicy{})
+	saveAlert(t, store, "Before", nil)
+	waitForAlerts(t, store, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := store.Subscribe(ctx)
+	saveAlert(t, store, "DiskFull", &alertstore.JobInfo{JobName: "cleanup-abcde"})
+	if err := store.UpdateJobInfo("cleanup-abcde", func(info *alertstore.JobInfo) { info.FailureReason = "Forbidden" }); err != nil {
+		t.Fatalf("UpdateJobInfo failed: %v", err)
+	}
+
+	// Events come from the informer, so writes of other replicas are streamed as well
+	for _, want := range []string{alertstore.EventAlert, alertstore.EventJob} {
+		select {
+		case event := <-events:
+			if event.Type != want || event.Entry.Alert.Labels["alertname"] != "DiskFull" {
+				t.Errorf("event = %s %+v; want %s of DiskFull", event.Type, event.Entry, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no %s event", want)
+		}
+	}
+}
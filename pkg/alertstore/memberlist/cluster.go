@@ -0,0 +1,144 @@
+package memberlist
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/metadata"
+	"github.com/hashicorp/memberlist"
+	"go.uber.org/zap"
+)
+
+// NodeMeta is gossiped with the membership of every node
+type NodeMeta struct {
+	Version string `json:"version,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+}
+
+// probe is the last successful probe of a node
+type probe struct {
+	time time.Time
+	rtt  time.Duration
+}
+
+// probes records the last successful probe of every node
+type probes struct {
+	mutex sync.Mutex
+	nodes map[string]probe
+}
+
+func (p *probes) record(node string, rtt time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.nodes == nil {
+		p.nodes = make(map[string]probe)
+	}
+	p.nodes[node] = probe{time: time.Now(), rtt: rtt}
+}
+
+func (p *probes) get(node string) probe {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.nodes[node]
+}
+
+// NodeMeta is used to retrieve meta-data about the current node
+func (d *delegate) NodeMeta(limit int) []byte {
+	data, err := json.Marshal(d.store.Meta)
+	if err != nil || len(data) > limit {
+		log.Warn("Node metadata does not fit into memberlist", zap.Int("limit", limit), zap.Error(err))
+		return []byte{}
+	}
+	return data
+}
+
+// AckPayload is invoked when an ack is being sent
+func (d *delegate) AckPayload() []byte {
+	return []byte{}
+}
+
+// NotifyPingComplete is invoked when another node answered a probe
+func (d *delegate) NotifyPingComplete(other *memberlist.Node, rtt time.Duration, payload []byte) {
+	d.probes.record(other.Name, rtt)
+	metadata.MemberlistProbeRTTSeconds.Observe(rtt.Seconds())
+	if d.store.ml != nil {
+		metadata.MemberlistHealthScore.Set(float64(d.store.ml.GetHealthScore()))
+	}
+	if d.broadcasts != nil {
+		metadata.MemberlistBroadcastQueueLength.Set(float64(d.broadcasts.NumQueued()))
+	}
+}
+
+// ClusterStatus returns the members of the cluster with their metadata and alert counts
+func (s *MemberlistStore) ClusterStatus() alertstore.ClusterStatus {
+	status := alertstore.ClusterStatus{LocalNode: s.node, Alerts: make(map[string]int)}
+
+	s.mutex.RLock()
+	for _, entry := range s.alerts {
+		status.Alerts[originNode(entry.ID)]++
+	}
+	s.mutex.RUnlock()
+
+	if s.ml == nil {
+		return status
+	}
+	status.HealthScore = s.ml.GetHealthScore()
+	status.BroadcastQueue = s.broadcasts.NumQueued()
+
+	for _, node := range s.ml.Members() {
+		member := alertstore.ClusterMember{
+			Name:    node.Name,
+			Address: node.Address(),
+			State:   stateName(node.State),
+			Local:   node.Name == s.node,
+			Alerts:  status.Alerts[node.Name],
+		}
+		var meta NodeMeta
+		if len(node.Meta) > 0 && json.Unmarshal(node.Meta, &meta) == nil {
+			member.Version, member.Commit = meta.Version, meta.Commit
+		}
+		if member.Local {
+			member.LastSeen = time.Now()
+		} else if last := s.delegate.probes.get(node.Name); !last.time.IsZero() {
+			member.LastSeen = last.time
+			member.RTTSeconds = last.rtt.Seconds()
+		}
+		status.Members = append(status.Members, member)
+	}
+	sort.Slice(status.Members, func(i, j int) bool { return status.Members[i].Name < status.Members[j].Name })
+	return status
+}
+
+// originNode returns the node that created an entry from its ID
+func originNode(id string) string {
+	if strings.HasPrefix(id, "legacy-") {
+		return "legacy"
+	}
+	// Strip the incarnation and the sequence number, node names may contain dashes
+	for i := 0; i < 2; i++ {
+		if cut := strings.LastIndexByte(id, '-'); cut > 0 {
+			id = id[:cut]
+		}
+	}
+	return id
+}
+
+func stateName(state memberlist.NodeStateType) string {
+	switch state {
+	case memberlist.StateAlive:
+		return "alive"
+	case memberlist.StateSuspect:
+		return "suspect"
+	case memberlist.StateDead:
+		return "dead"
+	case memberlist.StateLeft:
+		return "left"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,38 @@
+package memberlist
+
+import (
+	"testing"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+)
+
+func TestOriginNode(t *testing.T) {
+	for id, want := range map[string]string{
+		"openfero-7d9f-x2k4p-dm83hut8705x-12": "openfero-7d9f-x2k4p",
+		"node-a-dm83hut8705x-1":               "node-a",
+		"legacy-1748743140000000000-DiskFull": "legacy",
+	} {
+		if got := originNode(id); got != want {
+			t.Errorf("originNode(%q) = %q; want %q", id, got, want)
+		}
+	}
+}
+
+func TestClusterStatusCountsAlertsByOrigin(t *testing.T) {
+	a, b := newNode("openfero-a"), newNode("openfero-b")
+	alert := alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull"}}
+	for i := 0; i < 2; i++ {
+		if err := a.SaveAlert(alert, "firing"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.SaveAlert(alert, "firing"); err != nil {
+		t.Fatal(err)
+	}
+	replicate(t, a, b)
+
+	status := b.ClusterStatus()
+	if status.LocalNode != "openfero-b" || status.Alerts["openfero-a"] != 2 || status.Alerts["openfero-b"] != 1 {
+		t.Errorf("status = %+v; want 2 alerts of openfero-a and 1 of openfero-b", status)
+	}
+}
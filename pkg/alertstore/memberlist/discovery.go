@@ -0,0 +1,207 @@
+package memberlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Discovery types accepted by NewDiscovery
+const (
+	DiscoveryStatic     = "static"
+	DiscoveryDNS        = "dns"
+	DiscoverySRV        = "srv"
+	DiscoveryKubernetes = "kubernetes"
+)
+
+// DefaultDomain is the default DNS domain of Kubernetes clusters
+const DefaultDomain = "cluster.local"
+
+// Discovery finds the addresses of the other nodes of a memberlist cluster
+type Discovery interface {
+	// Peers returns host:port addresses of nodes to join. No peers is not an error,
+	// the first node of a cluster finds none.
+	Peers(ctx context.Context) ([]string, error)
+	// String describes the discovery for logging
+	String() string
+}
+
+// DiscoveryConfig selects and configures a discovery
+type DiscoveryConfig struct {
+	Type string
+	// Peers are the host:port addresses of static discovery, a missing port defaults to Port
+	Peers []string
+	// Service and Namespace name the headless service of DNS and Kubernetes discovery
+	Service   string
+	Namespace string
+	// Domain is the DNS domain of the cluster, DefaultDomain if empty
+	Domain string
+	// Port is the gossip port of peers found without one
+	Port int
+}
+
+// NewDiscovery creates the discovery of a configuration. Kubernetes discovery needs a clientset.
+func NewDiscovery(config DiscoveryConfig, clientset kubernetes.Interface) (Discovery, error) {
+	domain := config.Domain
+	if domain == "" {
+		domain = DefaultDomain
+	}
+	serviceDNS := fmt.Sprintf("%s.%s.svc.%s", config.Service, config.Namespace, domain)
+
+	switch config.Type {
+	case DiscoveryStatic:
+		return &StaticDiscovery{Addrs: config.Peers, Port: config.Port}, nil
+	case DiscoveryDNS, "":
+		return &DNSDiscovery{Name: serviceDNS, Port: config.Port}, nil
+	case DiscoverySRV:
+		// Kubernetes publishes SRV records for named ports of headless services
+		return &DNSDiscovery{Name: "_memberlist._tcp." + serviceDNS, SRV: true}, nil
+	case DiscoveryKubernetes:
+		if clientset == nil {
+			return nil, errors.New("kubernetes discovery needs a Kubernetes client")
+		}
+		return &EndpointSliceDiscovery{Clientset: clientset, Namespace: config.Namespace, Service: config.Service, Port: config.Port}, nil
+	default:
+		return nil, fmt.Errorf("unknown memberlist discovery %q, use static, dns, srv or kubernetes", config.Type)
+	}
+}
+
+// StaticDiscovery returns a fixed list of peers
+type StaticDiscovery struct {
+	Addrs []string
+	Port  int
+}
+
+// Peers returns the static addresses, adding the port where it is missing
+func (d *StaticDiscovery) Peers(ctx context.Context) ([]string, error) {
+	peers := make([]string, 0, len(d.Addrs))
+	for _, addr := range d.Addrs {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(addr); err != nil && d.Port != 0 {
+			addr = net.JoinHostPort(addr, strconv.Itoa(d.Port))
+		}
+		peers = append(peers, addr)
+	}
+	return peers, nil
+}
+
+func (d *StaticDiscovery) String() string {
+	return "static " + strings.Join(d.Addrs, ",")
+}
+
+// DNSDiscovery resolves the A or AAAA records of a name, or its SRV records
+type DNSDiscovery struct {
+	Name string
+	// Port is used for A and AAAA records, SRV records carry their own
+	Port int
+	SRV  bool
+	// Resolver defaults to net.DefaultResolver
+	Resolver *net.Resolver
+}
+
+// Peers resolves the name, a name that does not exist yet has no peers
+func (d *DNSDiscovery) Peers(ctx context.Context) ([]string, error) {
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	var peers []string
+	if d.SRV {
+		_, records, err := resolver.LookupSRV(ctx, "", "", d.Name)
+		if isNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up SRV records of %s: %w", d.Name, err)
+		}
+		for _, record := range records {
+			peers = append(peers, net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port))))
+		}
+		return peers, nil
+	}
+
+	hosts, err := resolver.LookupHost(ctx, d.Name)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", d.Name, err)
+	}
+	for _, host := range hosts {
+		peers = append(peers, net.JoinHostPort(host, strconv.Itoa(d.Port)))
+	}
+	return peers, nil
+}
+
+func (d *DNSDiscovery) String() string {
+	if d.SRV {
+		return "dns srv " + d.Name
+	}
+	return "dns " + d.Name
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// EndpointSliceDiscovery reads the addresses of a service from its EndpointSlices.
+// Endpoints that are not ready yet are peers too, only terminating ones are skipped.
+type EndpointSliceDiscovery struct {
+	Clientset kubernetes.Interface
+	Namespace string
+	Service   string
+	// Port is used if the slices have no port named memberlist
+	Port int
+}
+
+// Peers lists the EndpointSlices of the service
+func (d *EndpointSliceDiscovery) Peers(ctx context.Context) ([]string, error) {
+	slices, err := d.Clientset.DiscoveryV1().EndpointSlices(d.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + d.Service,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EndpointSlices of service %s/%s: %w", d.Namespace, d.Service, err)
+	}
+
+	seen := make(map[string]bool)
+	var peers []string
+	for _, slice := range slices.Items {
+		port := d.Port
+		for _, p := range slice.Ports {
+			if p.Name != nil && *p.Name == "memberlist" && p.Port != nil {
+				port = int(*p.Port)
+			}
+		}
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating {
+				continue
+			}
+			for _, address := range endpoint.Addresses {
+				peer := net.JoinHostPort(address, strconv.Itoa(port))
+				if !seen[peer] {
+					seen[peer] = true
+					peers = append(peers, peer)
+				}
+			}
+		}
+	}
+	sort.Strings(peers)
+	return peers, nil
+}
+
+func (d *EndpointSliceDiscovery) String() string {
+	return fmt.Sprintf("kubernetes endpointslices %s/%s", d.Namespace, d.Service)
+}
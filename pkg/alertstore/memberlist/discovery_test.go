@@ -0,0 +1,80 @@
+package memberlist
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewDiscovery(t *testing.T) {
+	config := DiscoveryConfig{Service: "openfero-headless", Namespace: "monitoring", Domain: "corp.example", Port: 7946}
+
+	config.Type = DiscoveryDNS
+	discovery, err := NewDiscovery(config, nil)
+	if err != nil || discovery.(*DNSDiscovery).Name != "openfero-headless.monitoring.svc.corp.example" {
+		t.Errorf("dns discovery = %v, %v; want the service name in the custom domain", discovery, err)
+	}
+
+	config.Type = DiscoverySRV
+	discovery, err = NewDiscovery(config, nil)
+	if err != nil || discovery.(*DNSDiscovery).Name != "_memberlist._tcp.openfero-headless.monitoring.svc.corp.example" {
+		t.Errorf("srv discovery = %v, %v; want the SRV name of the memberlist port", discovery, err)
+	}
+
+	config.Type = DiscoveryKubernetes
+	if _, err := NewDiscovery(config, nil); err == nil {
+		t.Error("kubernetes discovery without a client succeeded")
+	}
+	config.Type = "consul"
+	if _, err := NewDiscovery(config, nil); err == nil {
+		t.Error("an unknown discovery type was accepted")
+	}
+}
+
+func TestStaticDiscovery(t *testing.T) {
+	discovery := &StaticDiscovery{Addrs: []string{"10.0.0.1", " node-2:8000", "", "[fd00::3]:7946"}, Port: 7946}
+	peers, err := discovery.Peers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.1:7946", "node-2:8000", "[fd00::3]:7946"}
+	if !reflect.DeepEqual(peers, want) {
+		t.Errorf("peers = %v; want %v", peers, want)
+	}
+}
+
+func TestEndpointSliceDiscovery(t *testing.T) {
+	portName, port, yes, no := "memberlist", int32(8946), true, false
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "openfero-headless-abc",
+			Namespace: "monitoring",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "openfero-headless"},
+		},
+		Ports: []discoveryv1.EndpointPort{{Name: &portName, Port: &port}},
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.2"}, Conditions: discoveryv1.EndpointConditions{Ready: &no}},
+			{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &yes}},
+			{Addresses: []string{"10.0.0.3"}, Conditions: discoveryv1.EndpointConditions{Terminating: &yes}},
+		},
+	}
+	other := slice.DeepCopy()
+	other.Name = "other-abc"
+	other.Labels = map[string]string{discoveryv1.LabelServiceName: "other"}
+	other.Endpoints = []discoveryv1.Endpoint{{Addresses: []string{"10.0.9.9"}}}
+
+	discovery := &EndpointSliceDiscovery{Clientset: fake.NewClientset(slice, other), Namespace: "monitoring", Service: "openfero-headless", Port: 7946}
+	peers, err := discovery.Peers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Not ready endpoints are peers, terminating ones and other services are not
+	want := []string{"10.0.0.1:8946", "10.0.0.2:8946"}
+	if !reflect.DeepEqual(peers, want) {
+		t.Errorf("peers = %v; want %v", peers, want)
+	}
+}
@@ -0,0 +1,128 @@
+package memberlist
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// DefaultRejoinInterval is how often peers are discovered again by default
+const DefaultRejoinInterval = time.Minute
+
+// discoveryTimeout bounds a single discovery
+const discoveryTimeout = 10 * time.Second
+
+// defaultDiscovery resolves the headless service of $MEMBERLIST_SERVICE_NAME in $POD_NAMESPACE,
+// or in the namespace of the service account
+func defaultDiscovery() Discovery {
+	serviceName := os.Getenv("MEMBERLIST_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "openfero-headless"
+	}
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		namespaceData, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+		if err == nil {
+			namespace = strings.TrimSpace(string(namespaceData))
+		} else {
+			namespace = "default"
+			log.Debug("Using default namespace", zap.Error(err))
+		}
+	}
+	discovery, _ := NewDiscovery(DiscoveryConfig{
+		Type:      DiscoveryDNS,
+		Service:   serviceName,
+		Namespace: namespace,
+		Port:      DefaultGossipConfig().BindPort,
+	}, nil)
+	return discovery
+}
+
+// Ready returns the error of the initial join until a join succeeded
+func (s *MemberlistStore) Ready() error {
+	s.joinMutex.RLock()
+	defer s.joinMutex.RUnlock()
+	return s.joinErr
+}
+
+// initialJoin joins the discovered peers and records the result for Ready. Finding no
+// peers is not an error, the node then forms a new cluster that others join later.
+func (s *MemberlistStore) initialJoin() {
+	log.Info("Trying to join memberlist cluster", zap.Stringer("discovery", s.Discovery))
+
+	joined, err := s.join()
+	if err != nil {
+		log.Warn("Failed to join cluster, creating new cluster",
+			zap.Error(err),
+			zap.Stringer("discovery", s.Discovery))
+		err = fmt.Errorf("initial memberlist join failed: %w", err)
+	} else {
+		log.Info("Successfully joined cluster", zap.Int("nodesJoined", joined))
+	}
+
+	s.joinMutex.Lock()
+	s.joinErr = err
+	s.joinMutex.Unlock()
+}
+
+// join discovers peers and joins those that are not members yet
+func (s *MemberlistStore) join() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+	peers, err := s.Discovery.Peers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	members := make(map[string]bool)
+	for _, member := range s.ml.Members() {
+		members[member.Address()] = true
+	}
+	var unknown []string
+	for _, peer := range peers {
+		if !members[peer] {
+			unknown = append(unknown, peer)
+		}
+	}
+	if len(unknown) == 0 {
+		return 0, nil
+	}
+
+	log.Debug("Joining discovered peers", zap.Strings("peers", unknown))
+	return s.ml.Join(unknown)
+}
+
+// rejoinLoop joins newly discovered peers until the store is closed, so a node that
+// started alone or a split cluster merges again
+func (s *MemberlistStore) rejoinLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.RejoinInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			joined, err := s.join()
+			if err != nil {
+				log.Warn("Failed to rejoin memberlist peers", zap.Error(err), zap.Stringer("discovery", s.Discovery))
+				continue
+			}
+			if joined > 0 {
+				log.Info("Rejoined memberlist peers",
+					zap.Int("nodesJoined", joined),
+					zap.Int("members", s.ml.NumMembers()))
+			}
+			s.joinMutex.Lock()
+			s.joinErr = nil
+			s.joinMutex.Unlock()
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package memberlist
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/hashicorp/memberlist"
+	"go.uber.org/zap"
+)
+
+// keyReloadInterval is how often the key file is checked for rotated keys.
+// The kubelet updates mounted Secrets within about a minute.
+const keyReloadInterval = 30 * time.Second
+
+// GossipConfig configures the network and encryption of the memberlist gossip
+type GossipConfig struct {
+	// BindAddr and BindPort are the address and port to listen on
+	BindAddr string
+	BindPort int
+	// AdvertiseAddr and AdvertisePort are the address and port other nodes connect to,
+	// empty and zero use the bind address and port
+	AdvertiseAddr string
+	AdvertisePort int
+	// Label is sent with every packet and stream, nodes with another label are rejected
+	Label string
+	// KeyFile holds base64 encoded AES keys of 16, 24 or 32 bytes, one per line.
+	// The first key encrypts, all keys decrypt. Empty disables encryption.
+	KeyFile string
+}
+
+// DefaultGossipConfig returns the memberlist defaults without encryption
+func DefaultGossipConfig() GossipConfig {
+	defaults := memberlist.DefaultLANConfig()
+	return GossipConfig{
+		BindAddr: defaults.BindAddr,
+		BindPort: defaults.BindPort,
+	}
+}
+
+// apply sets the gossip configuration on a memberlist configuration
+func (g GossipConfig) apply(config *memberlist.Config) error {
+	if g.BindAddr != "" {
+		config.BindAddr = g.BindAddr
+	}
+	if g.BindPort != 0 {
+		config.BindPort = g.BindPort
+		config.AdvertisePort = g.BindPort
+	}
+	config.AdvertiseAddr = g.AdvertiseAddr
+	if g.AdvertisePort != 0 {
+		config.AdvertisePort = g.AdvertisePort
+	}
+	config.Label = g.Label
+
+	if g.KeyFile == "" {
+		return nil
+	}
+	keys, _, err := loadKeys(g.KeyFile)
+	if err != nil {
+		return err
+	}
+	keyring, err := memberlist.NewKeyring(keys, keys[0])
+	if err != nil {
+		return fmt.Errorf("failed to create gossip keyring: %w", err)
+	}
+	config.Keyring = keyring
+	return nil
+}
+
+// loadKeys reads the keys of a key file and returns them with the raw file content.
+// Empty lines and lines starting with # are ignored.
+func loadKeys(path string) ([][]byte, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read gossip key file: %w", err)
+	}
+
+	var keys [][]byte
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(line)
+		if err != nil {
+			return nil, nil, fmt.Errorf("gossip key on line %d is not base64 encoded", i+1)
+		}
+		if err := memberlist.ValidateKey(key); err != nil {
+			return nil, nil, fmt.Errorf("gossip key on line %d: %w", i+1, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, nil, fmt.Errorf("gossip key file %s holds no keys", path)
+	}
+	return keys, data, nil
+}
+
+// rotateKeys installs the keys of the key file in the keyring. New keys are added before the
+// primary key changes, and keys missing from the file are removed after it changed, so a key
+// is rotated by adding it on the second line, moving it to the first and removing the old key.
+func rotateKeys(keyring *memberlist.Keyring, keys [][]byte) error {
+	for _, key := range keys {
+		if err := keyring.AddKey(key); err != nil {
+			return err
+		}
+	}
+	if err := keyring.UseKey(keys[0]); err != nil {
+		return err
+	}
+	for _, installed := range keyring.GetKeys() {
+		if !slices.ContainsFunc(keys, func(key []byte) bool { return bytes.Equal(key, installed) }) {
+			if err := keyring.RemoveKey(installed); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// keyringLoop installs rotated keys from the key file until the store is closed
+func (s *MemberlistStore) keyringLoop(keyring *memberlist.Keyring) {
+	defer s.wg.Done()
+
+	_, last, _ := loadKeys(s.Gossip.KeyFile)
+	ticker := time.NewTicker(keyReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			keys, data, err := loadKeys(s.Gossip.KeyFile)
+			if err != nil {
+				log.Error("Failed to reload gossip keys, keeping the current keys", zap.Error(err))
+				continue
+			}
+			if bytes.Equal(data, last) {
+				continue
+			}
+			if err := rotateKeys(keyring, keys); err != nil {
+				log.Error("Failed to rotate gossip keys", zap.Error(err))
+				continue
+			}
+			last = data
+			log.Info("Rotated gossip keys", zap.Int("keys", len(keys)))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package memberlist
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/memberlist"
+)
+
+func writeKeys(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys")
+	var data []byte
+	for _, line := range lines {
+		data = append(data, line+"\n"...)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadKeys(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 16)
+
+	keys, _, err := loadKeys(writeKeys(t, "# primary first", base64.StdEncoding.EncodeToString(oldKey), "", base64.StdEncoding.EncodeToString(newKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || !bytes.Equal(keys[0], oldKey) || !bytes.Equal(keys[1], newKey) {
+		t.Errorf("keys = %v; want the old and the new key", keys)
+	}
+
+	for name, lines := range map[string][]string{
+		"no keys":     {"# nothing"},
+		"not base64":  {"not a key!"},
+		"wrong size":  {base64.StdEncoding.EncodeToString([]byte("short"))},
+		"missing key": nil,
+	} {
+		if _, _, err := loadKeys(writeKeys(t, lines...)); err == nil {
+			t.Errorf("%s: loadKeys succeeded; want an error", name)
+		}
+	}
+}
+
+func TestRotateKeys(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+	keyring, err := memberlist.NewKeyring([][]byte{oldKey}, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add the new key as the second key, the old one still encrypts
+	if err := rotateKeys(keyring, [][]byte{oldKey, newKey}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(keyring.GetPrimaryKey(), oldKey) || len(keyring.GetKeys()) != 2 {
+		t.Errorf("after adding, the keyring holds %d keys; want 2 with the old primary key", len(keyring.GetKeys()))
+	}
+
+	// Swap the keys, then remove the old one
+	if err := rotateKeys(keyring, [][]byte{newKey, oldKey}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(keyring.GetPrimaryKey(), newKey) {
+		t.Error("the new key is not the primary key after swapping")
+	}
+	if err := rotateKeys(keyring, [][]byte{newKey}); err != nil {
+		t.Fatal(err)
+	}
+	if keys := keyring.GetKeys(); len(keys) != 1 || !bytes.Equal(keys[0], newKey) {
+		t.Errorf("after removing, the keyring holds %d keys; want only the new key", len(keys))
+	}
+}
+
+func TestGossipConfigApply(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{3}, 24))
+	config := memberlist.DefaultLANConfig()
+	gossip := GossipConfig{BindAddr: "127.0.0.1", BindPort: 8946, Label: "openfero-prod", KeyFile: writeKeys(t, key)}
+	if err := gossip.apply(config); err != nil {
+		t.Fatal(err)
+	}
+	if config.BindAddr != "127.0.0.1" || config.BindPort != 8946 || config.AdvertisePort != 8946 || config.Label != "openfero-prod" {
+		t.Errorf("config = %s:%d advertising %d with label %q", config.BindAddr, config.BindPort, config.AdvertisePort, config.Label)
+	}
+	if config.Keyring == nil || len(config.Keyring.GetKeys()) != 1 {
+		t.Error("the keyring was not loaded")
+	}
+}
@@ -1,17 +1,17 @@
 package memberlist
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
-	"strings"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"sort"
-
 	"github.com/OpenFero/openfero/pkg/alertstore"
 	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/metadata"
 	"github.com/hashicorp/memberlist"
 	"go.uber.org/zap"
 )
@@ -22,12 +22,35 @@ type MemberlistStore struct {
 	broadcasts *memberlist.TransmitLimitedQueue
 	alerts     []alertEntry
 	mutex      sync.RWMutex
-	limit      int
+	policy     alertstore.RetentionPolicy
+	pruner     *alertstore.Pruner
 	delegate   *delegate
+	// node, incarnation and seq form the IDs of new entries
+	node        string
+	incarnation string
+	seq         atomic.Uint64
+	// Gossip configures the network and encryption, it is read by Initialize
+	Gossip GossipConfig
+	// Meta is announced to the other nodes, it is read by Initialize
+	Meta NodeMeta
+	// Discovery finds the peers to join, Initialize defaults it to the DNS name of the headless service
+	Discovery Discovery
+	// RejoinInterval is how often peers are discovered again to heal split clusters, 0 disables it
+	RejoinInterval time.Duration
+	joinMutex      sync.RWMutex
+	joinErr        error
+	stop           chan struct{}
+	stopOnce       sync.Once
+	wg             sync.WaitGroup
+	feed           alertstore.Feed
 }
 
-// alertEntry represents a single alert in the store
+// alertEntry represents a single alert in the store. Nodes keep the revision with
+// the highest version, the name of the writing node breaks ties.
 type alertEntry struct {
+	ID        string              `json:"id"`
+	Version   uint64              `json:"version"`
+	Writer    string              `json:"writer,omitempty"`
 	Alert     alertstore.Alert    `json:"alert"`
 	Status    string              `json:"status"`
 	Timestamp time.Time           `json:"timestamp"`
@@ -38,22 +61,40 @@ type alertEntry struct {
 type delegate struct {
 	broadcasts *memberlist.TransmitLimitedQueue
 	store      *MemberlistStore
+	// members counts the nodes in the cluster, including the local one
+	members atomic.Int32
+	probes  probes
 }
 
-// NewMemberlistStore creates a new memberlist-based alert store
+// NewMemberlistStore creates a new memberlist-based alert store that keeps the last limit entries
 func NewMemberlistStore(clustername string, limit int) *MemberlistStore {
-	if limit <= 0 {
-		limit = 100
+	return NewMemberlistStoreWithRetention(clustername, alertstore.RetentionPolicy{MaxEntries: limit})
+}
+
+// NewMemberlistStoreWithRetention creates a new memberlist-based alert store with a retention policy.
+// Every node applies the policy to its own copy of the alerts.
+func NewMemberlistStoreWithRetention(clustername string, policy alertstore.RetentionPolicy) *MemberlistStore {
+	if policy.MaxEntries <= 0 {
+		policy.MaxEntries = 100
 	}
 
 	log.Debug("Creating new memberlist store",
 		zap.String("clusterName", clustername),
-		zap.Int("alertLimit", limit))
+		zap.Int("alertLimit", policy.MaxEntries),
+		zap.Int("alertnameQuota", policy.MaxPerAlertname),
+		zap.Duration("maxAge", policy.MaxAge))
 
+	hostname, _ := os.Hostname()
 	store := &MemberlistStore{
-		alerts: make([]alertEntry, 0, limit),
-		limit:  limit,
+		alerts:         make([]alertEntry, 0, policy.MaxEntries),
+		policy:         policy,
+		Gossip:         DefaultGossipConfig(),
+		RejoinInterval: DefaultRejoinInterval,
+		stop:           make(chan struct{}),
+		node:           hostname,
+		incarnation:    strconv.FormatInt(time.Now().UnixNano(), 36),
 	}
+	store.pruner = alertstore.NewPruner(alertstore.DefaultPruneInterval, store.Prune)
 
 	// Create delegate
 	store.delegate = &delegate{
@@ -66,14 +107,24 @@ func NewMemberlistStore(clustername string, limit int) *MemberlistStore {
 // Initialize sets up the memberlist cluster
 func (s *MemberlistStore) Initialize() error {
 	// Create memberlist config
-	hostname, _ := os.Hostname()
 	config := memberlist.DefaultLANConfig()
-	config.Name = hostname
+	config.Name = s.node
 	config.Delegate = s.delegate
 	config.Events = s.delegate
+	config.Ping = s.delegate
+	if err := s.Gossip.apply(config); err != nil {
+		log.Error("Failed to configure memberlist gossip", zap.Error(err))
+		return err
+	}
 
 	log.Debug("Initializing memberlist with config",
-		zap.String("hostname", hostname))
+		zap.String("hostname", s.node),
+		zap.String("bindAddr", config.BindAddr),
+		zap.Int("bindPort", config.BindPort),
+		zap.String("advertiseAddr", config.AdvertiseAddr),
+		zap.Int("advertisePort", config.AdvertisePort),
+		zap.String("label", config.Label),
+		zap.Bool("encrypted", config.Keyring != nil))
 
 	// Create memberlist first
 	ml, err := memberlist.Create(config)
@@ -92,42 +143,21 @@ func (s *MemberlistStore) Initialize() error {
 	}
 	s.delegate.broadcasts = s.broadcasts
 
-	// Try to join the cluster using Kubernetes DNS for service discovery
-	// This assumes there's a headless service for the openfero pods
-	serviceName := os.Getenv("MEMBERLIST_SERVICE_NAME")
-	if serviceName == "" {
-		serviceName = "openfero-headless"
-	}
-	namespace := os.Getenv("POD_NAMESPACE")
-	if namespace == "" {
-		// Try to get namespace from the mounted service account
-		// Fix shadowing of err variable
-		namespaceData, readErr := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
-		if readErr == nil {
-			namespace = string(namespaceData)
-			log.Debug("Discovered namespace from service account", zap.String("namespace", namespace))
-		} else {
-			namespace = "default"
-			log.Debug("Using default namespace", zap.Error(readErr))
-		}
+	if s.Discovery == nil {
+		s.Discovery = defaultDiscovery()
 	}
+	s.initialJoin()
 
-	// Form the service DNS name for Kubernetes
-	serviceDNS := fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, namespace)
-	log.Info("Trying to join memberlist cluster",
-		zap.String("service", serviceDNS),
-		zap.String("namespace", namespace))
-
-	// Try joining the cluster
-	joinCount, err := s.ml.Join([]string{serviceDNS})
-	if err != nil {
-		log.Warn("Failed to join cluster, creating new cluster",
-			zap.Error(err),
-			zap.String("serviceDNS", serviceDNS))
-		// This is not a fatal error - this node will form its own cluster
-		// that others can join later
-	} else {
-		log.Info("Successfully joined cluster", zap.Int("nodesJoined", joinCount))
+	if s.policy.MaxAge > 0 {
+		s.pruner.Start()
+	}
+	if config.Keyring != nil {
+		s.wg.Add(1)
+		go s.keyringLoop(config.Keyring)
+	}
+	if s.RejoinInterval > 0 {
+		s.wg.Add(1)
+		go s.rejoinLoop()
 	}
 
 	log.Info("Memberlist store initialized",
@@ -144,6 +174,9 @@ func (s *MemberlistStore) SaveAlert(alert alertstore.Alert, status string) error
 // SaveAlertWithJobInfo adds an alert with job info to the store and broadcasts it to the cluster
 func (s *MemberlistStore) SaveAlertWithJobInfo(alert alertstore.Alert, status string, jobInfo *alertstore.JobInfo) error {
 	entry := alertEntry{
+		ID:        s.newID(),
+		Version:   1,
+		Writer:    s.node,
 		Alert:     alert,
 		Status:    status,
 		Timestamp: time.Now(),
@@ -152,6 +185,7 @@ func (s *MemberlistStore) SaveAlertWithJobInfo(alert alertstore.Alert, status st
 
 	alertName := alert.Labels["alertname"]
 	log.Debug("Saving alert to memberlist store",
+		zap.String("id", entry.ID),
 		zap.String("alertname", alertName),
 		zap.String("status", status))
 
@@ -160,146 +194,170 @@ func (s *MemberlistStore) SaveAlertWithJobInfo(alert alertstore.Alert, status st
 
 	// Add alert to the front of the list (newest first)
 	s.alerts = append([]alertEntry{entry}, s.alerts...)
+	s.feed.Publish(alertstore.Event{Type: alertstore.EventAlert, Entry: entry.entry()})
 
-	// Keep list at or under limit
-	if len(s.alerts) > s.limit {
-		s.alerts = s.alerts[:s.limit]
-		log.Debug("Trimmed alerts to limit", zap.Int("limit", s.limit))
+	if evicted := s.prune(entry.Timestamp); evicted > 0 {
+		log.Debug("Evicted alerts by retention policy", zap.Int("evicted", evicted))
 	}
 
 	// Broadcast the new alert to other nodes if memberlist is initialized
-	data, err := json.Marshal(entry)
-	if err != nil {
-		log.Error("Failed to marshal alert for broadcast",
+	if err := s.publish(entry); err != nil {
+		log.Error("Failed to broadcast alert",
 			zap.Error(err),
 			zap.String("alertname", alertName))
-		return fmt.Errorf("failed to marshal alert: %w", err)
+		return err
 	}
-
-	if s.broadcasts != nil && s.ml != nil {
-		s.broadcasts.QueueBroadcast(&broadcast{
-			msg:    data,
-			notify: nil,
-		})
-		log.Debug("Broadcast alert to cluster",
-			zap.String("alertname", alertName),
-			zap.Int("clusterSize", s.ml.NumMembers()))
-	} else {
-		log.Debug("Skipping broadcast - memberlist not initialized",
-			zap.String("alertname", alertName))
-	}
-
 	return nil
 }
 
-// GetAlerts retrieves alerts, optionally filtered by query
-func (s *MemberlistStore) GetAlerts(query string, limit int) ([]alertstore.AlertEntry, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	log.Debug("Getting alerts from store",
-		zap.String("query", query),
-		zap.Int("requestedLimit", limit),
-		zap.Int("totalAlerts", len(s.alerts)))
-
-	if limit <= 0 || limit > len(s.alerts) {
-		limit = len(s.alerts)
-	}
-
-	// If no query, just return the alerts up to the limit
-	if query == "" {
-		result := make([]alertstore.AlertEntry, 0, limit)
-		for i := 0; i < limit && i < len(s.alerts); i++ {
-			result = append(result, alertstore.AlertEntry{
-				Alert:     s.alerts[i].Alert,
-				Status:    s.alerts[i].Status,
-				Timestamp: s.alerts[i].Timestamp,
-				JobInfo:   s.alerts[i].JobInfo,
-			})
+// ImportAlerts adds entries with their timestamps, skipping entries the store already holds.
+// Imported entries are not broadcast, other nodes receive them with the next push/pull
+// and publish them to their subscribers.
+func (s *MemberlistStore) ImportAlerts(entries []alertstore.AlertEntry) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing := make(map[string]bool, len(s.alerts))
+	for _, e := range s.alerts {
+		existing[alertstore.EntryKey(e.entry())] = true
+	}
+	fresh := alertstore.NewEntries(entries, existing)
+	imported := make([]alertEntry, len(fresh))
+	for i, entry := range fresh {
+		imported[i] = alertEntry{
+			ID:        s.newID(),
+			Version:   1,
+			Writer:    s.node,
+			Alert:     entry.Alert,
+			Status:    entry.Status,
+			Timestamp: entry.Timestamp,
+			JobInfo:   entry.JobInfo,
 		}
-		log.Debug("Returning alerts with no query filter", zap.Int("resultCount", len(result)))
-		return result, nil
 	}
+	s.merge(imported)
+	return len(fresh), nil
+}
 
-	// With query, filter the alerts
-	result := make([]alertstore.AlertEntry, 0)
-	query = strings.ToLower(query)
+// UpdateJobInfo updates the job information of the entry that triggered jobName
+// and broadcasts the updated entry to the cluster
+func (s *MemberlistStore) UpdateJobInfo(jobName string, update func(*alertstore.JobInfo)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-	for _, entry := range s.alerts {
-		if s.alertMatchesQuery(entry, query) {
-			result = append(result, alertstore.AlertEntry{
-				Alert:     entry.Alert,
-				Status:    entry.Status,
-				Timestamp: entry.Timestamp,
-				JobInfo:   entry.JobInfo,
-			})
+	for i := range s.alerts {
+		if s.alerts[i].JobInfo == nil || s.alerts[i].JobInfo.JobName != jobName {
+			continue
 		}
-		if len(result) >= limit {
-			break
+
+		// Copy before updating, entries handed out by GetAlerts share the pointer
+		jobInfo := *s.alerts[i].JobInfo
+		update(&jobInfo)
+		s.alerts[i].JobInfo = &jobInfo
+		// The update replaces the entry on all nodes, whichever node created it
+		s.alerts[i].Version++
+		s.alerts[i].Writer = s.node
+		s.feed.Publish(alertstore.Event{Type: alertstore.EventJob, Entry: s.alerts[i].entry()})
+
+		if err := s.publish(s.alerts[i]); err != nil {
+			log.Error("Failed to broadcast updated job info",
+				zap.Error(err),
+				zap.String("jobName", jobName))
+			return err
 		}
+		return nil
 	}
 
-	log.Debug("Returning filtered alerts",
-		zap.String("query", query),
-		zap.Int("resultCount", len(result)))
-	return result, nil
+	return alertstore.ErrNotFound
 }
 
-// Close leaves the memberlist cluster
-func (s *MemberlistStore) Close() error {
-	if s.ml != nil {
-		log.Info("Leaving memberlist cluster",
-			zap.String("node", s.ml.LocalNode().Name),
-			zap.Int("clusterSize", s.ml.NumMembers()))
-		return s.ml.Leave(time.Second * 5)
-	}
-	log.Debug("No memberlist to close")
-	return nil
+// Subscribe returns the entries saved and updated on any node until ctx is done
+func (s *MemberlistStore) Subscribe(ctx context.Context) <-chan alertstore.Event {
+	return s.feed.Subscribe(ctx)
 }
 
-// alertMatchesQuery checks if an alert matches the search query
-func (s *MemberlistStore) alertMatchesQuery(entry alertEntry, query string) bool {
-	// Check status
-	if strings.Contains(strings.ToLower(entry.Status), query) {
-		return true
+// GetAlerts retrieves alerts newest first, optionally filtered by query
+func (s *MemberlistStore) GetAlerts(query string, limit int) ([]alertstore.AlertEntry, error) {
+	page, err := s.ListAlerts(alertstore.ListOptions{Query: query, Limit: limit})
+	if err != nil {
+		return nil, err
 	}
+	return page.Alerts, nil
+}
 
-	// Check alertname
-	if alertname, ok := entry.Alert.Labels["alertname"]; ok {
-		if strings.Contains(strings.ToLower(alertname), query) {
-			return true
-		}
+// ListAlerts retrieves a page of alerts. Entries with equal timestamps are ordered by their ID.
+func (s *MemberlistStore) ListAlerts(opts alertstore.ListOptions) (*alertstore.Page, error) {
+	list, err := alertstore.NewList(opts, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
-	// Check labels
-	for _, v := range entry.Alert.Labels {
-		if strings.Contains(strings.ToLower(v), query) {
-			return true
-		}
+	s.mutex.RLock()
+	items := make([]alertstore.Item, 0, len(s.alerts))
+	for _, entry := range s.alerts {
+		items = append(items, alertstore.Item{
+			Position: alertstore.Position{Time: entry.Timestamp, ID: entry.ID},
+			Entry: alertstore.AlertEntry{
+				Alert:     entry.Alert,
+				Status:    entry.Status,
+				Timestamp: entry.Timestamp,
+				JobInfo:   entry.JobInfo,
+			},
+		})
 	}
+	s.mutex.RUnlock()
 
-	// Check annotations
-	for _, v := range entry.Alert.Annotations {
-		if strings.Contains(strings.ToLower(v), query) {
-			return true
-		}
+	list.AddAll(items)
+	page := list.Page()
+
+	log.Debug("Returning alerts",
+		zap.String("query", opts.Query),
+		zap.Int("requestedLimit", opts.Limit),
+		zap.Int("totalAlerts", len(items)),
+		zap.Int("resultCount", len(page.Alerts)))
+	return page, nil
+}
+
+// Prune applies the retention policy at now and returns the number of evicted entries
+func (s *MemberlistStore) Prune(now time.Time) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.prune(now)
+}
+
+// prune applies the retention policy, the caller must hold the write lock
+func (s *MemberlistStore) prune(now time.Time) int {
+	entries := make([]alertstore.AlertEntry, len(s.alerts))
+	for i, entry := range s.alerts {
+		entries[i] = alertstore.AlertEntry{Alert: entry.Alert, Status: entry.Status, Timestamp: entry.Timestamp}
+	}
+	evict, evicted := s.policy.Evict(entries, now)
+	if evicted == 0 {
+		return 0
 	}
 
-	// Check job info if present
-	if entry.JobInfo != nil {
-		if strings.Contains(strings.ToLower(entry.JobInfo.ConfigMapName), query) ||
-			strings.Contains(strings.ToLower(entry.JobInfo.JobName), query) ||
-			strings.Contains(strings.ToLower(entry.JobInfo.Image), query) {
-			return true
+	kept := make([]alertEntry, 0, len(s.alerts)-evicted)
+	for i, entry := range s.alerts {
+		if !evict[i] {
+			kept = append(kept, entry)
 		}
 	}
-
-	return false
+	s.alerts = kept
+	return evicted
 }
 
-// NodeMeta is used to retrieve meta-data about the current node
-func (d *delegate) NodeMeta(limit int) []byte {
-	return []byte{}
+// Close stops the pruner and leaves the memberlist cluster
+func (s *MemberlistStore) Close() error {
+	s.pruner.Stop()
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.wg.Wait()
+	if s.ml != nil {
+		log.Info("Leaving memberlist cluster",
+			zap.String("node", s.ml.LocalNode().Name),
+			zap.Int("clusterSize", s.ml.NumMembers()))
+		return s.ml.Leave(time.Second * 5)
+	}
+	log.Debug("No memberlist to close")
+	return nil
 }
 
 // NotifyMsg is called when a user-data message is received
@@ -308,20 +366,16 @@ func (d *delegate) NotifyMsg(data []byte) {
 		return
 	}
 
-	// Deserialize the alert entry
-	var entry alertEntry
-	if err := json.Unmarshal(data, &entry); err != nil {
+	entries, err := decodeEntries(data)
+	if err != nil {
 		log.Error("Failed to unmarshal alert in NotifyMsg",
 			zap.Error(err),
 			zap.Int("dataLength", len(data)))
 		return
 	}
 
-	alertName := entry.Alert.Labels["alertname"]
 	log.Debug("Received alert notification from cluster",
-		zap.String("alertname", alertName),
-		zap.String("status", entry.Status),
-		zap.Time("timestamp", entry.Timestamp))
+		zap.Int("entries", len(entries)))
 
 	// Add the alert to our local store
 	if d.store == nil {
@@ -332,30 +386,11 @@ func (d *delegate) NotifyMsg(data []byte) {
 	d.store.mutex.Lock()
 	defer d.store.mutex.Unlock()
 
-	// Check if this alert already exists (exact match by alertname, labels, and timestamp)
-	for _, existing := range d.store.alerts {
-		if existing.Timestamp.Equal(entry.Timestamp) {
-			// Compare alertname if it exists
-			if alertname, ok := existing.Alert.Labels["alertname"]; ok {
-				if newAlertname, ok2 := entry.Alert.Labels["alertname"]; ok2 && alertname == newAlertname {
-					// Already have this alert, skip it
-					log.Debug("Skipping duplicate alert",
-						zap.String("alertname", alertname),
-						zap.Time("timestamp", entry.Timestamp))
-					return
-				}
-			}
-		}
-	}
-
-	// Add the alert to the front of our list
-	d.store.alerts = append([]alertEntry{entry}, d.store.alerts...)
-
-	// Keep list at or under limit
-	if len(d.store.alerts) > d.store.limit {
-		d.store.alerts = d.store.alerts[:d.store.limit]
-		log.Debug("Trimmed alerts to limit after receiving new alert",
-			zap.Int("limit", d.store.limit))
+	if events := d.store.merge(entries); len(events) > 0 {
+		d.store.feed.Publish(events...)
+		log.Debug("Applied alerts from cluster",
+			zap.Int("changed", len(events)),
+			zap.Int("totalAlerts", len(d.store.alerts)))
 	}
 }
 
@@ -377,7 +412,8 @@ func (d *delegate) LocalState(join bool) []byte {
 	d.store.mutex.RLock()
 	defer d.store.mutex.RUnlock()
 
-	data, err := json.Marshal(d.store.alerts)
+	// Only versions are exchanged, each side then pushes the entries the other one misses
+	data, err := encodeMessage(msgDigest, d.store.digest())
 	if err != nil {
 		log.Error("Failed to marshal local state",
 			zap.Error(err),
@@ -408,70 +444,47 @@ func (d *delegate) MergeRemoteState(buf []byte, join bool) {
 		zap.Int("bufferSize", len(buf)),
 		zap.Bool("joinOperation", join))
 
-	var remoteAlerts []alertEntry
-	if err := json.Unmarshal(buf, &remoteAlerts); err != nil {
+	if buf[0] == '[' {
+		// Full state of a node without IDs
+		entries, err := decodeEntries(buf)
+		if err != nil {
+			log.Error("Failed to unmarshal remote state",
+				zap.Error(err),
+				zap.Int("bufferSize", len(buf)))
+			return
+		}
+		d.store.mutex.Lock()
+		defer d.store.mutex.Unlock()
+		d.store.feed.Publish(d.store.merge(entries)...)
+		return
+	}
+
+	var remote digest
+	if err := decodeMessage(buf, msgDigest, &remote); err != nil {
 		log.Error("Failed to unmarshal remote state",
 			zap.Error(err),
 			zap.Int("bufferSize", len(buf)))
 		return
 	}
 
-	log.Debug("Unmarshalled remote alerts",
-		zap.Int("remoteAlertCount", len(remoteAlerts)))
-
-	d.store.mutex.Lock()
-	defer d.store.mutex.Unlock()
-
-	// Add remote alerts to our list, avoiding duplicates
-	newAlertCount := 0
-	for _, remoteEntry := range remoteAlerts {
-		found := false
-		for _, localEntry := range d.store.alerts {
-			if localEntry.Timestamp.Equal(remoteEntry.Timestamp) {
-				// Compare alertname if it exists
-				if alertname, ok := localEntry.Alert.Labels["alertname"]; ok {
-					if remoteAlertname, ok2 := remoteEntry.Alert.Labels["alertname"]; ok2 && alertname == remoteAlertname {
-						found = true
-						break
-					}
-				}
-			}
-		}
-
-		if !found {
-			d.store.alerts = append(d.store.alerts, remoteEntry)
-			newAlertCount++
-		}
-	}
-
-	if newAlertCount > 0 {
-		log.Debug("Added new alerts from remote state",
-			zap.Int("newAlerts", newAlertCount),
-			zap.Int("totalAlerts", len(d.store.alerts)))
-	}
-
-	// Sort by timestamp (newest first)
-	sort.Slice(d.store.alerts, func(i, j int) bool {
-		return d.store.alerts[i].Timestamp.After(d.store.alerts[j].Timestamp)
-	})
+	d.store.mutex.RLock()
+	missing := d.store.missing(remote)
+	d.store.mutex.RUnlock()
 
-	// Trim to limit
-	if len(d.store.alerts) > d.store.limit {
-		oldLength := len(d.store.alerts)
-		d.store.alerts = d.store.alerts[:d.store.limit]
-		log.Debug("Trimmed alerts to limit after merge",
-			zap.Int("limit", d.store.limit),
-			zap.Int("removed", oldLength-d.store.limit))
+	log.Debug("Remote node misses alerts",
+		zap.String("node", remote.Node),
+		zap.Int("remoteAlertCount", len(remote.Versions)),
+		zap.Int("missing", len(missing)))
+	if len(missing) > 0 && d.store.ml != nil {
+		go d.store.pushEntries(remote.Node, missing)
 	}
 }
 
 // NotifyJoin is invoked when a node joins the cluster
 func (d *delegate) NotifyJoin(node *memberlist.Node) {
-	// Safe access to cluster size
-	var clusterSize int
-	if d.store != nil && d.store.ml != nil {
-		clusterSize = d.store.ml.NumMembers()
-	}
+	// Memberlist holds its node lock here, so NumMembers would deadlock
+	clusterSize := int(d.members.Add(1))
+	metadata.MemberlistMembers.Set(float64(clusterSize))
 
 	log.Info("Node joined the cluster",
 		zap.String("node", node.Name),
@@ -482,11 +495,9 @@ func (d *delegate) NotifyJoin(node *memberlist.Node) {
 
 // NotifyLeave is invoked when a node leaves the cluster
 func (d *delegate) NotifyLeave(node *memberlist.Node) {
-	// Safe access to cluster size
-	var clusterSize int
-	if d.store != nil && d.store.ml != nil {
-		clusterSize = d.store.ml.NumMembers()
-	}
+	// Memberlist holds its node lock here, so NumMembers would deadlock
+	clusterSize := int(d.members.Add(-1))
+	metadata.MemberlistMembers.Set(float64(clusterSize))
 
 	log.Info("Node left the cluster",
 		zap.String("node", node.Name),
@@ -503,15 +514,21 @@ func (d *delegate) NotifyUpdate(node *memberlist.Node) {
 		zap.Uint8("state", uint8(node.State)))
 }
 
-// broadcast implements the memberlist.Broadcast interface
+// broadcast implements the memberlist.NamedBroadcast interface. A queued revision
+// of an entry is replaced by a newer one.
 type broadcast struct {
+	name   string
 	msg    []byte
 	notify chan<- struct{}
 }
 
 func (b *broadcast) Invalidates(other memberlist.Broadcast) bool {
-	// Never invalidate - we want all alerts to propagate
-	return false
+	named, ok := other.(memberlist.NamedBroadcast)
+	return ok && b.name == named.Name()
+}
+
+func (b *broadcast) Name() string {
+	return b.name
 }
 
 func (b *broadcast) Message() []byte {
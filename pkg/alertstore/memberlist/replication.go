@@ -0,0 +1,277 @@
+package memberlist
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/hashicorp/memberlist"
+	"go.uber.org/zap"
+)
+
+// Message kinds, the first byte of every message. Nodes without IDs sent plain JSON,
+// which starts with { for a single entry and with [ for their full state.
+const (
+	msgEntries byte = 1 // gzip compressed JSON array of entries
+	msgDigest  byte = 2 // gzip compressed JSON digest sent in push/pull
+)
+
+// maxBroadcastSize is the largest message gossiped over UDP, larger ones are sent to every
+// member over TCP. The default UDP buffer is 1400 bytes and carries protocol overhead.
+const maxBroadcastSize = 1200
+
+// deltaBatchSize is the maximum number of entries per message sent to a node that misses them
+const deltaBatchSize = 100
+
+// version identifies a revision of an entry. The higher version wins, the writer breaks ties.
+type version struct {
+	ID      string `json:"i"`
+	Version uint64 `json:"v"`
+	Writer  string `json:"w,omitempty"`
+}
+
+// digest is the push/pull state of a node, the versions of all its entries
+type digest struct {
+	Node     string    `json:"node"`
+	Versions []version `json:"versions"`
+}
+
+// entry returns the entry without its revision
+func (e alertEntry) entry() alertstore.AlertEntry {
+	return alertstore.AlertEntry{Alert: e.Alert, Status: e.Status, Timestamp: e.Timestamp, JobInfo: e.JobInfo}
+}
+
+func (e alertEntry) version() version {
+	return version{ID: e.ID, Version: e.Version, Writer: e.Writer}
+}
+
+// newer reports whether revision a replaces revision b of the same entry
+func newer(a, b version) bool {
+	if a.Version != b.Version {
+		return a.Version > b.Version
+	}
+	return a.Writer > b.Writer
+}
+
+// legacyID identifies entries of nodes that did not assign IDs yet
+func legacyID(entry alertEntry) string {
+	return fmt.Sprintf("legacy-%d-%s", entry.Timestamp.UnixNano(), entry.Alert.Labels["alertname"])
+}
+
+// newID returns a cluster-wide unique entry ID: the node name, the start time of the
+// process, as pod names repeat in StatefulSets, and a sequence number
+func (s *MemberlistStore) newID() string {
+	return fmt.Sprintf("%s-%s-%d", s.node, s.incarnation, s.seq.Add(1))
+}
+
+// encodeMessage compresses the JSON encoding of v behind the message kind
+func encodeMessage(kind byte, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(kind)
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(v); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeMessage decodes a message of the given kind into v
+func decodeMessage(data []byte, kind byte, v interface{}) error {
+	if len(data) == 0 || data[0] != kind {
+		return errors.New("unexpected message kind")
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data[1:]))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = zr.Close() }()
+	return json.NewDecoder(io.LimitReader(zr, maxStateBytes)).Decode(v)
+}
+
+// maxStateBytes bounds decompressed messages, like memberlist bounds push/pull states
+const maxStateBytes = 64 * 1024 * 1024
+
+// decodeEntries decodes a message carrying entries, including single entries and
+// full states of nodes without IDs
+func decodeEntries(data []byte) ([]alertEntry, error) {
+	switch data[0] {
+	case '{':
+		var entry alertEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, err
+		}
+		return []alertEntry{entry}, nil
+	case '[':
+		var entries []alertEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	default:
+		var entries []alertEntry
+		if err := decodeMessage(data, msgEntries, &entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+}
+
+// merge applies remote entries, keeping the newer revision of known entries.
+// It returns an event for every added or updated entry the retention policy keeps,
+// so entries this node evicted before are not announced again whenever a peer
+// with more retention pushes them. The caller must hold the write lock.
+func (s *MemberlistStore) merge(entries []alertEntry) []alertstore.Event {
+	index := make(map[string]int, len(s.alerts))
+	for i, entry := range s.alerts {
+		index[entry.ID] = i
+	}
+
+	var events []alertstore.Event
+	var ids []string
+	added := false
+	for _, entry := range entries {
+		if entry.ID == "" {
+			entry.ID = legacyID(entry)
+		}
+		if i, ok := index[entry.ID]; ok {
+			if newer(entry.version(), s.alerts[i].version()) {
+				s.alerts[i] = entry
+				events = append(events, alertstore.Event{Type: alertstore.EventJob, Entry: entry.entry()})
+				ids = append(ids, entry.ID)
+			}
+			continue
+		}
+		index[entry.ID] = len(s.alerts)
+		s.alerts = append(s.alerts, entry)
+		events = append(events, alertstore.Event{Type: alertstore.EventAlert, Entry: entry.entry()})
+		ids = append(ids, entry.ID)
+		added = true
+	}
+
+	if !added {
+		return events
+	}
+	// Newest first, remote entries may be older than local ones
+	sort.SliceStable(s.alerts, func(i, j int) bool {
+		return s.alerts[i].Timestamp.After(s.alerts[j].Timestamp)
+	})
+	evicted := s.prune(time.Now())
+	if evicted == 0 {
+		return events
+	}
+	log.Debug("Evicted alerts by retention policy after merge", zap.Int("evicted", evicted))
+
+	kept := make(map[string]bool, len(s.alerts))
+	for _, entry := range s.alerts {
+		kept[entry.ID] = true
+	}
+	var published []alertstore.Event
+	for i, event := range events {
+		if kept[ids[i]] {
+			published = append(published, event)
+		}
+	}
+	return published
+}
+
+// digest returns the versions of all entries. The caller must hold the read lock.
+func (s *MemberlistStore) digest() digest {
+	versions := make([]version, len(s.alerts))
+	for i, entry := range s.alerts {
+		versions[i] = entry.version()
+	}
+	return digest{Node: s.node, Versions: versions}
+}
+
+// missing returns the entries that are absent from or newer than in a remote digest.
+// The caller must hold the read lock.
+func (s *MemberlistStore) missing(remote digest) []alertEntry {
+	known := make(map[string]version, len(remote.Versions))
+	for _, v := range remote.Versions {
+		known[v.ID] = v
+	}
+	var entries []alertEntry
+	for _, entry := range s.alerts {
+		if v, ok := known[entry.ID]; !ok || newer(entry.version(), v) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// publish sends an entry to the cluster, gossiped over UDP if it is small enough
+func (s *MemberlistStore) publish(entry alertEntry) error {
+	data, err := encodeMessage(msgEntries, []alertEntry{entry})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	if s.broadcasts == nil || s.ml == nil {
+		log.Debug("Skipping broadcast - memberlist not initialized", zap.String("id", entry.ID))
+		return nil
+	}
+
+	if len(data) <= maxBroadcastSize {
+		s.broadcasts.QueueBroadcast(&broadcast{name: entry.ID, msg: data})
+		log.Debug("Broadcast alert to cluster",
+			zap.String("id", entry.ID),
+			zap.Uint64("version", entry.Version),
+			zap.Int("clusterSize", s.ml.NumMembers()))
+		return nil
+	}
+
+	log.Debug("Sending large alert to every member",
+		zap.String("id", entry.ID),
+		zap.Int("bytes", len(data)))
+	go func() {
+		for _, node := range s.ml.Members() {
+			if node.Name == s.node {
+				continue
+			}
+			if err := s.ml.SendReliable(node, data); err != nil {
+				log.Warn("Failed to send alert to member", zap.String("node", node.Name), zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+// pushEntries sends entries a node misses to it over TCP in batches
+func (s *MemberlistStore) pushEntries(nodeName string, entries []alertEntry) {
+	var target *memberlist.Node
+	for _, node := range s.ml.Members() {
+		if node.Name == nodeName {
+			target = node
+			break
+		}
+	}
+	if target == nil {
+		log.Debug("Node to push entries to is no member", zap.String("node", nodeName))
+		return
+	}
+
+	for start := 0; start < len(entries); start += deltaBatchSize {
+		batch := entries[start:min(start+deltaBatchSize, len(entries))]
+		data, err := encodeMessage(msgEntries, batch)
+		if err != nil {
+			log.Error("Failed to marshal entries for node", zap.String("node", nodeName), zap.Error(err))
+			return
+		}
+		if err := s.ml.SendReliable(target, data); err != nil {
+			log.Warn("Failed to push entries to node", zap.String("node", nodeName), zap.Error(err))
+			return
+		}
+	}
+	log.Debug("Pushed missing entries to node",
+		zap.String("node", nodeName),
+		zap.Int("entries", len(entries)))
+}
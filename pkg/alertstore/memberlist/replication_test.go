@@ -0,0 +1,224 @@
+package memberlist
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	if err := log.SetConfig(zap.NewDevelopmentConfig()); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func newNode(name string) *MemberlistStore {
+	store := NewMemberlistStore("test", 100)
+	store.node = name
+	return store
+}
+
+// replicate hands all entries of a node to the delegate of another, like a broadcast
+func replicate(t *testing.T, from, to *MemberlistStore) {
+	t.Helper()
+	data, err := encodeMessage(msgEntries, from.alerts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to.delegate.NotifyMsg(data)
+}
+
+func TestReplicationUniqueIDs(t *testing.T) {
+	a, b := newNode("openfero-a"), newNode("openfero-b")
+	alert := alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull"}}
+	// Both nodes receive the same notification
+	if err := a.SaveAlert(alert, "firing"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SaveAlert(alert, "firing"); err != nil {
+		t.Fatal(err)
+	}
+	b.alerts[0].Timestamp = a.alerts[0].Timestamp
+
+	replicate(t, a, b)
+	replicate(t, b, a)
+	replicate(t, a, b)
+	if len(a.alerts) != 2 || len(b.alerts) != 2 {
+		t.Fatalf("nodes hold %d and %d entries; want both entries on both nodes", len(a.alerts), len(b.alerts))
+	}
+	if a.alerts[0].ID == a.alerts[1].ID {
+		t.Errorf("both entries have the ID %s", a.alerts[0].ID)
+	}
+}
+
+func TestReplicationLastWriterWins(t *testing.T) {
+	a, b := newNode("openfero-a"), newNode("openfero-b")
+	alert := alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull"}}
+	if err := a.SaveAlertWithJobInfo(alert, "firing", &alertstore.JobInfo{JobName: "cleanup"}); err != nil {
+		t.Fatal(err)
+	}
+	replicate(t, a, b)
+
+	// A job status update on another node than the creating one propagates back
+	if err := b.UpdateJobInfo("cleanup", func(info *alertstore.JobInfo) { info.FailureReason = "BackoffLimitExceeded" }); err != nil {
+		t.Fatal(err)
+	}
+	stale := a.alerts[0]
+	replicate(t, b, a)
+	if a.alerts[0].JobInfo.FailureReason != "BackoffLimitExceeded" || a.alerts[0].Version != 2 {
+		t.Errorf("entry on a = %+v; want the update of b", a.alerts[0])
+	}
+
+	// An older revision does not replace a newer one
+	data, _ := encodeMessage(msgEntries, []alertEntry{stale})
+	a.delegate.NotifyMsg(data)
+	if a.alerts[0].JobInfo.FailureReason == "" {
+		t.Error("a stale revision replaced the update")
+	}
+
+	// Concurrent updates converge on the writer with the greater name
+	if err := a.UpdateJobInfo("cleanup", func(info *alertstore.JobInfo) { info.FailureReason = "from a" }); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.UpdateJobInfo("cleanup", func(info *alertstore.JobInfo) { info.FailureReason = "from b" }); err != nil {
+		t.Fatal(err)
+	}
+	replicate(t, a, b)
+	replicate(t, b, a)
+	if a.alerts[0].JobInfo.FailureReason != "from b" || b.alerts[0].JobInfo.FailureReason != "from b" {
+		t.Errorf("nodes hold %q and %q; want both to keep the update of b", a.alerts[0].JobInfo.FailureReason, b.alerts[0].JobInfo.FailureReason)
+	}
+}
+
+func TestReplicationDigest(t *testing.T) {
+	a, b := newNode("openfero-a"), newNode("openfero-b")
+	for _, name := range []string{"A", "B", "C"} {
+		if err := a.SaveAlertWithJobInfo(alertstore.Alert{Labels: map[string]string{"alertname": name}}, "firing", &alertstore.JobInfo{JobName: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.merge(a.alerts[1:])
+	if err := a.UpdateJobInfo("A", func(info *alertstore.JobInfo) { info.DryRun = true }); err != nil {
+		t.Fatal(err)
+	}
+
+	// The push/pull state only carries versions
+	state := b.delegate.LocalState(false)
+	var remote digest
+	if err := decodeMessage(state, msgDigest, &remote); err != nil {
+		t.Fatal(err)
+	}
+	if remote.Node != "openfero-b" || len(remote.Versions) != 2 {
+		t.Fatalf("digest = %+v; want the versions of the two entries of b", remote)
+	}
+
+	// b misses the newest entry and the update of the oldest one
+	missing := a.missing(remote)
+	if len(missing) != 2 || missing[0].Alert.Labels["alertname"] != "C" || missing[1].Alert.Labels["alertname"] != "A" {
+		t.Errorf("missing = %+v; want C and the updated A", missing)
+	}
+	if missing := b.missing(digest{Versions: a.digest().Versions}); len(missing) != 0 {
+		t.Errorf("a misses %d entries of b; want none", len(missing))
+	}
+}
+
+func TestReplicationLegacyMessages(t *testing.T) {
+	store := newNode("openfero-a")
+	entry := alertEntry{Alert: alertstore.Alert{Labels: map[string]string{"alertname": "Old"}}, Status: "firing", Timestamp: time.Now()}
+
+	// Nodes without IDs broadcast plain JSON entries and states
+	single, _ := json.Marshal(entry)
+	state, _ := json.Marshal([]alertEntry{entry})
+	store.delegate.NotifyMsg(single)
+	store.delegate.MergeRemoteState(state, false)
+	store.delegate.NotifyMsg(single)
+
+	if len(store.alerts) != 1 || store.alerts[0].ID != legacyID(entry) {
+		t.Errorf("entries = %+v; want one entry with a legacy ID", store.alerts)
+	}
+}
+
+func TestReplicationImportedEntries(t *testing.T) {
+	a, b := newNode("openfero-a"), newNode("openfero-b")
+	if err := a.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": "Current"}}, "firing"); err != nil {
+		t.Fatal(err)
+	}
+	old := alertstore.AlertEntry{Alert: alertstore.Alert{Labels: map[string]string{"alertname": "Old"}}, Status: "firing", Timestamp: time.Now().Add(-time.Hour)}
+	if imported, err := a.ImportAlerts([]alertstore.AlertEntry{old, old}); err != nil || imported != 1 {
+		t.Fatalf("ImportAlerts = %d, %v; want 1", imported, err)
+	}
+	if a.alerts[1].Alert.Labels["alertname"] != "Old" || a.alerts[1].ID == "" {
+		t.Fatalf("entries = %+v; want the imported entry with an ID after the newer one", a.alerts)
+	}
+
+	// Imported entries replicate like local ones
+	replicate(t, a, b)
+	if imported, _ := b.ImportAlerts([]alertstore.AlertEntry{old}); imported != 0 || len(b.alerts) != 2 {
+		t.Errorf("b holds %d entries and imported %d; want the replicated entries only", len(b.alerts), imported)
+	}
+}
+
+func TestReplicationPublishesRemoteChanges(t *testing.T) {
+	a, b := newNode("openfero-a"), newNode("openfero-b")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := b.Subscribe(ctx)
+
+	if err := a.SaveAlertWithJobInfo(alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull"}}, "firing", &alertstore.JobInfo{JobName: "cleanup"}); err != nil {
+		t.Fatal(err)
+	}
+	replicate(t, a, b)
+	if err := a.UpdateJobInfo("cleanup", func(info *alertstore.JobInfo) { info.FailureReason = "Forbidden" }); err != nil {
+		t.Fatal(err)
+	}
+	replicate(t, a, b)
+	// Known revisions are not published again
+	replicate(t, a, b)
+
+	if len(events) != 2 {
+		t.Fatalf("b published %d events; want 2", len(events))
+	}
+	if event := <-events; event.Type != alertstore.EventAlert {
+		t.Errorf("first event = %+v; want the new entry", event)
+	}
+	if event := <-events; event.Type != alertstore.EventJob || event.Entry.JobInfo.FailureReason != "Forbidden" {
+		t.Errorf("second event = %+v; want the job update", event)
+	}
+}
+
+func TestReplicationSkipsEventsOfEvictedEntries(t *testing.T) {
+	large := newNode("openfero-a")
+	small := NewMemberlistStore("test", 2)
+	small.node = "openfero-b"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := 0; i < 4; i++ {
+		if err := large.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull"}}, "firing"); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	replicate(t, large, small)
+	if len(small.alerts) != 2 {
+		t.Fatalf("small node holds %d entries; want 2", len(small.alerts))
+	}
+
+	// Every later sync pushes the entries the small node evicted again
+	events := small.Subscribe(ctx)
+	replicate(t, large, small)
+	replicate(t, large, small)
+	if len(events) != 0 {
+		t.Errorf("small node published %d events for evicted entries; want none", len(events))
+	}
+	if len(small.alerts) != 2 {
+		t.Errorf("small node holds %d entries; want 2", len(small.alerts))
+	}
+}
@@ -1,37 +1,60 @@
 package memory
 
 import (
-	"strings"
+	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/OpenFero/openfero/pkg/alertstore"
 	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
 )
 
 // MemoryStore implements alertstore.Store using in-memory storage
 type MemoryStore struct {
-	alerts  []alertstore.AlertEntry
-	mutex   sync.RWMutex
-	maxSize int
+	alerts []storedEntry
+	mutex  sync.RWMutex
+	policy alertstore.RetentionPolicy
+	nextID uint64
+	pruner *alertstore.Pruner
+	feed   alertstore.Feed
 }
 
-// NewMemoryStore creates a new in-memory alert store
+// storedEntry is an alert with a sequence number that orders entries with equal timestamps
+type storedEntry struct {
+	alertstore.AlertEntry
+	id uint64
+}
+
+// NewMemoryStore creates a new in-memory alert store that keeps the last maxSize entries
 func NewMemoryStore(maxSize int) *MemoryStore {
-	return &MemoryStore{
-		alerts:  make([]alertstore.AlertEntry, 0, maxSize),
-		maxSize: maxSize,
+	return NewMemoryStoreWithRetention(alertstore.RetentionPolicy{MaxEntries: maxSize})
+}
+
+// NewMemoryStoreWithRetention creates a new in-memory alert store with a retention policy
+func NewMemoryStoreWithRetention(policy alertstore.RetentionPolicy) *MemoryStore {
+	s := &MemoryStore{
+		alerts: make([]storedEntry, 0, policy.MaxEntries),
+		policy: policy,
 	}
+	s.pruner = alertstore.NewPruner(alertstore.DefaultPruneInterval, s.Prune)
+	return s
 }
 
-// Initialize prepares the store
+// Initialize starts the pruner if entries expire
 func (s *MemoryStore) Initialize() error {
-	return nil // Nothing to do for memory store
+	if s.policy.MaxAge > 0 {
+		s.pruner.Start()
+	}
+	return nil
 }
 
-// Close cleans up resources
+// Close stops the pruner
 func (s *MemoryStore) Close() error {
-	return nil // Nothing to do for memory store
+	s.pruner.Stop()
+	return nil
 }
 
 // SaveAlert saves an alert to the in-memory store
@@ -41,98 +64,134 @@ func (s *MemoryStore) SaveAlert(alert alertstore.Alert, status string) error {
 
 // SaveAlertWithJobInfo saves an alert to the in-memory store with job information
 func (s *MemoryStore) SaveAlertWithJobInfo(alert alertstore.Alert, status string, jobInfo *alertstore.JobInfo) error {
-	entry := alertstore.AlertEntry{
-		Alert:     alert,
-		Status:    status,
-		Timestamp: time.Now(),
-		JobInfo:   jobInfo,
-	}
-
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if len(s.alerts) < s.maxSize {
-		s.alerts = append(s.alerts, entry)
-	} else {
-		log.Debug("Alert store is full, dropping oldest alert")
-		copy(s.alerts, s.alerts[1:])
-		s.alerts[len(s.alerts)-1] = entry
+	s.nextID++
+	entry := storedEntry{
+		AlertEntry: alertstore.AlertEntry{
+			Alert:     alert,
+			Status:    status,
+			Timestamp: time.Now(),
+			JobInfo:   jobInfo,
+		},
+		id: s.nextID,
+	}
+
+	s.alerts = append(s.alerts, entry)
+	if evicted := s.prune(entry.Timestamp); evicted > 0 {
+		log.Debug("Alert store is full, dropped oldest alerts", zap.Int("evicted", evicted))
 	}
+	s.feed.Publish(alertstore.Event{Type: alertstore.EventAlert, Entry: entry.AlertEntry})
 
 	return nil
 }
 
-// GetAlerts retrieves alerts, optionally filtered by query
-func (s *MemoryStore) GetAlerts(query string, limit int) ([]alertstore.AlertEntry, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	if query == "" {
-		// Return all alerts in reverse chronological order (newest first), up to the limit
-		result := make([]alertstore.AlertEntry, 0, len(s.alerts))
-
-		// Copy the alerts in reverse order
-		for i := len(s.alerts) - 1; i >= 0; i-- {
-			result = append(result, s.alerts[i])
-			if limit > 0 && len(result) >= limit {
-				break
-			}
-		}
-		return result, nil
+// ImportAlerts adds entries with their timestamps, skipping entries the store already holds
+func (s *MemoryStore) ImportAlerts(entries []alertstore.AlertEntry) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing := make(map[string]bool, len(s.alerts))
+	for _, e := range s.alerts {
+		existing[alertstore.EntryKey(e.AlertEntry)] = true
+	}
+	fresh := alertstore.NewEntries(entries, existing)
+	for _, entry := range fresh {
+		s.nextID++
+		s.alerts = append(s.alerts, storedEntry{AlertEntry: entry, id: s.nextID})
 	}
 
-	// Filter alerts based on query
-	var results []alertstore.AlertEntry
-	for i := len(s.alerts) - 1; i >= 0; i-- {
-		if alertMatchesQuery(s.alerts[i], query) {
-			results = append(results, s.alerts[i])
-			if limit > 0 && len(results) >= limit {
-				break
-			}
-		}
+	// Oldest first, imported entries may be older than stored ones
+	sort.SliceStable(s.alerts, func(i, j int) bool {
+		return s.alerts[i].Timestamp.Before(s.alerts[j].Timestamp)
+	})
+	if evicted := s.prune(time.Now()); evicted > 0 {
+		log.Debug("Evicted alerts by retention policy after import", zap.Int("evicted", evicted))
 	}
+	return len(fresh), nil
+}
 
-	return results, nil
+// Prune applies the retention policy at now and returns the number of evicted entries
+func (s *MemoryStore) Prune(now time.Time) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.prune(now)
 }
 
-// alertMatchesQuery checks if an alert matches the search query
-func alertMatchesQuery(entry alertstore.AlertEntry, query string) bool {
-	query = strings.ToLower(query)
+// prune applies the retention policy, the caller must hold the write lock
+func (s *MemoryStore) prune(now time.Time) int {
+	entries := make([]alertstore.AlertEntry, len(s.alerts))
+	for i, e := range s.alerts {
+		entries[i] = e.AlertEntry
+	}
+	evict, evicted := s.policy.Evict(entries, now)
+	if evicted == 0 {
+		return 0
+	}
 
-	// Check alertname
-	if alertname, ok := entry.Alert.Labels["alertname"]; ok {
-		if strings.Contains(strings.ToLower(alertname), query) {
-			return true
+	kept := s.alerts[:0]
+	for i, e := range s.alerts {
+		if !evict[i] {
+			kept = append(kept, e)
 		}
 	}
+	// Clear the tail so evicted entries can be garbage collected
+	clear(s.alerts[len(kept):])
+	s.alerts = kept
+	return evicted
+}
 
-	// Check status
-	if strings.Contains(strings.ToLower(entry.Status), query) {
-		return true
-	}
+// UpdateJobInfo updates the job information of the entry that triggered jobName
+func (s *MemoryStore) UpdateJobInfo(jobName string, update func(*alertstore.JobInfo)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-	// Check labels
-	for _, value := range entry.Alert.Labels {
-		if strings.Contains(strings.ToLower(value), query) {
-			return true
+	for i := len(s.alerts) - 1; i >= 0; i-- {
+		if s.alerts[i].JobInfo != nil && s.alerts[i].JobInfo.JobName == jobName {
+			// Copy before updating, entries handed out by GetAlerts share the pointer
+			jobInfo := *s.alerts[i].JobInfo
+			update(&jobInfo)
+			s.alerts[i].JobInfo = &jobInfo
+			s.feed.Publish(alertstore.Event{Type: alertstore.EventJob, Entry: s.alerts[i].AlertEntry})
+			return nil
 		}
 	}
 
-	// Check annotations
-	for _, value := range entry.Alert.Annotations {
-		if strings.Contains(strings.ToLower(value), query) {
-			return true
-		}
+	return alertstore.ErrNotFound
+}
+
+// Subscribe returns the saved entries and job updates until ctx is done
+func (s *MemoryStore) Subscribe(ctx context.Context) <-chan alertstore.Event {
+	return s.feed.Subscribe(ctx)
+}
+
+// GetAlerts retrieves alerts newest first, optionally filtered by query
+func (s *MemoryStore) GetAlerts(query string, limit int) ([]alertstore.AlertEntry, error) {
+	page, err := s.ListAlerts(alertstore.ListOptions{Query: query, Limit: limit})
+	if err != nil {
+		return nil, err
 	}
+	return page.Alerts, nil
+}
 
-	// Check job info if present
-	if entry.JobInfo != nil {
-		if strings.Contains(strings.ToLower(entry.JobInfo.ConfigMapName), query) ||
-			strings.Contains(strings.ToLower(entry.JobInfo.JobName), query) ||
-			strings.Contains(strings.ToLower(entry.JobInfo.Image), query) {
-			return true
-		}
+// ListAlerts retrieves a page of alerts
+func (s *MemoryStore) ListAlerts(opts alertstore.ListOptions) (*alertstore.Page, error) {
+	list, err := alertstore.NewList(opts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	items := make([]alertstore.Item, 0, len(s.alerts))
+	for _, e := range s.alerts {
+		items = append(items, alertstore.Item{
+			Position: alertstore.Position{Time: e.Timestamp, ID: fmt.Sprintf("%020d", e.id)},
+			Entry:    e.AlertEntry,
+		})
 	}
+	s.mutex.RUnlock()
 
-	return false
+	list.AddAll(items)
+	return list.Page(), nil
 }
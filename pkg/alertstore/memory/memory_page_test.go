@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+)
+
+func TestListAlertsPages(t *testing.T) {
+	store := NewMemoryStore(10)
+	for i := 0; i < 5; i++ {
+		if err := store.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": fmt.Sprintf("Alert%d", i)}}, "firing"); err != nil {
+			t.Fatalf("Failed to save alert: %v", err)
+		}
+	}
+
+	for _, order := range []alertstore.Order{alertstore.OrderDesc, alertstore.OrderAsc} {
+		var names []string
+		opts := alertstore.ListOptions{Limit: 2, Order: order}
+		for pages := 1; ; pages++ {
+			page, err := store.ListAlerts(opts)
+			if err != nil {
+				t.Fatalf("ListAlerts failed: %v", err)
+			}
+			for _, entry := range page.Alerts {
+				names = append(names, entry.Alert.Labels["alertname"])
+			}
+			if page.NextCursor == "" {
+				if pages != 3 {
+					t.Errorf("got %d pages in order %s; want 3", pages, order)
+				}
+				break
+			}
+			opts.Cursor = page.NextCursor
+		}
+
+		want := "[Alert4 Alert3 Alert2 Alert1 Alert0]"
+		if order == alertstore.OrderAsc {
+			want = "[Alert0 Alert1 Alert2 Alert3 Alert4]"
+		}
+		if got := fmt.Sprint(names); got != want {
+			t.Errorf("pages in order %s = %s; want %s", order, got, want)
+		}
+	}
+
+	// Nothing is newer than now
+	page, err := store.ListAlerts(alertstore.ListOptions{From: time.Now()})
+	if err != nil || len(page.Alerts) != 0 {
+		t.Errorf("ListAlerts from now = %d alerts (err %v); want none", len(page.Alerts), err)
+	}
+}
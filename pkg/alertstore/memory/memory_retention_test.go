@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	// Initialize logger before running tests
+	if err := log.SetConfig(zap.NewDevelopmentConfig()); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	store := NewMemoryStoreWithRetention(alertstore.RetentionPolicy{MaxEntries: 4, MaxPerAlertname: 2, MaxAge: time.Hour})
+	save := func(alertname string) {
+		if err := store.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": alertname}}, "firing"); err != nil {
+			t.Fatalf("Failed to save alert: %v", err)
+		}
+	}
+	save("Rare")
+	for i := 0; i < 5; i++ {
+		save("Noisy")
+	}
+
+	entries, _ := store.GetAlerts("", 0)
+	if len(entries) != 3 || entries[2].Alert.Labels["alertname"] != "Rare" {
+		t.Fatalf("GetAlerts = %+v; want 2 Noisy entries and the Rare one", entries)
+	}
+
+	// The pruner evicts entries once they are older than the maximum age
+	if evicted := store.Prune(time.Now().Add(2 * time.Hour)); evicted != 3 {
+		t.Errorf("Prune evicted %d entries; want 3", evicted)
+	}
+	if entries, _ := store.GetAlerts("", 0); len(entries) != 0 {
+		t.Errorf("GetAlerts after prune returned %d entries; want none", len(entries))
+	}
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	source := NewMemoryStore(10)
+	for _, alertname := range []string{"DiskFull", "KubeQuotaExceeded", "DiskFull"} {
+		if err := source.SaveAlertWithJobInfo(alertstore.Alert{Labels: map[string]string{"alertname": alertname}}, "firing", &alertstore.JobInfo{JobName: alertname}); err != nil {
+			t.Fatalf("Failed to save alert: %v", err)
+		}
+	}
+
+	var snapshot bytes.Buffer
+	written, err := alertstore.WriteSnapshot(&snapshot, source, alertstore.ListOptions{Query: "diskfull"})
+	if err != nil || written != 2 {
+		t.Fatalf("WriteSnapshot = %d, %v; want the 2 DiskFull entries", written, err)
+	}
+	if lines := strings.Count(snapshot.String(), "\n"); lines != 2 {
+		t.Errorf("snapshot has %d lines; want 2", lines)
+	}
+
+	// The target holds a newer entry, imported entries are sorted in before it
+	target := NewMemoryStore(10)
+	if err := target.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": "Newest"}}, "firing"); err != nil {
+		t.Fatalf("Failed to save alert: %v", err)
+	}
+	result, err := alertstore.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), target)
+	if err != nil || result != (alertstore.ImportResult{Imported: 2}) {
+		t.Fatalf("ImportSnapshot = %+v, %v; want 2 imported entries", result, err)
+	}
+	entries, _ := target.GetAlerts("", 0)
+	if got := fmt.Sprint(alertnames(entries)); got != "[Newest DiskFull DiskFull]" {
+		t.Errorf("entries after import = %s; want the imported entries after the newest one", got)
+	}
+	source.mutex.RLock()
+	want := source.alerts[0].Timestamp
+	source.mutex.RUnlock()
+	if !entries[2].Timestamp.Equal(want) || entries[2].JobInfo == nil || entries[2].JobInfo.JobName != "DiskFull" {
+		t.Errorf("imported entry = %+v; want the timestamp and job info of the exported one", entries[2])
+	}
+
+	// Importing the same snapshot again adds nothing
+	result, err = alertstore.ImportSnapshot(bytes.NewReader(snapshot.Bytes()), target)
+	if err != nil || result != (alertstore.ImportResult{Duplicates: 2}) {
+		t.Errorf("second ImportSnapshot = %+v, %v; want 2 duplicates", result, err)
+	}
+}
+
+func TestImportSnapshotValidation(t *testing.T) {
+	store := NewMemoryStore(10)
+	for _, snapshot := range []string{
+		"not json\n",
+		`{"alert":{"labels":{"alertname":"NoTime"}},"status":"firing"}` + "\n",
+	} {
+		if _, err := alertstore.ImportSnapshot(strings.NewReader(snapshot), store); !errors.Is(err, alertstore.ErrInvalidSnapshot) {
+			t.Errorf("ImportSnapshot(%q) = %v; want ErrInvalidSnapshot", snapshot, err)
+		}
+	}
+
+	// Blank lines are skipped, the retention policy applies to imported entries
+	store = NewMemoryStore(1)
+	old := time.Now().Add(-time.Hour).Format(time.RFC3339Nano)
+	snapshot := fmt.Sprintf(`{"alert":{"labels":{"alertname":"A"}},"status":"firing","timestamp":%q}`+"\n\n"+
+		`{"alert":{"labels":{"alertname":"B"}},"status":"resolved","timestamp":%q}`+"\n", old, old)
+	if result, err := alertstore.ImportSnapshot(strings.NewReader(snapshot), store); err != nil || result.Imported != 2 {
+		t.Fatalf("ImportSnapshot = %+v, %v; want 2 imported entries", result, err)
+	}
+	if entries, _ := store.GetAlerts("", 0); len(entries) != 1 {
+		t.Errorf("store holds %d entries; want 1", len(entries))
+	}
+}
+
+func TestSnapshotterRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.ndjson")
+	source := NewMemoryStore(10)
+	snapshotter := alertstore.NewSnapshotter(path, time.Hour, source)
+	snapshotter.Start()
+	if err := source.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull"}}, "firing"); err != nil {
+		t.Fatalf("Failed to save alert: %v", err)
+	}
+	// Stopping writes a last snapshot
+	snapshotter.Stop()
+	snapshotter.Stop()
+
+	target := NewMemoryStore(10)
+	result, err := alertstore.RestoreSnapshot(path, target)
+	if err != nil || result.Imported != 1 {
+		t.Errorf("RestoreSnapshot = %+v, %v; want the saved entry", result, err)
+	}
+	if matches, _ := filepath.Glob(path + ".*"); len(matches) != 0 {
+		t.Errorf("temporary files were left behind: %v", matches)
+	}
+}
+
+func alertnames(entries []alertstore.AlertEntry) []string {
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Alert.Labels["alertname"])
+	}
+	return names
+}
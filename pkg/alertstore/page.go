@@ -0,0 +1,204 @@
+package alertstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Order is the sort order of listed alerts
+type Order string
+
+const (
+	OrderDesc Order = "desc" // Newest first, the default
+	OrderAsc  Order = "asc"  // Oldest first
+)
+
+// ErrInvalidCursor is returned for a cursor that was not returned by a previous page of the same order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ListOptions selects a page of alerts
+type ListOptions struct {
+	// Query filters the alerts, see ParseQuery
+	Query string
+	// Limit is the maximum number of alerts of the page, zero returns all
+	Limit int
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+	// From is the time of the oldest alert, inclusive
+	From time.Time
+	// To is the time of the newest alert, exclusive
+	To time.Time
+	// Order defaults to newest first
+	Order Order
+}
+
+// Page is a page of alerts
+type Page struct {
+	// Alerts of the page
+	Alerts []AlertEntry `json:"alerts"`
+	// NextCursor continues with the next page, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Position orders entries by time. ID is a store specific key that orders entries with equal times.
+type Position struct {
+	Time time.Time
+	ID   string
+}
+
+// Before reports whether p is ordered before o
+func (p Position) Before(o Position) bool {
+	if !p.Time.Equal(o.Time) {
+		return p.Time.Before(o.Time)
+	}
+	return p.ID < o.ID
+}
+
+// Item is an entry with its position
+type Item struct {
+	Position Position
+	Entry    AlertEntry
+}
+
+// cursor is the encoded position of the last entry of a page
+type cursor struct {
+	Time  int64  `json:"t"`
+	ID    string `json:"i"`
+	Order Order  `json:"o"`
+}
+
+// List collects a page of alerts. Stores walk their entries in the order of the list,
+// skipping to the window and cursor where they can, and Add them until Add returns false.
+type List struct {
+	Query *Query
+	Limit int
+	// After is the position of the last entry of the previous page, nil on the first page
+	After *Position
+	From  time.Time
+	To    time.Time
+	Order Order
+
+	page Page
+	last Position
+}
+
+// NewList parses the options, relative times of the query are resolved against now
+func NewList(opts ListOptions, now time.Time) (*List, error) {
+	q, err := ParseQuery(opts.Query, now)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("limit must not be negative")
+	}
+	order := opts.Order
+	if order == "" {
+		order = OrderDesc
+	}
+	if order != OrderDesc && order != OrderAsc {
+		return nil, fmt.Errorf("unknown order %q, must be %s or %s", order, OrderAsc, OrderDesc)
+	}
+
+	l := &List{Query: q, Limit: opts.Limit, From: opts.From, To: opts.To, Order: order}
+	// since= of the query narrows the window like from
+	if q.Since.After(l.From) {
+		l.From = q.Since
+	}
+	if opts.Cursor != "" {
+		after, err := decodeCursor(opts.Cursor, order)
+		if err != nil {
+			return nil, err
+		}
+		l.After = &after
+	}
+	return l, nil
+}
+
+// Desc reports whether the list is ordered newest first
+func (l *List) Desc() bool {
+	return l.Order != OrderAsc
+}
+
+// Add adds an entry if it matches and returns whether the store should continue with the next entry
+func (l *List) Add(pos Position, entry AlertEntry) bool {
+	// Entries come in order, so everything after the end of the window is outside of it too
+	if l.Desc() && !l.From.IsZero() && pos.Time.Before(l.From) {
+		return false
+	}
+	if !l.Desc() && !l.To.IsZero() && !pos.Time.Before(l.To) {
+		return false
+	}
+	if !l.Contains(pos) || !l.Query.Matches(entry) {
+		return true
+	}
+	if l.Limit > 0 && len(l.page.Alerts) >= l.Limit {
+		// There is at least one more entry
+		l.page.NextCursor = encodeCursor(l.last, l.Order)
+		return false
+	}
+	l.page.Alerts = append(l.page.Alerts, entry)
+	l.last = pos
+	return true
+}
+
+// Contains reports whether a position is inside the window and after the cursor
+func (l *List) Contains(pos Position) bool {
+	if !l.From.IsZero() && pos.Time.Before(l.From) {
+		return false
+	}
+	if !l.To.IsZero() && !pos.Time.Before(l.To) {
+		return false
+	}
+	if l.After != nil {
+		if l.Desc() {
+			return pos.Before(*l.After)
+		}
+		return l.After.Before(pos)
+	}
+	return true
+}
+
+// AddAll sorts items in the order of the list and adds them, for stores that keep their entries in memory
+func (l *List) AddAll(items []Item) {
+	sort.Slice(items, func(i, j int) bool {
+		if l.Desc() {
+			return items[j].Position.Before(items[i].Position)
+		}
+		return items[i].Position.Before(items[j].Position)
+	})
+	for _, item := range items {
+		if !l.Add(item.Position, item.Entry) {
+			return
+		}
+	}
+}
+
+// Page returns the collected page
+func (l *List) Page() *Page {
+	page := l.page
+	if page.Alerts == nil {
+		page.Alerts = []AlertEntry{}
+	}
+	return &page
+}
+
+func encodeCursor(pos Position, order Order) string {
+	data, _ := json.Marshal(cursor{Time: pos.Time.UnixNano(), ID: pos.ID, Order: order})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string, order Order) (Position, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Position{}, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Order != order {
+		return Position{}, ErrInvalidCursor
+	}
+	return Position{Time: time.Unix(0, c.Time), ID: c.ID}, nil
+}
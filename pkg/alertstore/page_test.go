@@ -0,0 +1,115 @@
+package alertstore
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func testItems(start time.Time, n int) []Item {
+	var items []Item
+	for i := 0; i < n; i++ {
+		// Pairs of entries share a timestamp, the ID orders them
+		at := start.Add(time.Duration(i/2) * time.Minute)
+		items = append(items, Item{
+			Position: Position{Time: at, ID: fmt.Sprintf("%02d", i)},
+			Entry:    AlertEntry{Alert: Alert{Labels: map[string]string{"alertname": fmt.Sprintf("A%d", i)}}, Status: "firing", Timestamp: at},
+		})
+	}
+	return items
+}
+
+func pageNames(page *Page) []string {
+	var names []string
+	for _, entry := range page.Alerts {
+		names = append(names, entry.Alert.Labels["alertname"])
+	}
+	return names
+}
+
+// walk lists every page and returns the alertnames in order
+func walk(t *testing.T, items []Item, opts ListOptions) []string {
+	t.Helper()
+	var names []string
+	for pages := 0; pages < 20; pages++ {
+		list, err := NewList(opts, time.Now())
+		if err != nil {
+			t.Fatalf("NewList failed: %v", err)
+		}
+		list.AddAll(append([]Item(nil), items...))
+		page := list.Page()
+		names = append(names, pageNames(page)...)
+		if page.NextCursor == "" {
+			return names
+		}
+		opts.Cursor = page.NextCursor
+	}
+	t.Fatal("too many pages")
+	return nil
+}
+
+func TestListPagesThroughEntries(t *testing.T) {
+	start := time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+	items := testItems(start, 7)
+
+	got := fmt.Sprint(walk(t, items, ListOptions{Limit: 2}))
+	if want := "[A6 A5 A4 A3 A2 A1 A0]"; got != want {
+		t.Errorf("newest first = %s; want %s", got, want)
+	}
+	got = fmt.Sprint(walk(t, items, ListOptions{Limit: 3, Order: OrderAsc}))
+	if want := "[A0 A1 A2 A3 A4 A5 A6]"; got != want {
+		t.Errorf("oldest first = %s; want %s", got, want)
+	}
+
+	// From is inclusive, To is exclusive
+	window := ListOptions{Limit: 1, From: start.Add(time.Minute), To: start.Add(3 * time.Minute)}
+	got = fmt.Sprint(walk(t, items, window))
+	if want := "[A5 A4 A3 A2]"; got != want {
+		t.Errorf("window = %s; want %s", got, want)
+	}
+	window.Order = OrderAsc
+	got = fmt.Sprint(walk(t, items, window))
+	if want := "[A2 A3 A4 A5]"; got != want {
+		t.Errorf("window oldest first = %s; want %s", got, want)
+	}
+
+	got = fmt.Sprint(walk(t, items, ListOptions{Query: `{alertname=~"A[135]"}`, Limit: 2}))
+	if want := "[A5 A3 A1]"; got != want {
+		t.Errorf("query = %s; want %s", got, want)
+	}
+}
+
+func TestListLastPageHasNoCursor(t *testing.T) {
+	list, _ := NewList(ListOptions{Limit: 2}, time.Now())
+	list.AddAll(testItems(time.Now(), 2))
+	if page := list.Page(); len(page.Alerts) != 2 || page.NextCursor != "" {
+		t.Errorf("page = %v with cursor %q; want 2 alerts without cursor", pageNames(page), page.NextCursor)
+	}
+
+	list, _ = NewList(ListOptions{}, time.Now())
+	if page := list.Page(); page.Alerts == nil {
+		t.Error("an empty page must have an empty list of alerts")
+	}
+}
+
+func TestNewListValidation(t *testing.T) {
+	list, _ := NewList(ListOptions{Limit: 1}, time.Now())
+	list.AddAll(testItems(time.Now(), 2))
+	cursor := list.Page().NextCursor
+
+	for _, opts := range []ListOptions{
+		{Limit: -1},
+		{Order: "newest"},
+		{Cursor: "not-a-cursor"},
+		// A cursor continues in the order it was created for
+		{Cursor: cursor, Order: OrderAsc},
+		{Query: `{alertname="A"`},
+	} {
+		if _, err := NewList(opts, time.Now()); err == nil {
+			t.Errorf("NewList(%+v) succeeded; want an error", opts)
+		}
+	}
+	if _, err := NewList(ListOptions{Cursor: cursor}, time.Now()); err != nil {
+		t.Errorf("NewList with a valid cursor failed: %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+package alertstore
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MatchType is the operator of a label matcher
+type MatchType string
+
+const (
+	MatchEqual     MatchType = "="
+	MatchNotEqual  MatchType = "!="
+	MatchRegexp    MatchType = "=~"
+	MatchNotRegexp MatchType = "!~"
+)
+
+// Job states that can be filtered with job=<state>
+const (
+	JobStateNone      = "none"      // No job was triggered
+	JobStateCreated   = "created"   // A job was created
+	JobStateFailed    = "failed"    // The job could not be created or failed
+	JobStateSucceeded = "succeeded" // The job completed successfully
+	JobStateSkipped   = "skipped"   // The job was skipped, e.g. rate-limited or rejected
+	JobStateDryRun    = "dryrun"    // The job was only validated with a server-side dry-run
+	JobStatePending   = "pending"   // The job waits for approval
+	JobStateCancelled = "cancelled" // The job was deleted because its alert resolved
+)
+
+var jobStates = []string{JobStateNone, JobStateCreated, JobStateFailed, JobStateSucceeded, JobStateSkipped, JobStateDryRun, JobStatePending, JobStateCancelled}
+
+// Matcher matches a label against a value like a PromQL label matcher
+type Matcher struct {
+	Name  string
+	Type  MatchType
+	Value string
+	re    *regexp.Regexp
+}
+
+// Matches checks a label value, a missing label matches as empty value
+func (m Matcher) Matches(value string) bool {
+	switch m.Type {
+	case MatchEqual:
+		return value == m.Value
+	case MatchNotEqual:
+		return value != m.Value
+	case MatchRegexp:
+		return m.re.MatchString(value)
+	case MatchNotRegexp:
+		return !m.re.MatchString(value)
+	}
+	return false
+}
+
+// Query is a parsed alert search. All parts must match.
+//
+//	{alertname="X", severity=~"crit.*", namespace!="dev"} status=firing since=2h job=failed
+//
+// Words that are not a label selector or a filter are a case-insensitive substring search,
+// so a plain text query behaves as before.
+type Query struct {
+	// Matchers are the label matchers of the {...} selector
+	Matchers []Matcher
+	// Status matches the status case-insensitively
+	Status string
+	// Since is the time of the oldest entry to return
+	Since time.Time
+	// Job is one of the job states
+	Job string
+	// Text is searched in the status, labels, annotations and job
+	Text string
+}
+
+// ParseQuery parses a search query, relative times like since=2h are resolved against now
+func ParseQuery(input string, now time.Time) (*Query, error) {
+	q := &Query{}
+	rest := strings.TrimSpace(input)
+
+	if strings.HasPrefix(rest, "{") {
+		end := closingBrace(rest)
+		if end < 0 {
+			return nil, fmt.Errorf("label selector is missing a closing }")
+		}
+		matchers, err := parseMatchers(rest[1:end])
+		if err != nil {
+			return nil, err
+		}
+		q.Matchers = matchers
+		rest = rest[end+1:]
+	}
+
+	var text []string
+	for _, word := range strings.Fields(rest) {
+		key, value, ok := strings.Cut(word, "=")
+		if !ok {
+			text = append(text, word)
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "status":
+			q.Status = value
+		case "since":
+			since, err := parseSince(value, now)
+			if err != nil {
+				return nil, err
+			}
+			q.Since = since
+		case "job":
+			// Other values stay a text search, like before job states existed
+			if !slices.Contains(jobStates, strings.ToLower(value)) {
+				text = append(text, word)
+				continue
+			}
+			q.Job = strings.ToLower(value)
+		default:
+			text = append(text, word)
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// IsEmpty reports whether the query matches every entry
+func (q *Query) IsEmpty() bool {
+	return len(q.Matchers) == 0 && q.Status == "" && q.Since.IsZero() && q.Job == "" && q.Text == ""
+}
+
+// Matches checks whether an entry matches every part of the query
+func (q *Query) Matches(entry AlertEntry) bool {
+	for _, m := range q.Matchers {
+		if !m.Matches(entry.Alert.Labels[m.Name]) {
+			return false
+		}
+	}
+	if q.Status != "" && !strings.EqualFold(entry.Status, q.Status) {
+		return false
+	}
+	if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+		return false
+	}
+	if q.Job != "" && JobState(entry.JobInfo) != q.Job {
+		return false
+	}
+	if q.Text != "" && !MatchesText(entry, q.Text) {
+		return false
+	}
+	return true
+}
+
+// EqualMatcher returns the value of an equality matcher on the label, used by backends with label indexes
+func (q *Query) EqualMatcher(name string) (string, bool) {
+	for _, m := range q.Matchers {
+		if m.Name == name && m.Type == MatchEqual {
+			return m.Value, true
+		}
+	}
+	return "", false
+}
+
+// JobState returns the job state of an entry
+func JobState(jobInfo *JobInfo) string {
+	switch {
+	case jobInfo == nil:
+		return JobStateNone
+	case !jobInfo.CancelledAt.IsZero():
+		return JobStateCancelled
+	case jobInfo.SkipReason != "":
+		return JobStateSkipped
+	case jobInfo.FailureReason != "" || jobInfo.Outcome == JobOutcomeFailed:
+		return JobStateFailed
+	case jobInfo.Outcome == JobOutcomeSucceeded:
+		return JobStateSucceeded
+	case jobInfo.DryRun:
+		return JobStateDryRun
+	case jobInfo.Approval != nil && jobInfo.Approval.State == "pending":
+		return JobStatePending
+	case jobInfo.Approval != nil && jobInfo.Approval.State != "approved":
+		// A rejected or expired job never ran
+		return JobStateSkipped
+	default:
+		return JobStateCreated
+	}
+}
+
+// parseMatchers parses the comma separated matchers inside a label selector
+func parseMatchers(selector string) ([]Matcher, error) {
+	var matchers []Matcher
+	rest := strings.TrimSpace(selector)
+	for rest != "" {
+		// Label name
+		i := 0
+		for i < len(rest) && (rest[i] == '_' || unicode.IsLetter(rune(rest[i])) || (i > 0 && unicode.IsDigit(rune(rest[i])))) {
+			i++
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("expected a label name at %q", rest)
+		}
+		m := Matcher{Name: rest[:i]}
+		rest = strings.TrimSpace(rest[i:])
+
+		// Operator, the two character operators are checked first
+		for _, op := range []MatchType{MatchNotEqual, MatchRegexp, MatchNotRegexp, MatchEqual} {
+			if strings.HasPrefix(rest, string(op)) {
+				m.Type = op
+				rest = strings.TrimSpace(rest[len(op):])
+				break
+			}
+		}
+		if m.Type == "" {
+			return nil, fmt.Errorf("expected one of =, !=, =~, !~ after label %s", m.Name)
+		}
+
+		// Quoted value
+		value, remaining, err := unquotePrefix(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of label %s: %w", m.Name, err)
+		}
+		m.Value = value
+		if m.Type == MatchRegexp || m.Type == MatchNotRegexp {
+			// Anchored like in PromQL
+			m.re, err = regexp.Compile("^(?:" + value + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression for label %s: %w", m.Name, err)
+			}
+		}
+		matchers = append(matchers, m)
+
+		rest = strings.TrimSpace(remaining)
+		if rest == "" {
+			break
+		}
+		if rest[0] != ',' {
+			return nil, fmt.Errorf("expected , between matchers at %q", rest)
+		}
+		rest = strings.TrimSpace(rest[1:])
+	}
+	return matchers, nil
+}
+
+// unquotePrefix reads a double or single quoted string at the start of s
+func unquotePrefix(s string) (string, string, error) {
+	if s == "" || (s[0] != '"' && s[0] != '\'') {
+		return "", "", fmt.Errorf("expected a quoted value")
+	}
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			if quote == '\'' {
+				return strings.ReplaceAll(s[1:i], `\'`, `'`), s[i+1:], nil
+			}
+			value, err := strconv.Unquote(s[:i+1])
+			return value, s[i+1:], err
+		}
+	}
+	return "", "", fmt.Errorf("missing closing quote")
+}
+
+// closingBrace returns the index of the } that closes the selector, skipping quoted values
+func closingBrace(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0 && s[i] == '\\':
+			i++
+		case quote != 0 && s[i] == quote:
+			quote = 0
+		case quote == 0 && (s[i] == '"' || s[i] == '\''):
+			quote = s[i]
+		case quote == 0 && s[i] == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseSince parses a duration relative to now, e.g. 2h or 7d, or an RFC 3339 timestamp
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid since %q, use a duration like 2h or 7d or an RFC 3339 time", value)
+	}
+	return now.Add(-d), nil
+}
@@ -0,0 +1,101 @@
+package alertstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQueryMatches(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	entry := AlertEntry{
+		Alert: Alert{
+			Labels:      map[string]string{"alertname": "DiskFull", "severity": "critical", "namespace": "prod"},
+			Annotations: map[string]string{"summary": "Disk almost full"},
+		},
+		Status:    "firing",
+		Timestamp: now.Add(-time.Hour),
+		JobInfo:   &JobInfo{JobName: "cleanup-abcde", FailureReason: "Forbidden"},
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"disk", true},
+		{"almost full", true},
+		{`{alertname="DiskFull"}`, true},
+		{`{alertname="Disk"}`, false},
+		{`{severity=~"crit.*", namespace!="dev"}`, true},
+		// Regular expressions are anchored
+		{`{severity=~"crit"}`, false},
+		{`{severity!~"warn|info"}`, true},
+		// A missing label matches as empty value
+		{`{team!="sre"}`, true},
+		{`{team=""}`, true},
+		{`{alertname='DiskFull'}`, true},
+		{`{summary="Disk almost full"} `, false},
+		{"status=FIRING", true},
+		{"status=resolved", false},
+		{"since=2h", true},
+		{"since=30m", false},
+		{"since=1d", true},
+		{"since=2025-06-01T11:30:00Z", false},
+		{"job=failed", true},
+		{"job=created", false},
+		// Unknown job states are searched as text
+		{"job=running", false},
+		{`{alertname="DiskFull"} status=firing job=failed cleanup`, true},
+		{`{alertname="DiskFull"} status=firing nothing`, false},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query, now)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) failed: %v", tt.query, err)
+			continue
+		}
+		if got := q.Matches(entry); got != tt.want {
+			t.Errorf("ParseQuery(%q).Matches = %v; want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		`{alertname="DiskFull"`,
+		`{alertname="DiskFull}`,
+		`{alertname=DiskFull}`,
+		`{alertname~"Disk"}`,
+		`{severity=~"crit("}`,
+		`{alertname="A" severity="B"}`,
+		"since=yesterday",
+	} {
+		if _, err := ParseQuery(query, time.Now()); err == nil {
+			t.Errorf("ParseQuery(%q) succeeded; want an error", query)
+		}
+	}
+}
+
+func TestJobState(t *testing.T) {
+	tests := []struct {
+		jobInfo *JobInfo
+		want    string
+	}{
+		{nil, JobStateNone},
+		{&JobInfo{JobName: "job"}, JobStateCreated},
+		{&JobInfo{FailureReason: "Forbidden"}, JobStateFailed},
+		{&JobInfo{SkipReason: "rate limited"}, JobStateSkipped},
+		{&JobInfo{DryRun: true}, JobStateDryRun},
+		{&JobInfo{Approval: &Approval{State: "pending"}}, JobStatePending},
+		{&JobInfo{Approval: &Approval{State: "rejected"}}, JobStateSkipped},
+		{&JobInfo{Approval: &Approval{State: "approved"}}, JobStateCreated},
+		{&JobInfo{JobName: "job", CancelledAt: time.Now()}, JobStateCancelled},
+		{&JobInfo{JobName: "job", Outcome: JobOutcomeSucceeded}, JobStateSucceeded},
+		{&JobInfo{JobName: "job", Outcome: JobOutcomeFailed}, JobStateFailed},
+	}
+	for _, tt := range tests {
+		if got := JobState(tt.jobInfo); got != tt.want {
+			t.Errorf("JobState(%+v) = %s; want %s", tt.jobInfo, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package alertstore
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/metadata"
+	"go.uber.org/zap"
+)
+
+// Retention rules, used as the rule label of the eviction metric
+const (
+	RetentionRuleAge       = "age"       // The entry is older than the maximum age
+	RetentionRuleAlertname = "alertname" // The alertname has more entries than its quota
+	RetentionRuleCount     = "count"     // The store has more entries than the global count
+)
+
+// DefaultPruneInterval is how often a Pruner applies the maximum age
+const DefaultPruneInterval = time.Minute
+
+// RetentionPolicy limits the entries of a store, zero disables a limit
+type RetentionPolicy struct {
+	// MaxAge is the age after which entries are evicted
+	MaxAge time.Duration
+	// MaxEntries is the number of entries of the store
+	MaxEntries int
+	// MaxPerAlertname is the number of entries of each alertname, so a noisy alert
+	// does not push the history of rare alerts out of the store
+	MaxPerAlertname int
+}
+
+// Evict returns which entries the policy evicts at now and counts them in the eviction metric.
+// The rules are applied in the order age, alertname quota and count, each one to the entries the
+// previous rules kept, and always evict the oldest entries first. The entries may be in any order.
+func (p RetentionPolicy) Evict(entries []AlertEntry, now time.Time) ([]bool, int) {
+	// Walk the entries newest first, so the counters below reach their limits at the oldest entries
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return entries[order[a]].Timestamp.After(entries[order[b]].Timestamp)
+	})
+
+	evict := make([]bool, len(entries))
+	evicted := make(map[string]int)
+	perAlertname := make(map[string]int)
+	kept := 0
+	for _, i := range order {
+		entry := entries[i]
+		alertname := entry.Alert.Labels["alertname"]
+		rule := ""
+		switch {
+		case p.MaxAge > 0 && now.Sub(entry.Timestamp) > p.MaxAge:
+			rule = RetentionRuleAge
+		case p.MaxPerAlertname > 0 && perAlertname[alertname] >= p.MaxPerAlertname:
+			rule = RetentionRuleAlertname
+		case p.MaxEntries > 0 && kept >= p.MaxEntries:
+			rule = RetentionRuleCount
+		}
+		if rule != "" {
+			evict[i] = true
+			evicted[rule]++
+			continue
+		}
+		perAlertname[alertname]++
+		kept++
+	}
+
+	for rule, n := range evicted {
+		metadata.AlertStoreEvictionsTotal.WithLabelValues(rule).Add(float64(n))
+	}
+	return evict, len(entries) - kept
+}
+
+// Pruner periodically applies the retention policy of a store, the memory and memberlist stores share it
+type Pruner struct {
+	interval time.Duration
+	prune    func(now time.Time) int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewPruner creates a pruner that calls prune every interval. prune returns the number of evicted entries.
+func NewPruner(interval time.Duration, prune func(now time.Time) int) *Pruner {
+	return &Pruner{interval: interval, prune: prune}
+}
+
+// Start starts pruning in the background
+func (p *Pruner) Start() {
+	p.stop = make(chan struct{})
+	p.wg.Add(1)
+	go p.loop()
+}
+
+// Stop stops pruning and waits for a running prune to finish
+func (p *Pruner) Stop() {
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	p.wg.Wait()
+	p.stop = nil
+}
+
+func (p *Pruner) loop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case now := <-ticker.C:
+			if evicted := p.prune(now); evicted > 0 {
+				log.Debug("Pruned alert store", zap.Int("evicted", evicted))
+			}
+		}
+	}
+}
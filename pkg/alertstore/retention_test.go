@@ -0,0 +1,93 @@
+package alertstore
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/metadata"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRetentionPolicyEvict(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	// Oldest first, like the memory store keeps them
+	var entries []AlertEntry
+	add := func(alertname string, age time.Duration) {
+		entries = append(entries, AlertEntry{Alert: Alert{Labels: map[string]string{"alertname": alertname}}, Timestamp: now.Add(-age)})
+	}
+	add("Rare", 3*time.Hour)
+	add("Noisy", 90*time.Minute)
+	add("Rare", 80*time.Minute)
+	for i := 5; i > 0; i-- {
+		add("Noisy", time.Duration(i)*time.Minute)
+	}
+
+	age := testutil.ToFloat64(metadata.AlertStoreEvictionsTotal.WithLabelValues(RetentionRuleAge))
+	quota := testutil.ToFloat64(metadata.AlertStoreEvictionsTotal.WithLabelValues(RetentionRuleAlertname))
+	count := testutil.ToFloat64(metadata.AlertStoreEvictionsTotal.WithLabelValues(RetentionRuleCount))
+
+	policy := RetentionPolicy{MaxAge: 2 * time.Hour, MaxPerAlertname: 3, MaxEntries: 3}
+	evict, evicted := policy.Evict(entries, now)
+
+	// The first Rare is too old, three Noisy exceed the quota, the last Rare exceeds the count
+	want := []bool{true, true, true, true, true, false, false, false}
+	for i := range want {
+		if evict[i] != want[i] {
+			t.Errorf("evict = %v; want %v", evict, want)
+			break
+		}
+	}
+	if evicted != 5 {
+		t.Errorf("evicted = %d; want 5", evicted)
+	}
+
+	if got := testutil.ToFloat64(metadata.AlertStoreEvictionsTotal.WithLabelValues(RetentionRuleAge)) - age; got != 1 {
+		t.Errorf("age evictions = %v; want 1", got)
+	}
+	if got := testutil.ToFloat64(metadata.AlertStoreEvictionsTotal.WithLabelValues(RetentionRuleAlertname)) - quota; got != 3 {
+		t.Errorf("alertname evictions = %v; want 3", got)
+	}
+	if got := testutil.ToFloat64(metadata.AlertStoreEvictionsTotal.WithLabelValues(RetentionRuleCount)) - count; got != 1 {
+		t.Errorf("count evictions = %v; want 1", got)
+	}
+}
+
+func TestRetentionPolicyQuotaKeepsRareAlerts(t *testing.T) {
+	now := time.Now()
+	entries := []AlertEntry{{Alert: Alert{Labels: map[string]string{"alertname": "Rare"}}, Timestamp: now.Add(-time.Hour)}}
+	for i := 0; i < 10; i++ {
+		entries = append(entries, AlertEntry{Alert: Alert{Labels: map[string]string{"alertname": "Noisy"}}, Timestamp: now.Add(-time.Duration(i) * time.Second)})
+	}
+
+	evict, _ := RetentionPolicy{MaxEntries: 5, MaxPerAlertname: 4}.Evict(entries, now)
+	if evict[0] {
+		t.Error("the quota of the noisy alert should leave room for the rare alert")
+	}
+	if evict, evicted := (RetentionPolicy{}).Evict(entries, now); evicted != 0 || evict[0] {
+		t.Errorf("an empty policy evicted %d entries", evicted)
+	}
+}
+
+func TestPruner(t *testing.T) {
+	var calls atomic.Int32
+	pruner := NewPruner(time.Millisecond, func(time.Time) int {
+		calls.Add(1)
+		return 0
+	})
+	pruner.Start()
+	for deadline := time.Now().Add(5 * time.Second); calls.Load() < 2 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	pruner.Stop()
+	pruner.Stop()
+
+	n := calls.Load()
+	if n < 2 {
+		t.Fatalf("prune was called %d times; want at least 2", n)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if calls.Load() != n {
+		t.Error("prune was called after Stop")
+	}
+}
@@ -0,0 +1,29 @@
+package alertstore
+
+import "strings"
+
+// MatchesText checks case-insensitively whether the status, a label, an annotation
+// or the job of an entry contains the query
+func MatchesText(entry AlertEntry, query string) bool {
+	query = strings.ToLower(query)
+
+	if strings.Contains(strings.ToLower(entry.Status), query) {
+		return true
+	}
+	for _, value := range entry.Alert.Labels {
+		if strings.Contains(strings.ToLower(value), query) {
+			return true
+		}
+	}
+	for _, value := range entry.Alert.Annotations {
+		if strings.Contains(strings.ToLower(value), query) {
+			return true
+		}
+	}
+	if entry.JobInfo != nil {
+		return strings.Contains(strings.ToLower(entry.JobInfo.ConfigMapName), query) ||
+			strings.Contains(strings.ToLower(entry.JobInfo.JobName), query) ||
+			strings.Contains(strings.ToLower(entry.JobInfo.Image), query)
+	}
+	return false
+}
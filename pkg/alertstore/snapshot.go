@@ -0,0 +1,222 @@
+package alertstore
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// snapshotPageSize is the number of entries read from the store per page while exporting
+const snapshotPageSize = 1000
+
+// importBatchSize is the number of entries handed to ImportAlerts at once
+const importBatchSize = 500
+
+// maxSnapshotLine bounds a single line of a snapshot
+const maxSnapshotLine = 16 * 1024 * 1024
+
+// ErrInvalidSnapshot is returned when a snapshot line is no valid entry
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// ImportResult counts the entries of an imported snapshot
+type ImportResult struct {
+	Imported int `json:"imported"`
+	// Duplicates were already in the store
+	Duplicates int `json:"duplicates"`
+}
+
+// EntryKey identifies an entry across stores for deduplication: the same notification
+// of the same alert at the same time with the same status
+func EntryKey(entry AlertEntry) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d\xff%s\xff%s", entry.Timestamp.UnixNano(), IncidentKey(entry.Alert), entry.Status)
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+// NewEntries returns the entries whose key is not in existing and adds their keys to it.
+// Stores use it to deduplicate imports.
+func NewEntries(entries []AlertEntry, existing map[string]bool) []AlertEntry {
+	var fresh []AlertEntry
+	for _, entry := range entries {
+		key := EntryKey(entry)
+		if existing[key] {
+			continue
+		}
+		existing[key] = true
+		fresh = append(fresh, entry)
+	}
+	return fresh
+}
+
+// WriteSnapshot writes the entries selected by the query, from and to of opts as
+// newline delimited JSON, oldest first, and returns the number of written entries
+func WriteSnapshot(w io.Writer, store Store, opts ListOptions) (int, error) {
+	opts.Limit = snapshotPageSize
+	opts.Order = OrderAsc
+	opts.Cursor = ""
+
+	enc := json.NewEncoder(w)
+	written := 0
+	for {
+		page, err := store.ListAlerts(opts)
+		if err != nil {
+			return written, err
+		}
+		for _, entry := range page.Alerts {
+			if err := enc.Encode(entry); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if page.NextCursor == "" {
+			return written, nil
+		}
+		opts.Cursor = page.NextCursor
+	}
+}
+
+// ImportSnapshot loads newline delimited JSON entries into a store, skipping entries it already holds
+func ImportSnapshot(r io.Reader, store Store) (ImportResult, error) {
+	var result ImportResult
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxSnapshotLine)
+
+	batch := make([]AlertEntry, 0, importBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		imported, err := store.ImportAlerts(batch)
+		if err != nil {
+			return err
+		}
+		result.Imported += imported
+		result.Duplicates += len(batch) - imported
+		batch = batch[:0]
+		return nil
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry AlertEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return result, fmt.Errorf("%w: line %d: %v", ErrInvalidSnapshot, line, err)
+		}
+		if entry.Timestamp.IsZero() {
+			return result, fmt.Errorf("%w: line %d has no timestamp", ErrInvalidSnapshot, line)
+		}
+		batch = append(batch, entry)
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return result, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		return result, err
+	}
+	return result, flush()
+}
+
+// RestoreSnapshot imports a snapshot file into a store
+func RestoreSnapshot(path string, store Store) (ImportResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	defer func() { _ = file.Close() }()
+	return ImportSnapshot(file, store)
+}
+
+// SaveSnapshot writes all entries of a store to a file. The file is replaced
+// atomically, so a failed snapshot leaves the previous one intact.
+func SaveSnapshot(path string, store Store) (int, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	writer := bufio.NewWriter(tmp)
+	written, err := WriteSnapshot(writer, store, ListOptions{})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return written, os.Rename(tmp.Name(), path)
+}
+
+// Snapshotter periodically writes a snapshot of a store to a file
+type Snapshotter struct {
+	path     string
+	interval time.Duration
+	store    Store
+	stop     chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewSnapshotter creates a snapshotter that writes store to path every interval
+func NewSnapshotter(path string, interval time.Duration, store Store) *Snapshotter {
+	return &Snapshotter{path: path, interval: interval, store: store, stop: make(chan struct{})}
+}
+
+// Start writes snapshots in the background until Stop is called
+func (s *Snapshotter) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.snapshot()
+			}
+		}
+	}()
+}
+
+// Stop stops the snapshotter and writes a last snapshot. It may be called more than once.
+func (s *Snapshotter) Stop() {
+	s.once.Do(func() {
+		close(s.stop)
+		s.wg.Wait()
+		s.snapshot()
+	})
+}
+
+func (s *Snapshotter) snapshot() {
+	written, err := SaveSnapshot(s.path, s.store)
+	if err != nil {
+		log.Error("Failed to write alert store snapshot", zap.String("path", s.path), zap.Error(err))
+		return
+	}
+	log.Debug("Wrote alert store snapshot", zap.String("path", s.path), zap.Int("entries", written))
+}
@@ -0,0 +1,72 @@
+package sqlstore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DialectSQLite stores alerts in a local SQLite file, suitable for a single replica
+	DialectSQLite = "sqlite"
+	// DialectPostgres stores alerts in PostgreSQL, shared by all replicas
+	DialectPostgres = "postgres"
+)
+
+// dialect holds the differences between the supported databases
+type dialect struct {
+	name string
+	// driver is the database/sql driver name
+	driver string
+	// idColumn declares an auto-incrementing primary key
+	idColumn string
+	// lockMigrations serializes migrations of concurrently starting replicas, empty if not needed
+	lockMigrations string
+	// forUpdate locks a selected row until the end of the transaction, empty if writes are serialized anyway
+	forUpdate string
+	// numberedPlaceholders uses $1, $2, ... instead of ?
+	numberedPlaceholders bool
+}
+
+var dialects = map[string]dialect{
+	DialectSQLite: {
+		name:     DialectSQLite,
+		driver:   "sqlite",
+		idColumn: "INTEGER PRIMARY KEY AUTOINCREMENT",
+	},
+	DialectPostgres: {
+		name:                 DialectPostgres,
+		driver:               "postgres",
+		idColumn:             "BIGSERIAL PRIMARY KEY",
+		lockMigrations:       "SELECT pg_advisory_xact_lock(4207315)",
+		forUpdate:            " FOR UPDATE",
+		numberedPlaceholders: true,
+	},
+}
+
+// getDialect returns the dialect with the given name
+func getDialect(name string) (dialect, error) {
+	d, ok := dialects[name]
+	if !ok {
+		return dialect{}, fmt.Errorf("unsupported SQL dialect %q, must be %s or %s", name, DialectSQLite, DialectPostgres)
+	}
+	return d, nil
+}
+
+// rebind rewrites the ? placeholders of a query for the dialect
+func (d dialect) rebind(query string) string {
+	if !d.numberedPlaceholders {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
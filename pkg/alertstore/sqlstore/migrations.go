@@ -0,0 +1,130 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// migration is one step of the schema, {{id}} is replaced by the primary key declaration of the dialect
+type migration struct {
+	version    int
+	statements []string
+}
+
+// migrations are applied in order and must never be changed once released, add a new one instead
+var migrations = []migration{
+	{
+		version: 1,
+		statements: []string{
+			`CREATE TABLE alerts (
+				id {{id}},
+				created_at BIGINT NOT NULL,
+				status TEXT NOT NULL,
+				alertname TEXT NOT NULL,
+				fingerprint TEXT NOT NULL,
+				alert TEXT NOT NULL,
+				job_name TEXT NOT NULL DEFAULT '',
+				job_config_map TEXT NOT NULL DEFAULT '',
+				job_image TEXT NOT NULL DEFAULT '',
+				job_info TEXT
+			)`,
+			`CREATE INDEX alerts_created_at ON alerts (created_at)`,
+			`CREATE INDEX alerts_alertname ON alerts (alertname)`,
+			`CREATE INDEX alerts_fingerprint ON alerts (fingerprint)`,
+			`CREATE INDEX alerts_job_name ON alerts (job_name)`,
+			`CREATE TABLE alert_labels (
+				alert_id BIGINT NOT NULL REFERENCES alerts (id) ON DELETE CASCADE,
+				name TEXT NOT NULL,
+				value TEXT NOT NULL
+			)`,
+			`CREATE INDEX alert_labels_alert_id ON alert_labels (alert_id)`,
+			`CREATE INDEX alert_labels_name_value ON alert_labels (name, value)`,
+			`CREATE TABLE alert_annotations (
+				alert_id BIGINT NOT NULL REFERENCES alerts (id) ON DELETE CASCADE,
+				name TEXT NOT NULL,
+				value TEXT NOT NULL
+			)`,
+			`CREATE INDEX alert_annotations_alert_id ON alert_annotations (alert_id)`,
+		},
+	},
+}
+
+// migrate brings the schema up to the latest version, every migration runs in its own transaction
+func migrate(db *sql.DB, d dialect) error {
+	if err := createMigrationsTable(db, d); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	for _, m := range migrations {
+		if err := applyMigration(db, d, m); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", m.version, err)
+		}
+	}
+	return nil
+}
+
+// createMigrationsTable creates the table of applied migrations. The migration lock is taken
+// first, since concurrent CREATE TABLE IF NOT EXISTS statements can fail in PostgreSQL.
+func createMigrationsTable(db *sql.DB, d dialect) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // no-op after Commit
+
+	if d.lockMigrations != "" {
+		if _, err := tx.Exec(d.lockMigrations); err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at BIGINT NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applyMigration applies a migration unless another replica already did
+func applyMigration(db *sql.DB, d dialect, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // no-op after Commit
+
+	if d.lockMigrations != "" {
+		if _, err := tx.Exec(d.lockMigrations); err != nil {
+			return err
+		}
+	}
+
+	var applied int
+	err = tx.QueryRow(d.rebind(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`), m.version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	for _, statement := range m.statements {
+		if _, err := tx.Exec(strings.ReplaceAll(statement, "{{id}}", d.idColumn)); err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(d.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`), m.version, time.Now().UnixNano())
+	if err != nil {
+		return err
+	}
+
+	log.Info("Applied alert store migration", zap.String("dialect", d.name), zap.Int("version", m.version))
+	return tx.Commit()
+}
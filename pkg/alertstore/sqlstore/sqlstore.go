@@ -0,0 +1,474 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/metadata"
+	"go.uber.org/zap"
+
+	// Database drivers, both are written in pure Go and work in CGO_ENABLED=0 builds
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// pruneInterval is how often entries older than the retention are deleted
+const pruneInterval = 10 * time.Minute
+
+// SQLStore implements alertstore.Store on top of SQLite or PostgreSQL
+type SQLStore struct {
+	db        *sql.DB
+	dialect   dialect
+	dsn       string
+	retention time.Duration
+	now       func() time.Time
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	feed      alertstore.Feed
+}
+
+// NewSQLStore creates a new SQL alert store. Entries older than retention are deleted,
+// a retention of zero keeps them forever.
+func NewSQLStore(dialectName string, dsn string, retention time.Duration) (*SQLStore, error) {
+	d, err := getDialect(dialectName)
+	if err != nil {
+		return nil, err
+	}
+	if dsn == "" {
+		return nil, fmt.Errorf("a data source name is required for the %s alert store", dialectName)
+	}
+	return &SQLStore{
+		dialect:   d,
+		dsn:       dsn,
+		retention: retention,
+		now:       time.Now,
+	}, nil
+}
+
+// Initialize opens the database, migrates the schema and starts the retention pruner
+func (s *SQLStore) Initialize() error {
+	db, err := sql.Open(s.dialect.driver, s.dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open %s alert store: %w", s.dialect.name, err)
+	}
+	if s.dialect.name == DialectSQLite {
+		// SQLite allows a single writer, serializing connections avoids busy errors
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return fmt.Errorf("failed to connect to %s alert store: %w", s.dialect.name, err)
+	}
+	if err := migrate(db, s.dialect); err != nil {
+		_ = db.Close()
+		return err
+	}
+	s.db = db
+
+	if s.retention > 0 {
+		s.stop = make(chan struct{})
+		s.wg.Add(1)
+		go s.pruneLoop()
+	}
+
+	log.Info("SQL alert store initialized",
+		zap.String("dialect", s.dialect.name),
+		zap.Duration("retention", s.retention))
+	return nil
+}
+
+// Close stops the retention pruner and closes the database
+func (s *SQLStore) Close() error {
+	if s.stop != nil {
+		close(s.stop)
+		s.wg.Wait()
+		s.stop = nil
+	}
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// SaveAlert saves an alert to the database
+func (s *SQLStore) SaveAlert(alert alertstore.Alert, status string) error {
+	return s.SaveAlertWithJobInfo(alert, status, nil)
+}
+
+// SaveAlertWithJobInfo saves an alert with job information to the database
+func (s *SQLStore) SaveAlertWithJobInfo(alert alertstore.Alert, status string, jobInfo *alertstore.JobInfo) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // no-op after Commit
+
+	entry := alertstore.AlertEntry{Alert: alert, Status: status, Timestamp: s.now(), JobInfo: jobInfo}
+	if err := s.insertEntry(tx, entry); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.feed.Publish(alertstore.Event{Type: alertstore.EventAlert, Entry: entry})
+	return nil
+}
+
+// ImportAlerts adds entries with their timestamps, skipping entries the store already holds
+func (s *SQLStore) ImportAlerts(entries []alertstore.AlertEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	from, to := entries[0].Timestamp.UnixNano(), entries[0].Timestamp.UnixNano()
+	for _, entry := range entries {
+		from = min(from, entry.Timestamp.UnixNano())
+		to = max(to, entry.Timestamp.UnixNano())
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() // no-op after Commit
+
+	// Only entries in the time range of the batch can be duplicates
+	rows, err := tx.Query(s.dialect.rebind(`SELECT id, created_at, status, alert, job_info FROM alerts WHERE created_at >= ? AND created_at <= ?`), from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		_, entry, err := scanEntry(rows)
+		if err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		existing[alertstore.EntryKey(entry)] = true
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+
+	fresh := alertstore.NewEntries(entries, existing)
+	for _, entry := range fresh {
+		if err := s.insertEntry(tx, entry); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(fresh), nil
+}
+
+// insertEntry inserts an entry with its labels and annotations
+func (s *SQLStore) insertEntry(tx *sql.Tx, entry alertstore.AlertEntry) error {
+	alertJSON, err := json.Marshal(entry.Alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	jobInfoJSON, err := marshalJobInfo(entry.JobInfo)
+	if err != nil {
+		return err
+	}
+	var jobName, configMapName, image string
+	if entry.JobInfo != nil {
+		jobName, configMapName, image = entry.JobInfo.JobName, entry.JobInfo.ConfigMapName, entry.JobInfo.Image
+	}
+
+	var id int64
+	err = tx.QueryRow(s.dialect.rebind(`INSERT INTO alerts
+		(created_at, status, alertname, fingerprint, alert, job_name, job_config_map, job_image, job_info)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`),
+		entry.Timestamp.UnixNano(), entry.Status, entry.Alert.Labels["alertname"], entry.Alert.Fingerprint, string(alertJSON),
+		jobName, configMapName, image, jobInfoJSON).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("failed to insert alert: %w", err)
+	}
+
+	for name, value := range entry.Alert.Labels {
+		_, err = tx.Exec(s.dialect.rebind(`INSERT INTO alert_labels (alert_id, name, value) VALUES (?, ?, ?)`), id, name, value)
+		if err != nil {
+			return fmt.Errorf("failed to insert alert label: %w", err)
+		}
+	}
+	for name, value := range entry.Alert.Annotations {
+		_, err = tx.Exec(s.dialect.rebind(`INSERT INTO alert_annotations (alert_id, name, value) VALUES (?, ?, ?)`), id, name, value)
+		if err != nil {
+			return fmt.Errorf("failed to insert alert annotation: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateJobInfo updates the job information of the newest entry that triggered jobName
+func (s *SQLStore) UpdateJobInfo(jobName string, update func(*alertstore.JobInfo)) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // no-op after Commit
+
+	row := tx.QueryRow(s.dialect.rebind(`SELECT id, created_at, status, alert, job_info FROM alerts WHERE job_name = ? ORDER BY id DESC LIMIT 1`+s.dialect.forUpdate), jobName)
+	id, entry, err := scanEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return alertstore.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to select job info: %w", err)
+	}
+
+	if entry.JobInfo == nil {
+		entry.JobInfo = &alertstore.JobInfo{JobName: jobName}
+	}
+	jobInfo := entry.JobInfo
+	update(jobInfo)
+
+	updated, err := marshalJobInfo(jobInfo)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(s.dialect.rebind(`UPDATE alerts SET job_name = ?, job_config_map = ?, job_image = ?, job_info = ? WHERE id = ?`),
+		jobInfo.JobName, jobInfo.ConfigMapName, jobInfo.Image, updated, id)
+	if err != nil {
+		return fmt.Errorf("failed to update job info: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.feed.Publish(alertstore.Event{Type: alertstore.EventJob, Entry: entry})
+	return nil
+}
+
+// Subscribe returns the entries saved and the jobs updated by this replica until ctx is done
+func (s *SQLStore) Subscribe(ctx context.Context) <-chan alertstore.Event {
+	return s.feed.Subscribe(ctx)
+}
+
+// GetAlerts retrieves alerts newest first, optionally filtered by query
+func (s *SQLStore) GetAlerts(query string, limit int) ([]alertstore.AlertEntry, error) {
+	page, err := s.ListAlerts(alertstore.ListOptions{Query: query, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return page.Alerts, nil
+}
+
+// ListAlerts retrieves a page of alerts. Label equality, status, time, cursor and text filters
+// are evaluated by the database, the rest of the query on the returned rows. The row ID orders
+// entries with equal timestamps.
+func (s *SQLStore) ListAlerts(opts alertstore.ListOptions) (*alertstore.Page, error) {
+	list, err := alertstore.NewList(opts, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	where, args, complete := queryConditions(list.Query)
+	if !list.From.IsZero() {
+		where = append(where, `a.created_at >= ?`)
+		args = append(args, list.From.UnixNano())
+	}
+	if !list.To.IsZero() {
+		where = append(where, `a.created_at < ?`)
+		args = append(args, list.To.UnixNano())
+	}
+	direction, compare := `DESC`, `<`
+	if !list.Desc() {
+		direction, compare = `ASC`, `>`
+	}
+	if list.After != nil {
+		id, err := strconv.ParseInt(list.After.ID, 10, 64)
+		if err != nil {
+			return nil, alertstore.ErrInvalidCursor
+		}
+		after := list.After.Time.UnixNano()
+		where = append(where, `(a.created_at `+compare+` ? OR (a.created_at = ? AND a.id `+compare+` ?))`)
+		args = append(args, after, after, id)
+	}
+
+	statement := `SELECT a.id, a.created_at, a.status, a.alert, a.job_info FROM alerts a`
+	if len(where) > 0 {
+		statement += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	statement += ` ORDER BY a.created_at ` + direction + `, a.id ` + direction
+	if list.Limit > 0 && complete {
+		// One more row tells whether there is a next page
+		statement += ` LIMIT ?`
+		args = append(args, list.Limit+1)
+	}
+
+	rows, err := s.db.Query(s.dialect.rebind(statement), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		id, entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		if !list.Add(alertstore.Position{Time: entry.Timestamp, ID: fmt.Sprintf("%020d", id)}, entry) {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list.Page(), nil
+}
+
+// queryConditions translates the parts of a query the schema can answer into SQL conditions.
+// complete is false if other parts must be checked on the returned rows.
+func queryConditions(q *alertstore.Query) (where []string, args []interface{}, complete bool) {
+	complete = true
+	for _, m := range q.Matchers {
+		switch {
+		case m.Type != alertstore.MatchEqual || m.Value == "":
+			// Regular expressions, negations and missing labels are checked on the rows
+			complete = false
+		case m.Name == "alertname":
+			where = append(where, `a.alertname = ?`)
+			args = append(args, m.Value)
+		default:
+			where = append(where, `EXISTS (SELECT 1 FROM alert_labels l WHERE l.alert_id = a.id AND l.name = ? AND l.value = ?)`)
+			args = append(args, m.Name, m.Value)
+		}
+	}
+	if q.Status != "" {
+		where = append(where, `LOWER(a.status) = ?`)
+		args = append(args, strings.ToLower(q.Status))
+	}
+	// since= is part of the window of the list
+	if q.Job != "" {
+		complete = false
+	}
+	if q.Text != "" {
+		// Same fields as alertstore.MatchesText, case-insensitive
+		pattern := "%" + escapeLike(strings.ToLower(q.Text)) + "%"
+		where = append(where, `(LOWER(a.status) LIKE ? ESCAPE '\'
+			OR LOWER(a.job_name) LIKE ? ESCAPE '\'
+			OR LOWER(a.job_config_map) LIKE ? ESCAPE '\'
+			OR LOWER(a.job_image) LIKE ? ESCAPE '\'
+			OR EXISTS (SELECT 1 FROM alert_labels l WHERE l.alert_id = a.id AND LOWER(l.value) LIKE ? ESCAPE '\')
+			OR EXISTS (SELECT 1 FROM alert_annotations n WHERE n.alert_id = a.id AND LOWER(n.value) LIKE ? ESCAPE '\'))`)
+		for i := 0; i < 6; i++ {
+			args = append(args, pattern)
+		}
+	}
+	return where, args, complete
+}
+
+// Prune deletes entries older than the retention and returns how many were deleted
+func (s *SQLStore) Prune() (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	cutoff := s.now().Add(-s.retention).UnixNano()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() // no-op after Commit
+
+	// SQLite does not enforce foreign keys by default, so the cascade is done by hand
+	for _, table := range []string{"alert_labels", "alert_annotations"} {
+		_, err = tx.Exec(s.dialect.rebind(`DELETE FROM `+table+` WHERE alert_id IN (SELECT id FROM alerts WHERE created_at < ?)`), cutoff)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prune %s: %w", table, err)
+		}
+	}
+	result, err := tx.Exec(s.dialect.rebind(`DELETE FROM alerts WHERE created_at < ?`), cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune alerts: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	metadata.AlertStoreEvictionsTotal.WithLabelValues(alertstore.RetentionRuleAge).Add(float64(deleted))
+	return deleted, nil
+}
+
+// pruneLoop runs Prune periodically until the store is closed
+func (s *SQLStore) pruneLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.Prune()
+		if err != nil {
+			log.Error("Failed to prune alert store", zap.Error(err))
+		} else if deleted > 0 {
+			log.Info("Pruned alert store", zap.Int64("deleted", deleted), zap.Duration("retention", s.retention))
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scanEntry reads the ID and alert entry from a row of id, created_at, status, alert and job_info
+func scanEntry(rows scanner) (int64, alertstore.AlertEntry, error) {
+	var id, createdAt int64
+	var status, alertJSON string
+	var jobInfoJSON sql.NullString
+	if err := rows.Scan(&id, &createdAt, &status, &alertJSON, &jobInfoJSON); err != nil {
+		return 0, alertstore.AlertEntry{}, fmt.Errorf("failed to scan alert: %w", err)
+	}
+
+	entry := alertstore.AlertEntry{
+		Status:    status,
+		Timestamp: time.Unix(0, createdAt),
+	}
+	if err := json.Unmarshal([]byte(alertJSON), &entry.Alert); err != nil {
+		return 0, alertstore.AlertEntry{}, fmt.Errorf("failed to unmarshal alert: %w", err)
+	}
+	if jobInfoJSON.Valid {
+		entry.JobInfo = &alertstore.JobInfo{}
+		if err := json.Unmarshal([]byte(jobInfoJSON.String), entry.JobInfo); err != nil {
+			return 0, alertstore.AlertEntry{}, fmt.Errorf("failed to unmarshal job info: %w", err)
+		}
+	}
+	return id, entry, nil
+}
+
+// scanner is a row or the current row of a result set
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// marshalJobInfo encodes job information as JSON, nil is stored as NULL
+func marshalJobInfo(jobInfo *alertstore.JobInfo) (sql.NullString, error) {
+	if jobInfo == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(jobInfo)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal job info: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,239 @@
+package sqlstore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	// Initialize logger before running tests
+	if err := log.SetConfig(zap.NewDevelopmentConfig()); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func newTestStore(t *testing.T, retention time.Duration) *SQLStore {
+	t.Helper()
+	store, err := NewSQLStore(DialectSQLite, filepath.Join(t.TempDir(), "alerts.db"), retention)
+	if err != nil {
+		t.Fatalf("NewSQLStore failed: %v", err)
+	}
+	if err := store.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestSQLStoreSaveAndSearch(t *testing.T) {
+	store := newTestStore(t, 0)
+
+	alerts := []struct {
+		labels      map[string]string
+		annotations map[string]string
+		status      string
+	}{
+		{map[string]string{"alertname": "KubeQuotaExceeded", "namespace": "payments"}, nil, "firing"},
+		{map[string]string{"alertname": "DiskFull"}, map[string]string{"summary": "Disk 100% full"}, "resolved"},
+		{map[string]string{"alertname": "DiskFull"}, nil, "FIRING"},
+	}
+	for _, a := range alerts {
+		if err := store.SaveAlert(alertstore.Alert{Labels: a.labels, Annotations: a.annotations}, a.status); err != nil {
+			t.Fatalf("SaveAlert failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 3},
+		{"firing", 2},
+		{"diskfull", 2},
+		{"payments", 1},
+		{"100%", 1},
+		{"50%", 0},
+		{`{alertname="DiskFull"}`, 2},
+		{`{alertname="DiskFull"} status=firing`, 1},
+		{`{namespace="payments"}`, 1},
+		{`{namespace!="payments"}`, 2},
+		{`{alertname=~"Disk.*"} 100%`, 1},
+		{"job=none", 3},
+	}
+	for _, tt := range tests {
+		entries, err := store.GetAlerts(tt.query, 0)
+		if err != nil {
+			t.Fatalf("GetAlerts(%q) failed: %v", tt.query, err)
+		}
+		if len(entries) != tt.want {
+			t.Errorf("GetAlerts(%q) returned %d entries; want %d", tt.query, len(entries), tt.want)
+		}
+	}
+
+	entries, err := store.GetAlerts("", 1)
+	if err != nil || len(entries) != 1 || entries[0].Status != "FIRING" {
+		t.Errorf("GetAlerts with limit 1 = %+v (err %v); want the newest entry", entries, err)
+	}
+}
+
+func TestSQLStoreUpdateJobInfo(t *testing.T) {
+	store := newTestStore(t, 0)
+
+	alert := alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull"}}
+	jobInfo := &alertstore.JobInfo{JobName: "cleanup-abcde", ConfigMapName: "openfero-diskfull-firing"}
+	if err := store.SaveAlertWithJobInfo(alert, "firing", jobInfo); err != nil {
+		t.Fatalf("SaveAlertWithJobInfo failed: %v", err)
+	}
+
+	err := store.UpdateJobInfo("cleanup-abcde", func(info *alertstore.JobInfo) {
+		info.FailureReason = "Forbidden"
+	})
+	if err != nil {
+		t.Fatalf("UpdateJobInfo failed: %v", err)
+	}
+	entries, err := store.GetAlerts("cleanup-abcde", 0)
+	if err != nil || len(entries) != 1 || entries[0].JobInfo.FailureReason != "Forbidden" {
+		t.Errorf("GetAlerts = %+v (err %v); want the updated job info", entries, err)
+	}
+
+	if err := store.UpdateJobInfo("unknown", func(*alertstore.JobInfo) {}); err != alertstore.ErrNotFound {
+		t.Errorf("UpdateJobInfo of an unknown job = %v; want ErrNotFound", err)
+	}
+}
+
+func TestSQLStorePrune(t *testing.T) {
+	// The retention is set after Initialize, so the prune loop does not race with the test
+	store := newTestStore(t, 0)
+	store.retention = time.Hour
+	now := time.Now()
+
+	store.now = func() time.Time { return now.Add(-2 * time.Hour) }
+	if err := store.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": "Old"}}, "firing"); err != nil {
+		t.Fatalf("SaveAlert failed: %v", err)
+	}
+	store.now = func() time.Time { return now }
+	if err := store.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": "New"}}, "firing"); err != nil {
+		t.Fatalf("SaveAlert failed: %v", err)
+	}
+
+	deleted, err := store.Prune()
+	if err != nil || deleted != 1 {
+		t.Fatalf("Prune = (%d, %v); want (1, nil)", deleted, err)
+	}
+	entries, err := store.GetAlerts("", 0)
+	if err != nil || len(entries) != 1 || entries[0].Alert.Labels["alertname"] != "New" {
+		t.Errorf("GetAlerts after prune = %+v (err %v); want only the new alert", entries, err)
+	}
+}
+
+func TestSQLStoreSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.db")
+	for i := 0; i < 2; i++ {
+		store, err := NewSQLStore(DialectSQLite, path, 0)
+		if err != nil {
+			t.Fatalf("NewSQLStore failed: %v", err)
+		}
+		if err := store.Initialize(); err != nil {
+			t.Fatalf("Initialize %d failed: %v", i+1, err)
+		}
+		if err := store.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": "Test"}}, "firing"); err != nil {
+			t.Fatalf("SaveAlert failed: %v", err)
+		}
+		entries, err := store.GetAlerts("", 0)
+		if err != nil || len(entries) != i+1 {
+			t.Errorf("run %d: GetAlerts returned %d entries (err %v); want %d", i+1, len(entries), err, i+1)
+		}
+		_ = store.Close()
+	}
+}
+
+func TestNewSQLStoreValidation(t *testing.T) {
+	if _, err := NewSQLStore("mysql", "dsn", 0); err == nil {
+		t.Errorf("NewSQLStore accepted an unsupported dialect")
+	}
+	if _, err := NewSQLStore(DialectPostgres, "", 0); err == nil {
+		t.Errorf("NewSQLStore accepted an empty data source name")
+	}
+	if got := dialects[DialectPostgres].rebind("a = ? AND b = ?"); got != "a = $1 AND b = $2" {
+		t.Errorf("rebind = %q; want numbered placeholders", got)
+	}
+}
+
+func TestSQLStoreListAlerts(t *testing.T) {
+	store := newTestStore(t, 0)
+	start := time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+	for i, alertname := range []string{"A", "B", "A", "B", "A", "B"} {
+		// Pairs of entries share a timestamp, the row ID orders them
+		at := start.Add(time.Duration(i/2) * time.Minute)
+		store.now = func() time.Time { return at }
+		if err := store.SaveAlert(alertstore.Alert{Labels: map[string]string{"alertname": alertname}}, "firing"); err != nil {
+			t.Fatalf("SaveAlert failed: %v", err)
+		}
+	}
+	store.now = time.Now
+
+	walk := func(opts alertstore.ListOptions) []string {
+		var names []string
+		for {
+			page, err := store.ListAlerts(opts)
+			if err != nil {
+				t.Fatalf("ListAlerts(%+v) failed: %v", opts, err)
+			}
+			for _, entry := range page.Alerts {
+				names = append(names, entry.Alert.Labels["alertname"]+entry.Timestamp.UTC().Format("04"))
+			}
+			if page.NextCursor == "" {
+				return names
+			}
+			opts.Cursor = page.NextCursor
+		}
+	}
+
+	tests := []struct {
+		opts alertstore.ListOptions
+		want string
+	}{
+		{alertstore.ListOptions{Limit: 4}, "[B02 A02 B01 A01 B00 A00]"},
+		{alertstore.ListOptions{Limit: 1, Order: alertstore.OrderAsc}, "[A00 B00 A01 B01 A02 B02]"},
+		{alertstore.ListOptions{Limit: 1, From: start.Add(time.Minute), To: start.Add(2 * time.Minute)}, "[B01 A01]"},
+		{alertstore.ListOptions{Query: `{alertname="A"}`, Limit: 1, Order: alertstore.OrderAsc}, "[A00 A01 A02]"},
+		// Regular expressions are checked on the rows, the page is filled anyway
+		{alertstore.ListOptions{Query: `{alertname=~"B"}`, Limit: 2}, "[B02 B01 B00]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(walk(tt.opts)); got != tt.want {
+			t.Errorf("ListAlerts(%+v) = %s; want %s", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestSQLStoreImportAlerts(t *testing.T) {
+	store := newTestStore(t, 0)
+	at := time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+	entries := []alertstore.AlertEntry{
+		{Alert: alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull", "node": "a"}}, Status: "firing", Timestamp: at},
+		{Alert: alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull", "node": "a"}}, Status: "resolved", Timestamp: at},
+		{Alert: alertstore.Alert{Labels: map[string]string{"alertname": "DiskFull", "node": "a"}}, Status: "firing", Timestamp: at},
+	}
+	// Only the status tells the first two entries apart, the third repeats the first
+	imported, err := store.ImportAlerts(entries)
+	if err != nil || imported != 2 {
+		t.Fatalf("ImportAlerts = %d, %v; want 2", imported, err)
+	}
+	if imported, err := store.ImportAlerts(entries); err != nil || imported != 0 {
+		t.Errorf("second ImportAlerts = %d, %v; want no duplicates", imported, err)
+	}
+
+	found, err := store.GetAlerts(`{node="a"} status=resolved`, 0)
+	if err != nil || len(found) != 1 || !found[0].Timestamp.Equal(at) {
+		t.Errorf("GetAlerts = %+v (err %v); want the resolved entry with its original timestamp", found, err)
+	}
+}
@@ -51,7 +51,7 @@ const docTemplate = `{
         },
         "/alertStore": {
             "get": {
-                "description": "Get the stored alerts with optional filtering",
+                "description": "Alias of GET /api/v1/alerts that answers with the bare list of alerts",
                 "produces": [
                     "application/json"
                 ],
@@ -59,28 +59,79 @@ const docTemplate = `{
                     "alerts"
                 ],
                 "summary": "Get alert store",
+                "deprecated": true,
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Search query to filter alerts",
                         "name": "q",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of alerts, up to 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Next-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Oldest alert time, inclusive",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Newest alert time, exclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "desc",
+                            "asc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order by time",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page, missing on the last page"
+                            }
+                        },
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/main.alert"
+                                "$ref": "#/definitions/alertstore.AlertEntry"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.APIError"
                         }
                     }
                 }
@@ -105,6 +156,126 @@ const docTemplate = `{
                     }
                 }
             },
+            "post": {
+                "description": "Alias of POST /api/v1/alerts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Process incoming alerts",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Alert message",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.hookMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts": {
+            "get": {
+                "description": "List stored alerts newest first, paged with limit and cursor",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "List alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query to filter alerts",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of alerts, up to 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Next-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Oldest alert time, inclusive",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Newest alert time, exclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "desc",
+                            "asc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order by time",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page like next_cursor, missing on the last page"
+                            }
+                        },
+                        "schema": {
+                            "$ref": "#/definitions/alertstore.Page"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
             "post": {
                 "description": "Process alerts received from Alertmanager",
                 "consumes": [
@@ -135,7 +306,322 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/definitions": {
+            "get": {
+                "description": "List the job definitions sorted by cluster, ConfigMap and key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "definitions"
+                ],
+                "summary": "List job definitions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a cluster, all clusters if missing",
+                        "name": "cluster",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.JobInfo"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Cluster not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/definitions/{configmap}/{key}": {
+            "get": {
+                "description": "Get a job definition with its parsed job and the job rendered for an alert named after the key",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "definitions"
+                ],
+                "summary": "Get a job definition",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the definition ConfigMap",
+                        "name": "configmap",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data key of the definition, the alertname",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a cluster, the default cluster if missing",
+                        "name": "cluster",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Alert label to render the job with, as name=value",
+                        "name": "label",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Definition"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Definition or cluster not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid definition",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/incidents": {
+            "get": {
+                "description": "List incidents newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "List incidents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query to filter alerts",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of alerts, up to 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Oldest alert time, inclusive",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Newest alert time, exclusive",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/alertstore.Incident"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/incidents/{id}": {
+            "get": {
+                "description": "Get an incident",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Get an incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID, or an incident key for its latest incident",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Time of an entry of the incident",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/alertstore.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs": {
+            "get": {
+                "description": "List the jobs created by OpenFero newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of a cluster, all clusters if missing",
+                        "name": "cluster",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Job"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Cluster not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs/{name}": {
+            "get": {
+                "description": "Get a job created by OpenFero",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get a job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the job",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of a cluster, all clusters in the order of their names if missing",
+                        "name": "cluster",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Job or cluster not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
                         }
                     }
                 }
@@ -244,6 +730,191 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "alertstore.Alert": {
+            "description": "Alert information from Alertmanager",
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "description": "Key-value pairs of alert annotations",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "endsAt": {
+                    "description": "Time when the alert ended",
+                    "type": "string"
+                },
+                "fingerprint": {
+                    "description": "Alertmanager fingerprint identifying the alert by its labels",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "Key-value pairs of alert labels",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "startsAt": {
+                    "description": "Time when the alert started firing",
+                    "type": "string"
+                }
+            }
+        },
+        "alertstore.AlertEntry": {
+            "description": "Stored alert notification",
+            "type": "object",
+            "properties": {
+                "alert": {
+                    "$ref": "#/definitions/alertstore.Alert"
+                },
+                "jobInfo": {
+                    "$ref": "#/definitions/alertstore.JobInfo"
+                },
+                "status": {
+                    "description": "Status of the notification",
+                    "type": "string",
+                    "example": "firing"
+                },
+                "timestamp": {
+                    "description": "Time the notification was received",
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "alertstore.Incident": {
+            "description": "Episode of an alert from its first firing notification to its resolution",
+            "type": "object",
+            "properties": {
+                "alertname": {
+                    "description": "Name of the alert",
+                    "type": "string"
+                },
+                "durationSeconds": {
+                    "description": "Time from start to end, or to now while firing",
+                    "type": "integer"
+                },
+                "endsAt": {
+                    "description": "End of the incident, missing while firing",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "description": "Incident key and start in Unix seconds",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Fingerprint of the alert, or a hash of its labels",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "Labels of the alert",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "startsAt": {
+                    "description": "Start of the incident",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "description": "Status of the incident",
+                    "type": "string",
+                    "enum": [
+                        "firing",
+                        "resolved"
+                    ]
+                },
+                "timeline": {
+                    "description": "Notifications of the incident oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/alertstore.AlertEntry"
+                    }
+                }
+            }
+        },
+        "alertstore.JobInfo": {
+            "description": "Job triggered by an alert",
+            "type": "object",
+            "properties": {
+                "cancelledAt": {
+                    "description": "Time the job was deleted because its alert resolved",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "cluster": {
+                    "description": "Cluster the job was created in",
+                    "type": "string"
+                },
+                "completedAt": {
+                    "description": "Time the job succeeded or failed",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "configMapName": {
+                    "description": "Name of the ConfigMap of the job definition",
+                    "type": "string"
+                },
+                "dryRun": {
+                    "description": "Job was only validated with a server-side dry-run",
+                    "type": "boolean"
+                },
+                "failureReason": {
+                    "description": "Kubernetes status reason if the job could not be created",
+                    "type": "string"
+                },
+                "image": {
+                    "description": "Container image of the job",
+                    "type": "string"
+                },
+                "jobName": {
+                    "description": "Name of the job",
+                    "type": "string"
+                },
+                "lockKey": {
+                    "description": "Rendered lock key of the job",
+                    "type": "string"
+                },
+                "outcome": {
+                    "description": "Outcome of the finished job",
+                    "type": "string",
+                    "enum": [
+                        "succeeded",
+                        "failed"
+                    ]
+                },
+                "skipReason": {
+                    "description": "Why no job was created, e.g. rate-limited",
+                    "type": "string"
+                },
+                "startedAt": {
+                    "description": "Time the job started running",
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "alertstore.Page": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "description": "Alerts of the page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/alertstore.AlertEntry"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor continues with the next page, it is empty on the last page",
+                    "type": "string"
+                }
+            }
+        },
         "main.alert": {
             "description": "Alert information from Alertmanager",
             "type": "object",
@@ -326,6 +997,355 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.APIError": {
+            "description": "Body of all error responses of the /api/v1 endpoints",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "HTTP status text",
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "message": {
+                    "description": "What went wrong",
+                    "type": "string",
+                    "example": "job not found"
+                },
+                "status": {
+                    "description": "HTTP status code",
+                    "type": "integer",
+                    "example": 404
+                }
+            }
+        },
+        "models.ContainerState": {
+            "description": "State of a container of a pod",
+            "type": "object",
+            "properties": {
+                "exitCode": {
+                    "description": "Exit code of a terminated container",
+                    "type": "integer"
+                },
+                "finishedAt": {
+                    "description": "Time a terminated container finished",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "image": {
+                    "description": "Image the container runs",
+                    "type": "string"
+                },
+                "init": {
+                    "description": "Container is an init container",
+                    "type": "boolean"
+                },
+                "message": {
+                    "description": "Message of a waiting or terminated state",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the container",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason of a waiting or terminated state, e.g. ImagePullBackOff or Completed",
+                    "type": "string"
+                },
+                "restartCount": {
+                    "description": "Number of restarts of the container",
+                    "type": "integer"
+                },
+                "startedAt": {
+                    "description": "Time the container started running",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "state": {
+                    "description": "State of the container",
+                    "type": "string",
+                    "enum": [
+                        "waiting",
+                        "running",
+                        "terminated"
+                    ]
+                }
+            }
+        },
+        "models.Definition": {
+            "description": "Job definition with its parsed and rendered job",
+            "type": "object",
+            "properties": {
+                "breakerClosesAt": {
+                    "description": "Time at which an open circuit breaker lets a run through again",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "breakerOpen": {
+                    "description": "Circuit breaker of the definition is open",
+                    "type": "boolean"
+                },
+                "breakerThreshold": {
+                    "description": "Number of consecutive failed jobs after which the circuit breaker opens",
+                    "type": "integer"
+                },
+                "cluster": {
+                    "description": "Cluster the job is defined in",
+                    "type": "string"
+                },
+                "concurrencyPolicy": {
+                    "description": "Concurrency policy of the definition",
+                    "type": "string",
+                    "enum": [
+                        "Allow",
+                        "Forbid",
+                        "Replace"
+                    ]
+                },
+                "configMapName": {
+                    "description": "Name of the ConfigMap containing the job definition",
+                    "type": "string"
+                },
+                "consecutiveFailures": {
+                    "description": "Number of consecutive failed jobs of the definition",
+                    "type": "integer"
+                },
+                "image": {
+                    "description": "Container image used by the job",
+                    "type": "string"
+                },
+                "job": {
+                    "description": "Job parsed from the definition, a batch/v1 Job",
+                    "type": "object"
+                },
+                "jobName": {
+                    "description": "Data key of the definition, the alertname",
+                    "type": "string"
+                },
+                "lockKey": {
+                    "description": "Lock key template of the definition",
+                    "type": "string"
+                },
+                "manifest": {
+                    "description": "Job manifest as OpenFero creates it for an alert, without the random name suffix",
+                    "type": "string"
+                },
+                "rateLimit": {
+                    "description": "Rate limit of the definition in the format <runs>/<duration>",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Job": {
+            "description": "Job created by OpenFero",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Number of running pods",
+                    "type": "integer"
+                },
+                "alert": {
+                    "description": "Alert store entry of the notification that triggered the job",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/alertstore.AlertEntry"
+                        }
+                    ]
+                },
+                "alertname": {
+                    "description": "Name of the alert that triggered the job",
+                    "type": "string"
+                },
+                "cluster": {
+                    "description": "Cluster the job runs in",
+                    "type": "string"
+                },
+                "completedAt": {
+                    "description": "Time the job succeeded or failed",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "conditions": {
+                    "description": "Conditions of the job",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JobCondition"
+                    }
+                },
+                "createdAt": {
+                    "description": "Time the job was created",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "definition": {
+                    "description": "Name of the ConfigMap of the job definition",
+                    "type": "string"
+                },
+                "durationSeconds": {
+                    "description": "Time from the start to the completion of the job, or to now while it runs",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Number of failed pods",
+                    "type": "integer"
+                },
+                "fingerprint": {
+                    "description": "Alertmanager fingerprint of the alert that triggered the job",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the job",
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "Namespace of the job",
+                    "type": "string"
+                },
+                "pods": {
+                    "description": "Pods of the job, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JobPod"
+                    }
+                },
+                "startedAt": {
+                    "description": "Time the job started running",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "state": {
+                    "description": "State of the job",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "suspended",
+                        "running",
+                        "succeeded",
+                        "failed"
+                    ]
+                },
+                "succeeded": {
+                    "description": "Number of succeeded pods",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.JobCondition": {
+            "description": "Condition of a job",
+            "type": "object",
+            "properties": {
+                "lastTransitionTime": {
+                    "description": "Time of the last transition",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "message": {
+                    "description": "Message of the last transition",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason of the last transition, e.g. BackoffLimitExceeded",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status of the condition: True, False or Unknown",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type of the condition, e.g. Complete or Failed",
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobInfo": {
+            "description": "Job definition",
+            "type": "object",
+            "properties": {
+                "breakerClosesAt": {
+                    "description": "Time at which an open circuit breaker lets a run through again",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "breakerOpen": {
+                    "description": "Circuit breaker of the definition is open",
+                    "type": "boolean"
+                },
+                "breakerThreshold": {
+                    "description": "Number of consecutive failed jobs after which the circuit breaker opens",
+                    "type": "integer"
+                },
+                "cluster": {
+                    "description": "Cluster the job is defined in",
+                    "type": "string"
+                },
+                "concurrencyPolicy": {
+                    "description": "Concurrency policy of the definition",
+                    "type": "string",
+                    "enum": [
+                        "Allow",
+                        "Forbid",
+                        "Replace"
+                    ]
+                },
+                "configMapName": {
+                    "description": "Name of the ConfigMap containing the job definition",
+                    "type": "string"
+                },
+                "consecutiveFailures": {
+                    "description": "Number of consecutive failed jobs of the definition",
+                    "type": "integer"
+                },
+                "image": {
+                    "description": "Container image used by the job",
+                    "type": "string"
+                },
+                "jobName": {
+                    "description": "Data key of the definition, the alertname",
+                    "type": "string"
+                },
+                "lockKey": {
+                    "description": "Lock key template of the definition",
+                    "type": "string"
+                },
+                "rateLimit": {
+                    "description": "Rate limit of the definition in the format <runs>/<duration>",
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobPod": {
+            "description": "Pod of a job",
+            "type": "object",
+            "properties": {
+                "containers": {
+                    "description": "Containers of the pod, init containers first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ContainerState"
+                    }
+                },
+                "name": {
+                    "description": "Name of the pod",
+                    "type": "string"
+                },
+                "node": {
+                    "description": "Node the pod is scheduled to",
+                    "type": "string"
+                },
+                "phase": {
+                    "description": "Phase of the pod, e.g. Running or Failed",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason of the phase, e.g. Evicted",
+                    "type": "string"
+                },
+                "startedAt": {
+                    "description": "Time the pod was started by the kubelet",
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
         }
     }
 }`
@@ -2,7 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/OpenFero/openfero/pkg/alertstore"
 	"github.com/OpenFero/openfero/pkg/kubernetes"
@@ -16,12 +21,23 @@ import (
 const (
 	ContentTypeHeader  = "Content-Type"
 	ApplicationJSONVal = "application/json"
+
+	// defaultAlertLimit is the page size of /alertStore without a limit parameter
+	defaultAlertLimit = 100
+	// maxAlertLimit bounds the page size of /alertStore
+	maxAlertLimit = 1000
 )
 
 // Server holds dependencies for handlers
 type Server struct {
-	KubeClient *kubernetes.Client
+	Clusters   *kubernetes.ClusterSet
 	AlertStore alertstore.Store
+	Limits     *services.Limits
+	// ApproverHeader is the request header with the identity of the approver, set by an
+	// authenticating proxy. Approvals are refused while it is empty.
+	ApproverHeader string
+	// MaxImportBytes bounds the body of an alert store import
+	MaxImportBytes int64
 }
 
 // AlertsGetHandler handles GET requests to /alerts
@@ -37,7 +53,7 @@ func (s *Server) AlertsGetHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// AlertsPostHandler handles POST requests to /alerts
+// AlertsPostHandler handles POST requests to /api/v1/alerts and /alerts, the Alertmanager webhook
 func (s *Server) AlertsPostHandler(w http.ResponseWriter, r *http.Request) {
 	dec := json.NewDecoder(r.Body)
 	defer func() {
@@ -49,7 +65,7 @@ func (s *Server) AlertsPostHandler(w http.ResponseWriter, r *http.Request) {
 	message := models.HookMessage{}
 	if err := dec.Decode(&message); err != nil {
 		log.Error("error decoding message: ", zap.String("error", err.Error()))
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeAPIError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
@@ -71,27 +87,104 @@ func (s *Server) AlertsPostHandler(w http.ResponseWriter, r *http.Request) {
 		zap.Int("jobCount", alertcount))
 
 	for _, alert := range message.Alerts {
-		go services.CreateResponseJob(s.KubeClient, s.AlertStore, alert, status)
+		client, err := s.Clusters.ForAlert(alert.Labels)
+		if err != nil {
+			log.Error("Could not select target cluster for alert",
+				zap.String("alertname", alert.Labels["alertname"]),
+				zap.Error(err))
+			services.SaveAlert(s.AlertStore, alert, status)
+			continue
+		}
+		go services.CreateResponseJob(client, s.AlertStore, alert, status)
 	}
 }
 
-// AlertStoreGetHandler handles GET requests to /alertStore
-func (s *Server) AlertStoreGetHandler(w http.ResponseWriter, r *http.Request) {
-	// Get search query parameter
-	query := r.URL.Query().Get("q")
-	limit := 100 // Default limit
-
-	alerts, err := s.AlertStore.GetAlerts(query, limit)
-	if err != nil {
-		log.Error("Error retrieving alerts", zap.Error(err))
-		http.Error(w, "", http.StatusInternalServerError)
+// AlertListGetHandler handles GET requests to /api/v1/alerts. The alerts are paged with limit, cursor, from,
+// to and order, the cursor of the next page is returned as next_cursor and in the X-Next-Cursor header.
+func (s *Server) AlertListGetHandler(w http.ResponseWriter, r *http.Request) {
+	page, ok := s.listAlerts(w, r)
+	if !ok {
 		return
 	}
+	if page.Alerts == nil {
+		page.Alerts = []alertstore.AlertEntry{}
+	}
+	writeJSON(w, page)
+}
 
+// AlertStoreGetHandler handles GET requests to /alertStore. It pages like /api/v1/alerts, but answers
+// with the bare list of alerts and returns the cursor of the next page in the X-Next-Cursor header only.
+func (s *Server) AlertStoreGetHandler(w http.ResponseWriter, r *http.Request) {
+	page, ok := s.listAlerts(w, r)
+	if !ok {
+		return
+	}
 	w.Header().Set(ContentTypeHeader, ApplicationJSONVal)
-	err = json.NewEncoder(w).Encode(alerts)
+	err := json.NewEncoder(w).Encode(page.Alerts)
 	if err != nil {
 		log.Error("Error encoding alerts", zap.Error(err))
 		http.Error(w, "", http.StatusInternalServerError)
 	}
 }
+
+// listAlerts reads a page of alerts and sets the X-Next-Cursor and Link headers. It answers
+// errors itself and returns false then.
+func (s *Server) listAlerts(w http.ResponseWriter, r *http.Request) (*alertstore.Page, bool) {
+	opts, err := parseListOptions(r.URL.Query())
+	if err == nil {
+		// Validate the query and cursor before asking the store
+		_, err = alertstore.NewList(opts, time.Now())
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	page, err := s.AlertStore.ListAlerts(opts)
+	if errors.Is(err, alertstore.ErrInvalidCursor) {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if err != nil {
+		log.Error("Error retrieving alerts", zap.Error(err))
+		writeAPIError(w, http.StatusInternalServerError, "")
+		return nil, false
+	}
+
+	if page.NextCursor != "" {
+		next := r.URL.Query()
+		next.Set("cursor", page.NextCursor)
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+		w.Header().Set("Link", fmt.Sprintf("<%s?%s>; rel=\"next\"", r.URL.Path, next.Encode()))
+	}
+	return page, true
+}
+
+// parseListOptions reads the q, limit, cursor, from, to and order parameters
+func parseListOptions(values url.Values) (alertstore.ListOptions, error) {
+	opts := alertstore.ListOptions{
+		Query:  values.Get("q"),
+		Limit:  defaultAlertLimit,
+		Cursor: values.Get("cursor"),
+		Order:  alertstore.Order(values.Get("order")),
+	}
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxAlertLimit {
+			return opts, fmt.Errorf("invalid limit %q, must be between 1 and %d", limit, maxAlertLimit)
+		}
+		opts.Limit = n
+	}
+	for name, t := range map[string]*time.Time{"from": &opts.From, "to": &opts.To} {
+		value := values.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s %q, must be an RFC 3339 time", name, value)
+		}
+		*t = parsed
+	}
+	return opts, nil
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// defaultAnalyticsWindow is the window of the analytics without from and window parameters
+const defaultAnalyticsWindow = 7 * 24 * time.Hour
+
+// analyticsWindows are the windows offered by the analytics page
+var analyticsWindows = []string{"24h", "7d", "30d"}
+
+// durationBar is a bar of the p50 and p95 of durations, scaled to the longest p95 of the page
+type durationBar struct {
+	Label    string
+	Stats    alertstore.DurationStats
+	P50Width float64 // Percentage of the bar up to the p50
+	P95Width float64 // Percentage of the bar from the p50 to the p95
+}
+
+// share returns value as a percentage of total, the width of a bar
+func share(value, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(value / total * 100)
+}
+
+// analyticsTemplateFuncs are the template functions of the analytics page
+var analyticsTemplateFuncs = template.FuncMap{
+	"percent": func(rate float64) string {
+		return strconv.FormatFloat(rate*100, 'f', 1, 64) + "%"
+	},
+	"share": share,
+	"durationBar": func(label string, stats alertstore.DurationStats, maxSeconds float64) durationBar {
+		p50 := share(stats.P50Seconds, maxSeconds)
+		return durationBar{Label: label, Stats: stats, P50Width: p50, P95Width: share(stats.P95Seconds, maxSeconds) - p50}
+	},
+	"formatSeconds": func(seconds float64) string {
+		return (time.Duration(seconds * float64(time.Second))).Round(time.Second).String()
+	},
+}
+
+// AnalyticsGetHandler handles GET requests to /api/v1/analytics. It returns the remediation statistics
+// of the entries matching q in the window selected by from and to, or by window until now.
+func (s *Server) AnalyticsGetHandler(w http.ResponseWriter, r *http.Request) {
+	analytics, err := s.collectAnalytics(r.URL.Query())
+	if err != nil {
+		writeAPIStoreError(w, err)
+		return
+	}
+
+	w.Header().Set(ContentTypeHeader, ApplicationJSONVal)
+	if err := json.NewEncoder(w).Encode(analytics); err != nil {
+		log.Error("Error encoding analytics", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
+
+// AnalyticsUIHandler handles GET requests to /analytics
+func (s *Server) AnalyticsUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(ContentTypeHeader, "text/html")
+
+	log.Debug("Processing analytics UI request",
+		zap.String("path", r.URL.Path),
+		zap.String("method", r.Method),
+		zap.String("remoteAddr", r.RemoteAddr))
+
+	values := r.URL.Query()
+	analytics, err := s.collectAnalytics(values)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	window := values.Get("window")
+	if window == "" && values.Get("from") == "" {
+		window = "7d"
+	}
+
+	// The longest p95 scales the duration bars
+	var maxSeconds float64
+	for _, stats := range analytics.Remediations {
+		maxSeconds = max(maxSeconds, stats.Duration.P95Seconds, stats.TimeToStart.P95Seconds, stats.TimeToResolve.P95Seconds)
+	}
+
+	data := struct {
+		Title      string
+		ShowSearch bool
+		Analytics  *alertstore.Analytics
+		MaxSeconds float64
+		Window     string
+		Windows    []string
+		Query      string
+		Version    string
+		Commit     string
+		BuildDate  string
+	}{
+		Title:      "Analytics",
+		ShowSearch: false,
+		Analytics:  analytics,
+		MaxSeconds: maxSeconds,
+		Window:     window,
+		Windows:    analyticsWindows,
+		Query:      values.Get("q"),
+		Version:    buildInformation.Version,
+		Commit:     buildInformation.Commit,
+		BuildDate:  buildInformation.BuildDate,
+	}
+
+	tmpl, err := template.New("analytics.html.templ").Funcs(analyticsTemplateFuncs).ParseFiles(
+		"web/templates/analytics.html.templ",
+		"web/templates/navbar.html.templ",
+	)
+	if err != nil {
+		log.Error("Failed to parse analytics templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Error("Failed to execute analytics templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
+
+// collectAnalytics computes the analytics selected by the q, from, to and window parameters
+func (s *Server) collectAnalytics(values url.Values) (*alertstore.Analytics, error) {
+	now := time.Now().Round(0)
+	from, to, err := parseAnalyticsWindow(values, now)
+	if err != nil {
+		return nil, err
+	}
+	query, err := alertstore.ParseQuery(values.Get("q"), now)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics, err := alertstore.CollectAnalytics(s.AlertStore, query, from, to)
+	if err != nil {
+		return nil, errStore{err}
+	}
+	return analytics, nil
+}
+
+// parseAnalyticsWindow returns the window of the from and to parameters. to defaults to now and
+// from to the window parameter before to, a duration like 24h or a number of days like 7d.
+func parseAnalyticsWindow(values url.Values, now time.Time) (time.Time, time.Time, error) {
+	opts, err := parseListOptions(url.Values{"from": {values.Get("from")}, "to": {values.Get("to")}})
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	from, to := opts.From, opts.To
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		window := defaultAnalyticsWindow
+		if value := values.Get("window"); value != "" {
+			if window, err = parseWindow(value); err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+		}
+		from = to.Add(-window)
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+	return from, to, nil
+}
+
+// parseWindow parses a positive duration, d counts days
+func parseWindow(value string) (time.Duration, error) {
+	var window time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil {
+			window = time.Duration(n) * 24 * time.Hour
+		}
+	} else {
+		window, _ = time.ParseDuration(value)
+	}
+	if window <= 0 {
+		return 0, fmt.Errorf("invalid window %q, must be a duration like 24h or 7d", value)
+	}
+	return window, nil
+}
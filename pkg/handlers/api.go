@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/models"
+	"github.com/OpenFero/openfero/pkg/services"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// writeJSON answers with v as JSON
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set(ContentTypeHeader, ApplicationJSONVal)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("Error encoding response", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
+
+// writeAPIError answers with an APIError, the body of all errors of the /api/v1 endpoints
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set(ContentTypeHeader, ApplicationJSONVal)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	body := models.APIError{Status: status, Error: http.StatusText(status), Message: message}
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Error("Error encoding error response", zap.Error(err))
+	}
+}
+
+// errStore marks errors of the alert store, which are server errors
+type errStore struct{ error }
+
+// storeErrorStatus returns the status and message of an error reading the alert store:
+// 500 for alert store errors and 400 for invalid parameters
+func storeErrorStatus(err error) (int, string) {
+	if _, ok := err.(errStore); ok {
+		log.Error("Error retrieving alerts", zap.Error(err))
+		return http.StatusInternalServerError, ""
+	}
+	return http.StatusBadRequest, err.Error()
+}
+
+// writeAPIStoreError answers an error reading the alert store with an APIError
+func writeAPIStoreError(w http.ResponseWriter, err error) {
+	status, message := storeErrorStatus(err)
+	writeAPIError(w, status, message)
+}
+
+// writeStoreError answers an error reading the alert store of a page with a plain text body
+func writeStoreError(w http.ResponseWriter, err error) {
+	status, message := storeErrorStatus(err)
+	http.Error(w, message, status)
+}
+
+// clients returns the client of the cluster parameter, or all clients without one
+func (s *Server) clients(r *http.Request) ([]*kubernetes.Client, bool) {
+	name := r.URL.Query().Get("cluster")
+	if name == "" {
+		return s.Clusters.List(), true
+	}
+	client, ok := s.Clusters.Get(name)
+	if !ok {
+		return nil, false
+	}
+	return []*kubernetes.Client{client}, true
+}
+
+// DefinitionsGetHandler handles GET requests to /api/v1/definitions. It lists the job definitions
+// of all clusters, or of the cluster parameter, sorted by cluster, ConfigMap and key.
+func (s *Server) DefinitionsGetHandler(w http.ResponseWriter, r *http.Request) {
+	clients, ok := s.clients(r)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "cluster not found")
+		return
+	}
+
+	definitions := []models.JobInfo{}
+	for _, client := range clients {
+		definitions = append(definitions, getJobDefinitions(client)...)
+	}
+	s.addLimitStates(definitions)
+	sort.Slice(definitions, func(i, j int) bool {
+		a, b := definitions[i], definitions[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.ConfigMapName != b.ConfigMapName {
+			return a.ConfigMapName < b.ConfigMapName
+		}
+		return a.JobName < b.JobName
+	})
+	writeJSON(w, definitions)
+}
+
+// DefinitionGetHandler handles GET requests to /api/v1/definitions/{configmap}/{key}. It returns the
+// definition of the default cluster, or of the cluster parameter, with the parsed job and the job
+// rendered for an alert named after the key. label parameters like label=namespace=payments add
+// labels to the alert.
+func (s *Server) DefinitionGetHandler(w http.ResponseWriter, r *http.Request) {
+	client := s.Clusters.Default()
+	if name := r.URL.Query().Get("cluster"); name != "" {
+		var ok bool
+		if client, ok = s.Clusters.Get(name); !ok {
+			writeAPIError(w, http.StatusNotFound, "cluster not found")
+			return
+		}
+	}
+	configMapName, key := r.PathValue("configmap"), r.PathValue("key")
+
+	obj, exists, err := client.ConfigMapStore.GetByKey(client.ConfigmapNamespace + "/" + configMapName)
+	if err != nil {
+		log.Error("Error getting configmap from store", zap.String("configmap", configMapName), zap.Error(err))
+		writeAPIError(w, http.StatusInternalServerError, "")
+		return
+	}
+	if !exists {
+		writeAPIError(w, http.StatusNotFound, "definition not found")
+		return
+	}
+	configMap := obj.(*corev1.ConfigMap)
+	if _, ok := configMap.Data[key]; !ok {
+		writeAPIError(w, http.StatusNotFound, "definition not found")
+		return
+	}
+
+	alert := models.Alert{Labels: map[string]string{}}
+	for _, label := range r.URL.Query()["label"] {
+		name, value, ok := strings.Cut(label, "=")
+		if !ok || name == "" {
+			writeAPIError(w, http.StatusBadRequest, "invalid label "+label+", must be name=value")
+			return
+		}
+		alert.Labels[name] = value
+	}
+	alert.Labels["alertname"] = key
+
+	options, err := kubernetes.GetDefinitionOptions(configMap)
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	parsed, err := kubernetes.ParseJobFromConfigMap(configMap, key)
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	rendered, err := services.RenderJob(client, configMap, key, alert)
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	manifest, err := kubernetes.RenderJobManifest(rendered)
+	if err != nil {
+		log.Error("Failed to render job manifest", zap.String("configmap", configMapName), zap.Error(err))
+		writeAPIError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	definition := models.Definition{
+		JobInfo: models.JobInfo{
+			ConfigMapName:     configMapName,
+			JobName:           key,
+			Cluster:           client.Name,
+			RateLimit:         kubernetes.FormatRate(options.RateLimit, options.RateLimitPeriod),
+			BreakerThreshold:  options.CircuitBreakerThreshold,
+			ConcurrencyPolicy: options.ConcurrencyPolicy,
+			LockKey:           options.LockKey,
+		},
+		Job:      parsed,
+		Manifest: manifest,
+	}
+	if containers := parsed.Spec.Template.Spec.Containers; len(containers) > 0 {
+		definition.Image = containers[0].Image
+	}
+	infos := []models.JobInfo{definition.JobInfo}
+	s.addLimitStates(infos)
+	definition.JobInfo = infos[0]
+	writeJSON(w, definition)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/services"
+	"github.com/OpenFero/openfero/pkg/utils"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ApprovalsUIHandler handles GET requests to /approvals
+func (s *Server) ApprovalsUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(ContentTypeHeader, "text/html")
+
+	log.Debug("Processing approvals UI request",
+		zap.String("path", r.URL.Path),
+		zap.String("method", r.Method),
+		zap.String("remoteAddr", r.RemoteAddr))
+
+	var approvals []kubernetes.PendingApproval
+	for _, client := range s.Clusters.List() {
+		approvals = append(approvals, client.ListPendingApprovals()...)
+	}
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].ExpiresAt.Before(approvals[j].ExpiresAt)
+	})
+
+	tmpl, err := template.ParseFiles(
+		"web/templates/approvals.html.templ",
+		"web/templates/navbar.html.templ",
+	)
+	if err != nil {
+		log.Error("Failed to parse approval templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		Title          string
+		ShowSearch     bool
+		Approvals      []kubernetes.PendingApproval
+		ApproverHeader string
+		Approver       string
+		Version        string
+		Commit         string
+		BuildDate      string
+	}{
+		Title:          "Approvals",
+		ShowSearch:     false,
+		Approvals:      approvals,
+		ApproverHeader: s.ApproverHeader,
+		Approver:       s.approverIdentity(r),
+		Version:        buildInformation.Version,
+		Commit:         buildInformation.Commit,
+		BuildDate:      buildInformation.BuildDate,
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Error("Failed to execute approval templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+
+	log.Debug("Approvals UI request completed successfully",
+		zap.String("path", r.URL.Path),
+		zap.Int("approvalCount", len(approvals)))
+}
+
+// ApprovePostHandler handles POST requests to /approvals/{cluster}/{job}/approve
+func (s *Server) ApprovePostHandler(w http.ResponseWriter, r *http.Request) {
+	s.decideApproval(w, r, services.ApproveJob)
+}
+
+// RejectPostHandler handles POST requests to /approvals/{cluster}/{job}/reject
+func (s *Server) RejectPostHandler(w http.ResponseWriter, r *http.Request) {
+	s.decideApproval(w, r, services.RejectJob)
+}
+
+// decideApproval resolves the cluster and approver of a request and applies the decision
+func (s *Server) decideApproval(w http.ResponseWriter, r *http.Request, decide func(*kubernetes.Client, alertstore.Store, string, string) error) {
+	if !sameOrigin(r) {
+		log.Warn("Cross-origin approval decision refused",
+			zap.String("origin", r.Header.Get("Origin")),
+			zap.String("remoteAddr", r.RemoteAddr))
+		http.Error(w, "cross-origin request refused", http.StatusForbidden)
+		return
+	}
+
+	clusterName := utils.SanitizeInput(r.PathValue("cluster"))
+	jobName := utils.SanitizeInput(r.PathValue("job"))
+
+	client, ok := s.Clusters.Get(clusterName)
+	if !ok {
+		http.Error(w, "unknown cluster", http.StatusNotFound)
+		return
+	}
+
+	if s.ApproverHeader == "" {
+		http.Error(w, "approvals are disabled, set --approverHeader", http.StatusForbidden)
+		return
+	}
+	approver := s.approverIdentity(r)
+	if approver == "" {
+		http.Error(w, "missing approver identity header "+s.ApproverHeader, http.StatusForbidden)
+		return
+	}
+
+	if err := decide(client, s.AlertStore, jobName, approver); err != nil {
+		log.Warn("Approval decision failed",
+			zap.String("cluster", clusterName),
+			zap.String("job", jobName),
+			zap.String("approver", approver),
+			zap.Error(err))
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			http.Error(w, "job "+jobName+" was decided concurrently", http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	http.Redirect(w, r, "/approvals", http.StatusSeeOther)
+}
+
+// sameOrigin reports whether a request comes from a page of OpenFero itself. The approver identity
+// header is set by a proxy from a session cookie, so cross-site form posts would carry it, too.
+// Browsers mark such requests with Sec-Fetch-Site or Origin, requests without both are not sent by browsers.
+func sameOrigin(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "":
+	case "same-origin", "none":
+		return true
+	default:
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return u.Host == r.Host
+}
+
+// approverIdentity returns the identity of the person deciding on an approval. Only the header
+// configured with --approverHeader is trusted, it must be set by an authenticating proxy.
+func (s *Server) approverIdentity(r *http.Request) string {
+	if s.ApproverHeader == "" {
+		return ""
+	}
+	return utils.SanitizeInput(strings.TrimSpace(r.Header.Get(s.ApproverHeader)))
+}
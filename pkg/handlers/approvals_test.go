@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "no browser headers", want: true},
+		{name: "same-origin fetch", headers: map[string]string{"Sec-Fetch-Site": "same-origin"}, want: true},
+		{name: "cross-site fetch", headers: map[string]string{"Sec-Fetch-Site": "cross-site"}, want: false},
+		{name: "same-site fetch", headers: map[string]string{"Sec-Fetch-Site": "same-site"}, want: false},
+		{name: "same origin", headers: map[string]string{"Origin": "https://openfero.example.com"}, want: true},
+		{name: "other origin", headers: map[string]string{"Origin": "https://evil.example.com"}, want: false},
+		{name: "null origin", headers: map[string]string{"Origin": "null"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "https://openfero.example.com/approvals/local/job/approve", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+			if got := sameOrigin(r); got != tt.want {
+				t.Errorf("sameOrigin() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApprovePostHandlerRefusesCrossOrigin(t *testing.T) {
+	server := &Server{ApproverHeader: "X-Forwarded-User"}
+	r := httptest.NewRequest(http.MethodPost, "https://openfero.example.com/approvals/local/job/approve", nil)
+	r.SetPathValue("cluster", "local")
+	r.SetPathValue("job", "job")
+	r.Header.Set("X-Forwarded-User", "jane")
+	r.Header.Set("Origin", "https://evil.example.com")
+	w := httptest.NewRecorder()
+
+	server.ApprovePostHandler(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d; want 403", w.Code)
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// ClusterGetHandler handles GET requests to /api/v1/cluster. It answers with 404
+// if the alert store does not replicate.
+func (s *Server) ClusterGetHandler(w http.ResponseWriter, r *http.Request) {
+	reporter, ok := s.AlertStore.(alertstore.ClusterReporter)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "the alert store does not replicate")
+		return
+	}
+
+	w.Header().Set(ContentTypeHeader, ApplicationJSONVal)
+	if err := json.NewEncoder(w).Encode(reporter.ClusterStatus()); err != nil {
+		log.Error("Error encoding cluster status", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
+
+// ClusterUIHandler handles GET requests to /cluster
+func (s *Server) ClusterUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(ContentTypeHeader, "text/html")
+
+	log.Debug("Processing cluster UI request",
+		zap.String("path", r.URL.Path),
+		zap.String("method", r.Method),
+		zap.String("remoteAddr", r.RemoteAddr))
+
+	var status *alertstore.ClusterStatus
+	if reporter, ok := s.AlertStore.(alertstore.ClusterReporter); ok {
+		clusterStatus := reporter.ClusterStatus()
+		status = &clusterStatus
+	}
+
+	data := struct {
+		Title      string
+		ShowSearch bool
+		Cluster    *alertstore.ClusterStatus
+		Version    string
+		Commit     string
+		BuildDate  string
+	}{
+		Title:      "Cluster",
+		ShowSearch: false,
+		Cluster:    status,
+		Version:    buildInformation.Version,
+		Commit:     buildInformation.Commit,
+		BuildDate:  buildInformation.BuildDate,
+	}
+
+	tmpl, err := template.New("cluster.html.templ").Funcs(template.FuncMap{
+		"milliseconds": func(seconds float64) float64 { return seconds * 1000 },
+	}).ParseFiles(
+		"web/templates/cluster.html.templ",
+		"web/templates/navbar.html.templ",
+	)
+	if err != nil {
+		log.Error("Failed to parse cluster templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Error("Failed to execute cluster templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
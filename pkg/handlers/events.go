@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/models"
+	"go.uber.org/zap"
+)
+
+// keepaliveInterval is how often an idle event stream sends a comment, so proxies keep
+// it open and the stream of a client that went away ends
+const keepaliveInterval = 30 * time.Second
+
+// alertCard is an entry rendered by the alertCard template. SwapOOB marks a card
+// that replaces the card of the same entry on the alerts page.
+type alertCard struct {
+	models.AlertStoreEntry
+	SwapOOB bool
+}
+
+// EventsGetHandler handles GET requests to /api/v1/events. It streams the entries saved and
+// the jobs updated from now on as Server-Sent Events of the types alert and job, optionally
+// filtered by the query q. The data is the entry as JSON, or the alert card with format=html.
+func (s *Server) EventsGetHandler(w http.ResponseWriter, r *http.Request) {
+	query, err := alertstore.ParseQuery(r.URL.Query().Get("q"), time.Now())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var tmpl *template.Template
+	if r.URL.Query().Get("format") == "html" {
+		tmpl, err = template.New("alertCard.html.templ").Funcs(incidentTemplateFuncs).ParseFiles("web/templates/alertCard.html.templ")
+		if err != nil {
+			log.Error("Failed to parse alert card template", zap.Error(err))
+			writeAPIError(w, http.StatusInternalServerError, "")
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	// The stream outlives the write timeout of the server
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("Could not clear the write deadline of the event stream", zap.Error(err))
+	}
+
+	events := s.AlertStore.Subscribe(r.Context())
+	w.Header().Set(ContentTypeHeader, "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Error("Event stream cannot be flushed", zap.Error(err))
+		return
+	}
+	log.Debug("Event stream opened", zap.String("remoteAddr", r.RemoteAddr))
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				log.Debug("Event stream closed", zap.String("remoteAddr", r.RemoteAddr))
+				return
+			}
+			if !query.Matches(event.Entry) {
+				continue
+			}
+			err = writeEvent(w, tmpl, event)
+		case <-keepalive.C:
+			_, err = io.WriteString(w, ": keepalive\n\n")
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			log.Debug("Event stream failed", zap.String("remoteAddr", r.RemoteAddr), zap.Error(err))
+			return
+		}
+	}
+}
+
+// writeEvent writes an event in the Server-Sent Events format, as an alert card if tmpl is set
+func writeEvent(w io.Writer, tmpl *template.Template, event alertstore.Event) error {
+	data, err := json.Marshal(event.Entry)
+	if err != nil {
+		return err
+	}
+	if tmpl != nil {
+		var card alertCard
+		if err := json.Unmarshal(data, &card.AlertStoreEntry); err != nil {
+			return err
+		}
+		card.SwapOOB = event.Type == alertstore.EventJob
+
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, "alertCard", card); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+
+	// Every line of the data needs its own field
+	var b strings.Builder
+	fmt.Fprintf(&b, "event: %s\n", event.Type)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	_, err = io.WriteString(w, b.String())
+	return err
+}
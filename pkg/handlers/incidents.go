@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/models"
+	"go.uber.org/zap"
+)
+
+// maxIncidentEntries bounds the alert store entries grouped into incidents per request
+const maxIncidentEntries = 5000
+
+// incidentTemplateFuncs are the template functions of the incident pages and the alert cards
+var incidentTemplateFuncs = template.FuncMap{
+	// incidentKey returns the incident key of an alert card
+	"incidentKey": func(alert models.Alert) string {
+		return alertstore.IncidentKey(alertstore.Alert{Labels: alert.Labels, Fingerprint: alert.Fingerprint})
+	},
+	// entryKey identifies an alert card, so live updates can replace it
+	"entryKey": func(entry models.AlertStoreEntry) string {
+		return alertstore.EntryKey(alertstore.AlertEntry{
+			Alert:     alertstore.Alert{Labels: entry.Alert.Labels, Fingerprint: entry.Alert.Fingerprint},
+			Status:    entry.Status,
+			Timestamp: entry.Timestamp,
+		})
+	},
+	"formatDuration": func(seconds int64) string {
+		return (time.Duration(seconds) * time.Second).String()
+	},
+}
+
+// IncidentsGetHandler handles GET requests to /api/v1/incidents. It groups the newest entries
+// matching q, from and to into incidents and returns up to limit of them, newest first.
+func (s *Server) IncidentsGetHandler(w http.ResponseWriter, r *http.Request) {
+	incidents, limit, err := s.listIncidents(r)
+	if err != nil {
+		writeAPIStoreError(w, err)
+		return
+	}
+	if len(incidents) > limit {
+		incidents = incidents[:limit]
+	}
+
+	w.Header().Set(ContentTypeHeader, ApplicationJSONVal)
+	if err := json.NewEncoder(w).Encode(incidents); err != nil {
+		log.Error("Error encoding incidents", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
+
+// IncidentGetHandler handles GET requests to /api/v1/incidents/{id}
+func (s *Server) IncidentGetHandler(w http.ResponseWriter, r *http.Request) {
+	incident, err := s.findIncident(r)
+	if err != nil {
+		writeAPIStoreError(w, err)
+		return
+	}
+	if incident == nil {
+		writeAPIError(w, http.StatusNotFound, "incident not found")
+		return
+	}
+
+	w.Header().Set(ContentTypeHeader, ApplicationJSONVal)
+	if err := json.NewEncoder(w).Encode(incident); err != nil {
+		log.Error("Error encoding incident", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
+
+// IncidentsUIHandler handles GET requests to /incidents
+func (s *Server) IncidentsUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(ContentTypeHeader, "text/html")
+
+	log.Debug("Processing incidents UI request",
+		zap.String("path", r.URL.Path),
+		zap.String("method", r.Method),
+		zap.String("remoteAddr", r.RemoteAddr))
+
+	incidents, limit, err := s.listIncidents(r)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if len(incidents) > limit {
+		incidents = incidents[:limit]
+	}
+
+	data := struct {
+		Title      string
+		ShowSearch bool
+		Incidents  []alertstore.Incident
+		Version    string
+		Commit     string
+		BuildDate  string
+	}{
+		Title:      "Incidents",
+		ShowSearch: false,
+		Incidents:  incidents,
+		Version:    buildInformation.Version,
+		Commit:     buildInformation.Commit,
+		BuildDate:  buildInformation.BuildDate,
+	}
+	renderIncidentTemplate(w, "incidents.html.templ", data)
+}
+
+// IncidentUIHandler handles GET requests to /incidents/{id}
+func (s *Server) IncidentUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(ContentTypeHeader, "text/html")
+
+	log.Debug("Processing incident UI request",
+		zap.String("path", r.URL.Path),
+		zap.String("method", r.Method),
+		zap.String("remoteAddr", r.RemoteAddr))
+
+	incident, err := s.findIncident(r)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if incident == nil {
+		http.Error(w, "incident not found", http.StatusNotFound)
+		return
+	}
+
+	data := struct {
+		Title      string
+		ShowSearch bool
+		Incident   *alertstore.Incident
+		Version    string
+		Commit     string
+		BuildDate  string
+	}{
+		Title:      "Incident",
+		ShowSearch: false,
+		Incident:   incident,
+		Version:    buildInformation.Version,
+		Commit:     buildInformation.Commit,
+		BuildDate:  buildInformation.BuildDate,
+	}
+	renderIncidentTemplate(w, "incident.html.templ", data)
+}
+
+// listIncidents groups the entries selected by the q, from and to parameters into incidents and returns
+// the limit parameter. Entries are grouped newest first, so the oldest incident may miss early entries.
+func (s *Server) listIncidents(r *http.Request) ([]alertstore.Incident, int, error) {
+	opts, err := parseListOptions(r.URL.Query())
+	if err != nil {
+		return nil, 0, err
+	}
+	limit := opts.Limit
+	opts.Limit = maxIncidentEntries
+	opts.Cursor = ""
+	opts.Order = alertstore.OrderDesc
+	if _, err := alertstore.NewList(opts, time.Now()); err != nil {
+		return nil, 0, err
+	}
+
+	page, err := s.AlertStore.ListAlerts(opts)
+	if err != nil {
+		return nil, 0, errStore{err}
+	}
+	return alertstore.GroupIncidents(page.Alerts, time.Now()), limit, nil
+}
+
+// findIncident returns the incident of the id path value, nil if there is none. The id may also be
+// an incident key, then the incident of that key at the time of the at parameter or the latest one is returned.
+func (s *Server) findIncident(r *http.Request) (*alertstore.Incident, error) {
+	var at time.Time
+	if value := r.URL.Query().Get("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid at: %w", err)
+		}
+		at = parsed
+	}
+
+	page, err := s.AlertStore.ListAlerts(alertstore.ListOptions{Limit: maxIncidentEntries})
+	if err != nil {
+		return nil, errStore{err}
+	}
+	id := r.PathValue("id")
+	incidents := alertstore.GroupIncidents(page.Alerts, time.Now())
+
+	var latest *alertstore.Incident
+	for i := range incidents {
+		incident := &incidents[i]
+		if incident.ID == id {
+			return incident, nil
+		}
+		if incident.Key != id {
+			continue
+		}
+		if latest == nil {
+			// Incidents are sorted newest first
+			latest = incident
+		}
+		if !at.IsZero() {
+			for _, entry := range incident.Timeline {
+				if entry.Timestamp.Equal(at) {
+					return incident, nil
+				}
+			}
+		}
+	}
+	return latest, nil
+}
+
+// renderIncidentTemplate renders an incident page
+func renderIncidentTemplate(w http.ResponseWriter, name string, data interface{}) {
+	tmpl, err := template.New(name).Funcs(incidentTemplateFuncs).ParseFiles(
+		"web/templates/"+name,
+		"web/templates/navbar.html.templ",
+	)
+	if err != nil {
+		log.Error("Failed to parse incident templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Error("Failed to execute incident templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OpenFero/openfero/pkg/alertstore/memory"
+)
+
+func TestIncidentHandlersRejectInvalidAt(t *testing.T) {
+	server := &Server{AlertStore: memory.NewMemoryStore(10)}
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		contentType string
+	}{
+		{name: "api", handler: server.IncidentGetHandler, contentType: ApplicationJSONVal},
+		{name: "page", handler: server.IncidentUIHandler, contentType: "text/plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/incidents/key?at=yesterday", nil)
+			r.SetPathValue("id", "key")
+			w := httptest.NewRecorder()
+
+			tt.handler(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d; want 400", w.Code)
+			}
+			if contentType := w.Header().Get(ContentTypeHeader); !strings.HasPrefix(contentType, tt.contentType) {
+				t.Errorf("content type = %q; want %s", contentType, tt.contentType)
+			}
+		})
+	}
+}
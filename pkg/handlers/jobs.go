@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/models"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Job states of the jobs API
+const (
+	jobStatePending   = "pending"
+	jobStateSuspended = "suspended"
+	jobStateRunning   = "running"
+	jobStateSucceeded = "succeeded"
+	jobStateFailed    = "failed"
+)
+
+// maxJobAlertEntries bounds the alert store entries searched for the alerts that triggered jobs
+const maxJobAlertEntries = 5000
+
+// errJobNotFound is returned by findJob for a job that is in none of the clusters
+type errJobNotFound struct{}
+
+func (errJobNotFound) Error() string { return "job not found" }
+
+// JobsGetHandler handles GET requests to /api/v1/jobs. It lists the jobs of all clusters,
+// or of the cluster parameter, newest first with their pods and the alert that triggered them.
+func (s *Server) JobsGetHandler(w http.ResponseWriter, r *http.Request) {
+	clients, ok := s.clients(r)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "cluster not found")
+		return
+	}
+	writeJSON(w, s.listJobs(r.Context(), clients))
+}
+
+// JobGetHandler handles GET requests to /api/v1/jobs/{name}. Without the cluster parameter
+// the job is looked up in all clusters in the order of their names.
+func (s *Server) JobGetHandler(w http.ResponseWriter, r *http.Request) {
+	clients, ok := s.clients(r)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "cluster not found")
+		return
+	}
+
+	job, err := s.findJob(r.Context(), clients, r.PathValue("name"))
+	if _, ok := err.(errJobNotFound); ok {
+		writeAPIError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "")
+		return
+	}
+	writeJSON(w, job)
+}
+
+// RunsUIHandler handles GET requests to /runs
+func (s *Server) RunsUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(ContentTypeHeader, "text/html")
+
+	log.Debug("Processing runs UI request",
+		zap.String("path", r.URL.Path),
+		zap.String("method", r.Method),
+		zap.String("remoteAddr", r.RemoteAddr))
+
+	clients, ok := s.clients(r)
+	if !ok {
+		http.Error(w, "cluster not found", http.StatusNotFound)
+		return
+	}
+
+	data := struct {
+		Title      string
+		ShowSearch bool
+		Jobs       []models.Job
+		Version    string
+		Commit     string
+		BuildDate  string
+	}{
+		Title:      "Runs",
+		ShowSearch: false,
+		Jobs:       s.listJobs(r.Context(), clients),
+		Version:    buildInformation.Version,
+		Commit:     buildInformation.Commit,
+		BuildDate:  buildInformation.BuildDate,
+	}
+	renderJobTemplate(w, "runs.html.templ", data)
+}
+
+// RunUIHandler handles GET requests to /runs/{cluster}/{name}
+func (s *Server) RunUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(ContentTypeHeader, "text/html")
+
+	log.Debug("Processing run UI request",
+		zap.String("path", r.URL.Path),
+		zap.String("method", r.Method),
+		zap.String("remoteAddr", r.RemoteAddr))
+
+	client, ok := s.Clusters.Get(r.PathValue("cluster"))
+	if !ok {
+		http.Error(w, "cluster not found", http.StatusNotFound)
+		return
+	}
+	job, err := s.findJob(r.Context(), []*kubernetes.Client{client}, r.PathValue("name"))
+	if _, ok := err.(errJobNotFound); ok {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		Title      string
+		ShowSearch bool
+		Job        *models.Job
+		Version    string
+		Commit     string
+		BuildDate  string
+	}{
+		Title:      "Run",
+		ShowSearch: false,
+		Job:        job,
+		Version:    buildInformation.Version,
+		Commit:     buildInformation.Commit,
+		BuildDate:  buildInformation.BuildDate,
+	}
+	renderJobTemplate(w, "run.html.templ", data)
+}
+
+// listJobs returns the jobs of the clients newest first
+func (s *Server) listJobs(ctx context.Context, clients []*kubernetes.Client) []models.Job {
+	alerts := s.jobAlerts()
+	now := time.Now()
+
+	jobs := []models.Job{}
+	for _, client := range clients {
+		pods, err := client.ListJobPods(ctx, "")
+		if err != nil {
+			log.Warn("Failed to list the pods of jobs, listing jobs without pods",
+				zap.String("cluster", client.Name),
+				zap.Error(err))
+		}
+		for _, obj := range client.JobStore.List() {
+			jobObject := obj.(*batchv1.Job)
+			jobs = append(jobs, newJob(client.Name, jobObject, pods[jobObject.Name], alerts, now))
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+		}
+		return jobs[i].Name < jobs[j].Name
+	})
+	return jobs
+}
+
+// findJob returns the job of the first client that has a job with the name
+func (s *Server) findJob(ctx context.Context, clients []*kubernetes.Client, name string) (*models.Job, error) {
+	for _, client := range clients {
+		obj, exists, err := client.JobStore.GetByKey(client.JobDestinationNamespace + "/" + name)
+		if err != nil {
+			log.Error("Error getting job from store", zap.String("cluster", client.Name), zap.String("job", name), zap.Error(err))
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		pods, err := client.ListJobPods(ctx, name)
+		if err != nil {
+			log.Warn("Failed to list the pods of job",
+				zap.String("cluster", client.Name),
+				zap.String("job", name),
+				zap.Error(err))
+		}
+		job := newJob(client.Name, obj.(*batchv1.Job), pods[name], s.jobAlerts(), time.Now())
+		return &job, nil
+	}
+	return nil, errJobNotFound{}
+}
+
+// jobAlerts returns the newest alert store entries that triggered jobs by job name
+func (s *Server) jobAlerts() map[string]alertstore.AlertEntry {
+	page, err := s.AlertStore.ListAlerts(alertstore.ListOptions{Limit: maxJobAlertEntries})
+	if err != nil {
+		log.Warn("Failed to get the alerts that triggered jobs", zap.Error(err))
+		return nil
+	}
+	alerts := make(map[string]alertstore.AlertEntry)
+	for _, entry := range page.Alerts {
+		if entry.JobInfo == nil || entry.JobInfo.JobName == "" {
+			continue
+		}
+		if _, ok := alerts[entry.JobInfo.JobName]; !ok {
+			alerts[entry.JobInfo.JobName] = entry
+		}
+	}
+	return alerts
+}
+
+// newJob converts a job of a cluster with its pods for the jobs API
+func newJob(cluster string, jobObject *batchv1.Job, pods []corev1.Pod, alerts map[string]alertstore.AlertEntry, now time.Time) models.Job {
+	job := models.Job{
+		Name:        jobObject.Name,
+		Namespace:   jobObject.Namespace,
+		Cluster:     cluster,
+		Definition:  jobObject.Annotations[kubernetes.DefinitionAnnotation],
+		Alertname:   jobObject.Annotations[kubernetes.AlertnameAnnotation],
+		Fingerprint: jobObject.Labels[kubernetes.FingerprintLabel],
+		State:       jobState(jobObject),
+		CreatedAt:   jobObject.CreationTimestamp.Time,
+		Active:      jobObject.Status.Active,
+		Succeeded:   jobObject.Status.Succeeded,
+		Failed:      jobObject.Status.Failed,
+	}
+	if jobObject.Status.StartTime != nil {
+		job.StartedAt = jobObject.Status.StartTime.Time
+	}
+	if jobObject.Status.CompletionTime != nil {
+		job.CompletedAt = jobObject.Status.CompletionTime.Time
+	}
+	for _, condition := range jobObject.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			job.CompletedAt = condition.LastTransitionTime.Time
+		}
+		job.Conditions = append(job.Conditions, models.JobCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+		})
+	}
+	if !job.StartedAt.IsZero() {
+		end := job.CompletedAt
+		if end.IsZero() {
+			end = now
+		}
+		job.DurationSeconds = int64(end.Sub(job.StartedAt) / time.Second)
+	}
+	for _, pod := range pods {
+		job.Pods = append(job.Pods, newJobPod(pod))
+	}
+	if entry, ok := alerts[jobObject.Name]; ok {
+		job.Alert = &entry
+	}
+	return job
+}
+
+// jobState returns the state of a job for the jobs API
+func jobState(jobObject *batchv1.Job) string {
+	if finished, succeeded := kubernetes.GetJobFinished(jobObject); finished {
+		if succeeded {
+			return jobStateSucceeded
+		}
+		return jobStateFailed
+	}
+	if jobObject.Spec.Suspend != nil && *jobObject.Spec.Suspend {
+		return jobStateSuspended
+	}
+	if jobObject.Status.Active > 0 {
+		return jobStateRunning
+	}
+	return jobStatePending
+}
+
+// newJobPod converts a pod of a job with the states of its containers, init containers first
+func newJobPod(pod corev1.Pod) models.JobPod {
+	jobPod := models.JobPod{
+		Name:   pod.Name,
+		Phase:  string(pod.Status.Phase),
+		Reason: pod.Status.Reason,
+		Node:   pod.Spec.NodeName,
+	}
+	if pod.Status.StartTime != nil {
+		jobPod.StartedAt = pod.Status.StartTime.Time
+	}
+	jobPod.Containers = append(containerStates(pod.Spec.InitContainers, pod.Status.InitContainerStatuses, true),
+		containerStates(pod.Spec.Containers, pod.Status.ContainerStatuses, false)...)
+	return jobPod
+}
+
+// containerStates returns the states of containers. Containers without a status yet are waiting.
+func containerStates(containers []corev1.Container, statuses []corev1.ContainerStatus, init bool) []models.ContainerState {
+	byName := make(map[string]corev1.ContainerStatus, len(statuses))
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+
+	states := make([]models.ContainerState, 0, len(containers))
+	for _, container := range containers {
+		state := models.ContainerState{Name: container.Name, Image: container.Image, Init: init, State: "waiting"}
+		status, ok := byName[container.Name]
+		if ok {
+			state.RestartCount = status.RestartCount
+		}
+		switch {
+		case status.State.Terminated != nil:
+			terminated := status.State.Terminated
+			exitCode := terminated.ExitCode
+			state.State = "terminated"
+			state.Reason = terminated.Reason
+			state.Message = terminated.Message
+			state.ExitCode = &exitCode
+			state.StartedAt = terminated.StartedAt.Time
+			state.FinishedAt = terminated.FinishedAt.Time
+		case status.State.Running != nil:
+			state.State = "running"
+			state.StartedAt = status.State.Running.StartedAt.Time
+		case status.State.Waiting != nil:
+			state.Reason = status.State.Waiting.Reason
+			state.Message = status.State.Waiting.Message
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// renderJobTemplate renders a page of the runs UI
+func renderJobTemplate(w http.ResponseWriter, name string, data interface{}) {
+	tmpl, err := template.New(name).Funcs(incidentTemplateFuncs).Funcs(template.FuncMap{
+		"alertIncidentKey": alertstore.IncidentKey,
+		"deref":            func(exitCode *int32) int32 { return *exitCode },
+	}).ParseFiles(
+		"web/templates/"+name,
+		"web/templates/navbar.html.templ",
+	)
+	if err != nil {
+		log.Error("Failed to parse run templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Error("Failed to execute run templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/models"
+	"github.com/OpenFero/openfero/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// BreakerResetPostHandler handles POST requests to /breakers/{cluster}/{configmap}/reset
+func (s *Server) BreakerResetPostHandler(w http.ResponseWriter, r *http.Request) {
+	clusterName := utils.SanitizeInput(r.PathValue("cluster"))
+	configMapName := utils.SanitizeInput(r.PathValue("configmap"))
+
+	if s.Limits == nil {
+		http.Error(w, "dispatch limits are not enabled", http.StatusNotFound)
+		return
+	}
+
+	if !sameOrigin(r) {
+		log.Warn("Cross-origin circuit breaker reset refused",
+			zap.String("origin", r.Header.Get("Origin")),
+			zap.String("remoteAddr", r.RemoteAddr))
+		http.Error(w, "cross-origin request refused", http.StatusForbidden)
+		return
+	}
+	if s.ApproverHeader == "" {
+		http.Error(w, "breaker resets are disabled, set --approverHeader", http.StatusForbidden)
+		return
+	}
+	approver := s.approverIdentity(r)
+	if approver == "" {
+		http.Error(w, "missing approver identity header "+s.ApproverHeader, http.StatusForbidden)
+		return
+	}
+
+	if err := s.Limits.ResetBreaker(clusterName, configMapName); err != nil {
+		log.Warn("Circuit breaker reset failed",
+			zap.String("cluster", clusterName),
+			zap.String("configmap", configMapName),
+			zap.String("approver", approver),
+			zap.Error(err))
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	log.Info("Circuit breaker reset",
+		zap.String("cluster", clusterName),
+		zap.String("configmap", configMapName),
+		zap.String("approver", approver))
+
+	http.Redirect(w, r, "/jobs", http.StatusSeeOther)
+}
+
+// addLimitStates adds the circuit breaker state of each definition to the job infos
+func (s *Server) addLimitStates(jobInfos []models.JobInfo) {
+	if s.Limits == nil {
+		return
+	}
+	for i := range jobInfos {
+		state, ok := s.Limits.State(jobInfos[i].Cluster, jobInfos[i].ConfigMapName)
+		if !ok {
+			continue
+		}
+		jobInfos[i].ConsecutiveFailures = state.ConsecutiveFailures
+		jobInfos[i].BreakerOpen = state.BreakerOpen
+		jobInfos[i].BreakerClosesAt = state.BreakerClosesAt
+	}
+}
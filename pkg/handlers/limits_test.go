@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenFero/openfero/pkg/services"
+)
+
+func TestBreakerResetPostHandlerRefusesCrossOrigin(t *testing.T) {
+	server := &Server{Limits: services.NewLimits(0), ApproverHeader: "X-Forwarded-User"}
+	r := httptest.NewRequest(http.MethodPost, "https://openfero.example.com/breakers/local/definitions/reset", nil)
+	r.SetPathValue("cluster", "local")
+	r.SetPathValue("configmap", "definitions")
+	r.Header.Set("X-Forwarded-User", "jane")
+	r.Header.Set("Sec-Fetch-Site", "cross-site")
+	w := httptest.NewRecorder()
+
+	server.BreakerResetPostHandler(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d; want 403", w.Code)
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// ApplicationNDJSONVal is the content type of alert store snapshots
+const ApplicationNDJSONVal = "application/x-ndjson"
+
+// AlertsExportGetHandler handles GET requests to /api/v1/alerts/export. It streams the
+// entries selected by q, from and to as newline delimited JSON, oldest first.
+// The export is not limited by the write timeout of the server.
+func (s *Server) AlertsExportGetHandler(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseListOptions(r.URL.Query())
+	if err == nil {
+		// Validate the query before the response is started
+		_, err = alertstore.NewList(opts, time.Now())
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Large stores take longer to stream than the write timeout allows
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("Could not clear the write deadline of the export", zap.Error(err))
+	}
+
+	w.Header().Set(ContentTypeHeader, ApplicationNDJSONVal)
+	w.Header().Set("Content-Disposition", `attachment; filename="openfero-alerts.ndjson"`)
+	written, err := alertstore.WriteSnapshot(w, s.AlertStore, opts)
+	if err != nil {
+		// The status is sent already, the client sees a truncated body
+		log.Error("Error exporting alerts", zap.Int("written", written), zap.Error(err))
+		return
+	}
+	log.Debug("Exported alerts", zap.Int("entries", written))
+}
+
+// AlertsImportPostHandler handles POST requests to /api/v1/alerts/import. The body is a
+// snapshot as written by the export of at most MaxImportBytes, entries the store already holds are skipped.
+// The upload is not limited by the read timeout of the server.
+func (s *Server) AlertsImportPostHandler(w http.ResponseWriter, r *http.Request) {
+	// Large snapshots take longer to upload than the read timeout allows, MaxImportBytes bounds them
+	if err := http.NewResponseController(w).SetReadDeadline(time.Time{}); err != nil {
+		log.Warn("Could not clear the read deadline of the import", zap.Error(err))
+	}
+	if s.MaxImportBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.MaxImportBytes)
+	}
+	defer func() {
+		if err := r.Body.Close(); err != nil {
+			log.Error("Failed to close request body", zap.Error(err))
+		}
+	}()
+
+	result, err := alertstore.ImportSnapshot(r.Body, s.AlertStore)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeAPIError(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("snapshot is larger than %d bytes, %d entries were imported", tooLarge.Limit, result.Imported))
+		return
+	}
+	if errors.Is(err, alertstore.ErrInvalidSnapshot) {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Error("Error importing alerts", zap.Int("imported", result.Imported), zap.Error(err))
+		writeAPIError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	log.Info("Imported alerts",
+		zap.Int("imported", result.Imported),
+		zap.Int("duplicates", result.Duplicates))
+	w.Header().Set(ContentTypeHeader, ApplicationJSONVal)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Error("Error encoding import result", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	"github.com/OpenFero/openfero/pkg/alertstore/memory"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	// Initialize logger before running tests
+	if err := log.SetConfig(zap.NewDevelopmentConfig()); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestAlertsImportPostHandlerSlowBody(t *testing.T) {
+	store := memory.NewMemoryStore(10)
+	server := &Server{AlertStore: store, MaxImportBytes: 1 << 20}
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(server.AlertsImportPostHandler))
+	ts.Config.ReadTimeout = 100 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+
+	// Upload the snapshot slower than the read timeout allows
+	body, writer := io.Pipe()
+	go func() {
+		now := time.Now().UTC()
+		for i := range 3 {
+			entry := alertstore.AlertEntry{
+				Alert:     alertstore.Alert{Labels: map[string]string{"alertname": "TestAlert"}},
+				Status:    "firing",
+				Timestamp: now.Add(time.Duration(i) * time.Second),
+			}
+			if err := json.NewEncoder(writer).Encode(entry); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		writer.Close()
+	}()
+
+	resp, err := http.Post(ts.URL, ApplicationNDJSONVal, body)
+	if err != nil {
+		t.Fatalf("import request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(resp.Body)
+		t.Fatalf("status = %d (%s); want 200", resp.StatusCode, message)
+	}
+
+	var result alertstore.ImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode import result: %v", err)
+	}
+	if result.Imported != 3 {
+		t.Errorf("imported = %d; want 3", result.Imported)
+	}
+}
@@ -10,7 +10,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
 	log "github.com/OpenFero/openfero/pkg/logging"
 	"github.com/OpenFero/openfero/pkg/models"
 	"github.com/ghodss/yaml"
@@ -30,8 +33,9 @@ func UIHandler(w http.ResponseWriter, r *http.Request) {
 		zap.String("remoteAddr", r.RemoteAddr))
 
 	// Parse templates
-	tmpl, err := template.ParseFiles(
+	tmpl, err := template.New("alertStore.html.templ").Funcs(incidentTemplateFuncs).ParseFiles(
 		"web/templates/alertStore.html.templ",
+		"web/templates/alertCard.html.templ",
 		"web/templates/navbar.html.templ",
 	)
 	if err != nil {
@@ -42,19 +46,35 @@ func UIHandler(w http.ResponseWriter, r *http.Request) {
 
 	query := r.URL.Query().Get("q")
 	log.Debug("Fetching alerts with query filter", zap.String("query", query))
-	alerts := GetAlerts(query)
+	var alerts []models.AlertStoreEntry
+	var queryError string
+	if _, err := alertstore.ParseQuery(query, time.Now()); err != nil {
+		queryError = err.Error()
+	} else {
+		alerts = GetAlerts(query)
+	}
+	cards := make([]alertCard, len(alerts))
+	for i, alert := range alerts {
+		cards[i] = alertCard{AlertStoreEntry: alert}
+	}
 
 	data := struct {
 		Title      string
 		ShowSearch bool
-		Alerts     []models.AlertStoreEntry
+		Query      string
+		QueryError string
+		EventsURL  string
+		Alerts     []alertCard
 		Version    string
 		Commit     string
 		BuildDate  string
 	}{
 		Title:      "Alerts",
 		ShowSearch: true,
-		Alerts:     alerts,
+		Query:      query,
+		QueryError: queryError,
+		EventsURL:  "/api/v1/events?" + url.Values{"format": {"html"}, "q": {query}}.Encode(),
+		Alerts:     cards,
 		Version:    buildInformation.Version,
 		Commit:     buildInformation.Commit,
 		BuildDate:  buildInformation.BuildDate,
@@ -108,14 +128,68 @@ func (s *Server) JobsUIHandler(w http.ResponseWriter, r *http.Request) {
 		zap.String("method", r.Method),
 		zap.String("remoteAddr", r.RemoteAddr))
 
+	var jobInfos []models.JobInfo
+	for _, client := range s.Clusters.List() {
+		jobInfos = append(jobInfos, getJobDefinitions(client)...)
+	}
+	s.addLimitStates(jobInfos)
+
+	// Parse and execute template
+	tmpl, err := template.ParseFiles(
+		"web/templates/jobs.html.templ",
+		"web/templates/navbar.html.templ",
+	)
+	if err != nil {
+		log.Error("Failed to parse job templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		Title      string
+		ShowSearch bool
+		Jobs       []models.JobInfo
+		Version    string
+		Commit     string
+		BuildDate  string
+	}{
+		Title:      "Jobs",
+		ShowSearch: false,
+		Jobs:       jobInfos,
+		Version:    buildInformation.Version,
+		Commit:     buildInformation.Commit,
+		BuildDate:  buildInformation.BuildDate,
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Error("Failed to execute job templates", zap.Error(err))
+		http.Error(w, "", http.StatusInternalServerError)
+	}
+
+	log.Debug("Jobs UI request completed successfully",
+		zap.String("path", r.URL.Path),
+		zap.Int("jobCount", len(jobInfos)))
+}
+
+// getJobDefinitions lists the job definitions from the ConfigMap store of a cluster
+func getJobDefinitions(client *kubernetes.Client) []models.JobInfo {
 	// Get all ConfigMaps from store
-	configMaps := s.KubeClient.ConfigMapStore.List()
-	log.Debug("Retrieved ConfigMaps from store", zap.Int("count", len(configMaps)))
+	configMaps := client.ConfigMapStore.List()
+	log.Debug("Retrieved ConfigMaps from store",
+		zap.String("cluster", client.Name),
+		zap.Int("count", len(configMaps)))
 
 	var jobInfos []models.JobInfo
 	for _, obj := range configMaps {
 		configMap := obj.(*corev1.ConfigMap)
 
+		options, err := kubernetes.GetDefinitionOptions(configMap)
+		if err != nil {
+			log.Warn("Invalid definition options in configmap",
+				zap.String("configMap", configMap.Name),
+				zap.Error(err))
+		}
+
 		// Process each job definition in ConfigMap
 		for name, jobDef := range configMap.Data {
 			log.Debug("Processing job definition",
@@ -146,11 +220,17 @@ func (s *Server) JobsUIHandler(w http.ResponseWriter, r *http.Request) {
 			if len(jobObject.Spec.Template.Spec.Containers) > 0 {
 				image := jobObject.Spec.Template.Spec.Containers[0].Image
 				jobInfos = append(jobInfos, models.JobInfo{
-					ConfigMapName: configMap.Name,
-					JobName:       name,
-					Image:         image,
+					ConfigMapName:     configMap.Name,
+					JobName:           name,
+					Image:             image,
+					Cluster:           client.Name,
+					RateLimit:         kubernetes.FormatRate(options.RateLimit, options.RateLimitPeriod),
+					BreakerThreshold:  options.CircuitBreakerThreshold,
+					ConcurrencyPolicy: options.ConcurrencyPolicy,
+					LockKey:           options.LockKey,
 				})
 				log.Debug("Added job info",
+					zap.String("cluster", client.Name),
 					zap.String("configMap", configMap.Name),
 					zap.String("jobName", name),
 					zap.String("image", image))
@@ -158,41 +238,7 @@ func (s *Server) JobsUIHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Parse and execute template
-	tmpl, err := template.ParseFiles(
-		"web/templates/jobs.html.templ",
-		"web/templates/navbar.html.templ",
-	)
-	if err != nil {
-		log.Error("Failed to parse job templates", zap.Error(err))
-		http.Error(w, "", http.StatusInternalServerError)
-		return
-	}
-
-	data := struct {
-		Title      string
-		ShowSearch bool
-		Jobs       []models.JobInfo
-		Version    string
-		Commit     string
-		BuildDate  string
-	}{
-		Title:      "Jobs",
-		ShowSearch: false,
-		Jobs:       jobInfos,
-		Version:    buildInformation.Version,
-		Commit:     buildInformation.Commit,
-		BuildDate:  buildInformation.BuildDate,
-	}
-
-	if err := tmpl.Execute(w, data); err != nil {
-		log.Error("Failed to execute job templates", zap.Error(err))
-		http.Error(w, "", http.StatusInternalServerError)
-	}
-
-	log.Debug("Jobs UI request completed successfully",
-		zap.String("path", r.URL.Path),
-		zap.Int("jobCount", len(jobInfos)))
+	return jobInfos
 }
 
 // AssetsHandler serves static assets
@@ -291,17 +337,40 @@ func (s *Server) HealthzGetHandler(w http.ResponseWriter, r *http.Request) {
 func (s *Server) ReadinessGetHandler(w http.ResponseWriter, r *http.Request) {
 	log.Debug("Readiness check requested", zap.String("path", r.URL.Path))
 
-	_, err := s.KubeClient.Clientset.CoreV1().ConfigMaps(s.KubeClient.ConfigmapNamespace).List(context.TODO(), metav1.ListOptions{})
+	client := s.Clusters.Default()
+	_, err := client.Clientset.CoreV1().ConfigMaps(client.ConfigmapNamespace).List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		log.Error("Readiness check failed - unable to list ConfigMaps",
-			zap.String("namespace", s.KubeClient.ConfigmapNamespace),
+			zap.String("namespace", client.ConfigmapNamespace),
 			zap.Error(err))
 		http.Error(w, "", http.StatusInternalServerError)
 		return
 	}
 
+	if checker, ok := s.AlertStore.(alertstore.ReadinessChecker); ok {
+		if err := checker.Ready(); err != nil {
+			log.Error("Readiness check failed - alert store not ready", zap.Error(err))
+			http.Error(w, "", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Remote clusters do not affect readiness, otherwise one unreachable
+	// cluster would stop remediation for all others
+	for _, remote := range s.Clusters.List() {
+		if remote.Name == client.Name {
+			continue
+		}
+		if _, err := remote.Clientset.CoreV1().ConfigMaps(remote.ConfigmapNamespace).List(context.TODO(), metav1.ListOptions{Limit: 1}); err != nil {
+			log.Warn("Remote cluster is not reachable",
+				zap.String("cluster", remote.Name),
+				zap.String("namespace", remote.ConfigmapNamespace),
+				zap.Error(err))
+		}
+	}
+
 	w.Header().Set(ContentTypeHeader, ApplicationJSONVal)
 	w.WriteHeader(http.StatusOK)
 	log.Debug("Readiness check successful",
-		zap.String("namespace", s.KubeClient.ConfigmapNamespace))
+		zap.String("namespace", client.ConfigmapNamespace))
 }
@@ -0,0 +1,198 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ApprovalStateAnnotation holds the approval state of a job
+	ApprovalStateAnnotation = "openfero.io/approval"
+	// ApprovalExpiresAnnotation holds the time until a pending job can be approved
+	ApprovalExpiresAnnotation = "openfero.io/approval-expires"
+	// ApprovalDecidedByAnnotation holds the identity of the person who decided on a job
+	ApprovalDecidedByAnnotation = "openfero.io/approval-decided-by"
+	// ApprovalDecidedAtAnnotation holds the time of the decision
+	ApprovalDecidedAtAnnotation = "openfero.io/approval-decided-at"
+
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalExpired  = "expired"
+)
+
+// PendingApproval describes a suspended job waiting for a decision
+type PendingApproval struct {
+	Cluster   string
+	JobName   string
+	Alertname string
+	Image     string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// AddApprovalGate suspends the job and marks it as waiting for approval until expiresAt
+func AddApprovalGate(jobObject *batchv1.Job, expiresAt time.Time) {
+	suspend := true
+	jobObject.Spec.Suspend = &suspend
+	if jobObject.Annotations == nil {
+		jobObject.Annotations = make(map[string]string)
+	}
+	jobObject.Annotations[ApprovalStateAnnotation] = ApprovalPending
+	jobObject.Annotations[ApprovalExpiresAnnotation] = expiresAt.UTC().Format(time.RFC3339)
+}
+
+// GetApprovalExpiry returns the approval expiry of a job
+func GetApprovalExpiry(jobObject *batchv1.Job) (time.Time, error) {
+	return time.Parse(time.RFC3339, jobObject.Annotations[ApprovalExpiresAnnotation])
+}
+
+// ListPendingApprovals returns all jobs in the job store that wait for approval
+func (c *Client) ListPendingApprovals() []PendingApproval {
+	var pending []PendingApproval
+	for _, obj := range c.JobStore.List() {
+		job := obj.(*batchv1.Job)
+		if job.Annotations[ApprovalStateAnnotation] != ApprovalPending {
+			continue
+		}
+		expiresAt, err := GetApprovalExpiry(job)
+		if err != nil {
+			log.Warn("Pending job has invalid approval expiry",
+				zap.String("job", job.Name),
+				zap.String("cluster", c.Name),
+				zap.Error(err))
+		}
+		approval := PendingApproval{
+			Cluster:   c.Name,
+			JobName:   job.Name,
+			Alertname: job.Annotations[AlertnameAnnotation],
+			CreatedAt: job.CreationTimestamp.Time,
+			ExpiresAt: expiresAt,
+		}
+		if approval.Alertname == "" {
+			// Jobs created by older versions have no provenance annotations
+			approval.Alertname = getJobEnv(job, "OPENFERO_ALERTNAME")
+		}
+		if len(job.Spec.Template.Spec.Containers) > 0 {
+			approval.Image = job.Spec.Template.Spec.Containers[0].Image
+		}
+		pending = append(pending, approval)
+	}
+	return pending
+}
+
+// getPendingJob returns a job from the job store that is still waiting for approval
+func (c *Client) getPendingJob(jobName string) (*batchv1.Job, error) {
+	obj, exists, err := c.JobStore.GetByKey(c.JobDestinationNamespace + "/" + jobName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("job %s not found", jobName)
+	}
+	job := obj.(*batchv1.Job)
+	if job.Annotations[ApprovalStateAnnotation] != ApprovalPending {
+		return nil, fmt.Errorf("job %s is not waiting for approval", jobName)
+	}
+	return job, nil
+}
+
+// ApproveJob records the decision on the job and unsuspends it. The update is conditional on the
+// resource version of the pending job, so of concurrent decisions only the first one succeeds and
+// the others fail with a conflict error.
+func (c *Client) ApproveJob(jobName string, approver string, now time.Time) error {
+	job, err := c.getPendingJob(jobName)
+	if err != nil {
+		return err
+	}
+	if expiresAt, err := GetApprovalExpiry(job); err == nil && now.After(expiresAt) {
+		return fmt.Errorf("approval for job %s expired at %s", jobName, expiresAt.Format(time.RFC3339))
+	}
+
+	approved := job.DeepCopy()
+	approved.Annotations[ApprovalStateAnnotation] = ApprovalApproved
+	approved.Annotations[ApprovalDecidedByAnnotation] = approver
+	approved.Annotations[ApprovalDecidedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	suspend := false
+	approved.Spec.Suspend = &suspend
+
+	log.Info("Approving job", zap.String("job", jobName), zap.String("cluster", c.Name), zap.String("approver", approver))
+	_, err = c.Clientset.BatchV1().Jobs(c.JobDestinationNamespace).Update(context.TODO(), approved, metav1.UpdateOptions{})
+	if err != nil {
+		log.Error("Error approving job", zap.String("job", jobName), zap.String("cluster", c.Name), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// RejectJob deletes a job that is waiting for approval. Like ApproveJob, the deletion fails with a
+// conflict error if the job was changed since it was listed as pending.
+func (c *Client) RejectJob(jobName string, approver string) error {
+	job, err := c.getPendingJob(jobName)
+	if err != nil {
+		return err
+	}
+	log.Info("Rejecting job", zap.String("job", jobName), zap.String("cluster", c.Name), zap.String("approver", approver))
+	propagation := metav1.DeletePropagationBackground
+	err = c.Clientset.BatchV1().Jobs(c.JobDestinationNamespace).Delete(context.TODO(), jobName, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+		Preconditions:     &metav1.Preconditions{UID: &job.UID, ResourceVersion: &job.ResourceVersion},
+	})
+	if err != nil {
+		log.Error("Error rejecting job", zap.String("job", jobName), zap.String("cluster", c.Name), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ExpirePendingApprovals deletes all pending jobs whose approval expired and returns their names.
+// Like RejectJob, each deletion is conditional on the listed job, so a job approved in the meantime
+// is left running and not reported as expired.
+func (c *Client) ExpirePendingApprovals(now time.Time) []string {
+	var expired []string
+	for _, approval := range c.ListPendingApprovals() {
+		if approval.ExpiresAt.IsZero() || now.Before(approval.ExpiresAt) {
+			continue
+		}
+		job, err := c.getPendingJob(approval.JobName)
+		if err != nil {
+			continue
+		}
+		log.Info("Approval expired, deleting job", zap.String("job", approval.JobName), zap.String("cluster", c.Name))
+		propagation := metav1.DeletePropagationBackground
+		err = c.Clientset.BatchV1().Jobs(c.JobDestinationNamespace).Delete(context.TODO(), approval.JobName, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+			Preconditions:     &metav1.Preconditions{UID: &job.UID, ResourceVersion: &job.ResourceVersion},
+		})
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			log.Info("Job was decided before its approval expired", zap.String("job", approval.JobName), zap.String("cluster", c.Name))
+			continue
+		}
+		if err != nil {
+			log.Error("Error deleting expired job", zap.String("job", approval.JobName), zap.String("cluster", c.Name), zap.Error(err))
+			continue
+		}
+		expired = append(expired, approval.JobName)
+	}
+	return expired
+}
+
+// getJobEnv returns the value of an environment variable of the first container
+func getJobEnv(jobObject *batchv1.Job, name string) string {
+	if len(jobObject.Spec.Template.Spec.Containers) == 0 {
+		return ""
+	}
+	for _, env := range jobObject.Spec.Template.Spec.Containers[0].Env {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+	return ""
+}
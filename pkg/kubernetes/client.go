@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
@@ -16,16 +17,20 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 )
 
 // Client represents a Kubernetes client with necessary stores and configuration
 type Client struct {
-	Clientset               kubernetes.Clientset
+	Name                    string
+	Clientset               kubernetes.Interface
 	JobDestinationNamespace string
 	ConfigmapNamespace      string
 	ConfigMapStore          cache.Store
 	JobStore                cache.Store
 	LabelSelector           *metav1.LabelSelector
+	DryRun                  bool
+	Recorder                record.EventRecorder
 }
 
 // InitKubeClient initializes a Kubernetes client using in-cluster or kubeconfig
@@ -82,8 +87,9 @@ func GetCurrentNamespace() (string, error) {
 	}
 }
 
-// InitConfigMapInformer initializes a ConfigMap informer
-func InitConfigMapInformer(clientset *kubernetes.Clientset, configmapNamespace string, labelSelector *metav1.LabelSelector) cache.Store {
+// InitConfigMapInformer initializes a ConfigMap informer. If the cache does not sync before ctx
+// is done, the informer is stopped and an error is returned.
+func InitConfigMapInformer(ctx context.Context, clientset kubernetes.Interface, configmapNamespace string, labelSelector *metav1.LabelSelector) (cache.Store, error) {
 	// Create informer factory
 	configMapfactory := informers.NewSharedInformerFactoryWithOptions(
 		clientset,
@@ -116,23 +122,27 @@ func InitConfigMapInformer(clientset *kubernetes.Clientset, configmapNamespace s
 			log.Debug("ConfigMap removed from store", zap.String("name", cm.Name))
 		},
 	}); err != nil {
-		log.Fatal("Failed to add ConfigMap event handler", zap.Error(err))
+		return nil, fmt.Errorf("failed to add ConfigMap event handler: %w", err)
 	}
 
 	// Start informer
-	go configMapfactory.Start(context.Background().Done())
+	stop := make(chan struct{})
+	go configMapfactory.Start(stop)
 
 	// Wait for cache sync
-	if !cache.WaitForCacheSync(context.Background().Done(), configMapInformer.HasSynced) {
-		log.Fatal("Failed to sync ConfigMap cache", zap.String("namespace", configmapNamespace))
+	if !cache.WaitForCacheSync(ctx.Done(), configMapInformer.HasSynced) {
+		close(stop)
+		return nil, fmt.Errorf("failed to sync ConfigMap cache of namespace %s", configmapNamespace)
 	}
 	log.Info("ConfigMap cache synced", zap.String("namespace", configmapNamespace))
 
-	return configMapInformer.GetStore()
+	return configMapInformer.GetStore(), nil
 }
 
-// InitJobInformer initializes a Job informer
-func InitJobInformer(clientset *kubernetes.Clientset, jobDestinationNamespace string, labelSelector *metav1.LabelSelector) cache.Store {
+// InitJobInformer initializes a Job informer for the named cluster.
+// Additional event handlers are registered before the informer starts.
+// If the cache does not sync before ctx is done, the informer is stopped and an error is returned.
+func InitJobInformer(ctx context.Context, clientset kubernetes.Interface, clusterName string, jobDestinationNamespace string, labelSelector *metav1.LabelSelector, handlers ...cache.ResourceEventHandler) (cache.Store, error) {
 	// Create informer factory
 	jobFactory := informers.NewSharedInformerFactoryWithOptions(
 		clientset,
@@ -144,6 +154,7 @@ func InitJobInformer(clientset *kubernetes.Clientset, jobDestinationNamespace st
 	)
 
 	log.Debug("Initializing Job informer",
+		zap.String("cluster", clusterName),
 		zap.String("namespace", jobDestinationNamespace),
 		zap.String("labelSelector", metav1.FormatLabelSelector(labelSelector)))
 
@@ -154,19 +165,19 @@ func InitJobInformer(clientset *kubernetes.Clientset, jobDestinationNamespace st
 	if _, err := jobInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			job := obj.(*batchv1.Job)
-			log.Debug("Job added", zap.String("job", job.Name), zap.String("namespace", job.Namespace))
-			metadata.JobsCreatedTotal.Inc()
+			log.Debug("Job added", zap.String("job", job.Name), zap.String("namespace", job.Namespace), zap.String("cluster", clusterName))
+			metadata.JobsCreatedTotal.WithLabelValues(clusterName).Inc()
 		},
 		UpdateFunc: func(old, new interface{}) {
 			oldJob := old.(*batchv1.Job)
 			newJob := new.(*batchv1.Job)
 			if newJob.Status.Succeeded > 0 && oldJob.Status.Succeeded == 0 {
-				log.Debug("Job completed successfully", zap.String("job", newJob.Name), zap.String("namespace", newJob.Namespace))
-				metadata.JobsSucceededTotal.Inc()
+				log.Debug("Job completed successfully", zap.String("job", newJob.Name), zap.String("namespace", newJob.Namespace), zap.String("cluster", clusterName))
+				metadata.JobsSucceededTotal.WithLabelValues(clusterName).Inc()
 			}
 			if newJob.Status.Failed > 0 && oldJob.Status.Failed == 0 {
-				log.Debug("Job failed", zap.String("job", newJob.Name), zap.String("namespace", newJob.Namespace))
-				metadata.JobsFailedTotal.Inc()
+				log.Debug("Job failed", zap.String("job", newJob.Name), zap.String("namespace", newJob.Namespace), zap.String("cluster", clusterName))
+				metadata.JobsFailedTotal.WithLabelValues(clusterName).Inc()
 			}
 		},
 		DeleteFunc: func(obj interface{}) {
@@ -174,17 +185,24 @@ func InitJobInformer(clientset *kubernetes.Clientset, jobDestinationNamespace st
 			log.Debug("Job deleted", zap.String("job", job.Name), zap.String("namespace", job.Namespace))
 		},
 	}); err != nil {
-		log.Fatal("Failed to add Job event handler", zap.Error(err))
+		return nil, fmt.Errorf("failed to add Job event handler: %w", err)
+	}
+	for _, handler := range handlers {
+		if _, err := jobInformer.AddEventHandler(handler); err != nil {
+			return nil, fmt.Errorf("failed to add Job event handler: %w", err)
+		}
 	}
 
 	// Start informer
-	go jobFactory.Start(context.Background().Done())
+	stop := make(chan struct{})
+	go jobFactory.Start(stop)
 
 	// Wait for cache sync
-	if !cache.WaitForCacheSync(context.Background().Done(), jobInformer.HasSynced) {
-		log.Fatal("Failed to sync Job cache", zap.String("namespace", jobDestinationNamespace))
+	if !cache.WaitForCacheSync(ctx.Done(), jobInformer.HasSynced) {
+		close(stop)
+		return nil, fmt.Errorf("failed to sync Job cache of namespace %s", jobDestinationNamespace)
 	}
-	log.Info("Job cache synced", zap.String("namespace", jobDestinationNamespace))
+	log.Info("Job cache synced", zap.String("cluster", clusterName), zap.String("namespace", jobDestinationNamespace))
 
-	return jobInformer.GetStore()
+	return jobInformer.GetStore(), nil
 }
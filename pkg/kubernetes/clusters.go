@@ -0,0 +1,195 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// DefaultClusterName is the name of the cluster OpenFero itself runs in
+	DefaultClusterName = "local"
+	// DefaultClusterLabel is the alert label used to select the target cluster
+	DefaultClusterLabel = "cluster"
+	// ClusterNameAnnotation overrides the cluster name of a kubeconfig Secret
+	ClusterNameAnnotation = "openfero.io/cluster-name"
+	// ConfigMapNamespaceAnnotation overrides the ConfigMap namespace of a kubeconfig Secret
+	ConfigMapNamespaceAnnotation = "openfero.io/configmap-namespace"
+	// JobNamespaceAnnotation overrides the job destination namespace of a kubeconfig Secret
+	JobNamespaceAnnotation = "openfero.io/job-namespace"
+	// KubeconfigSecretKey is the data key holding the kubeconfig in a cluster Secret
+	KubeconfigSecretKey = "kubeconfig"
+)
+
+// ClusterConfig describes how to reach a remote cluster loaded from a kubeconfig Secret
+type ClusterConfig struct {
+	Name                    string
+	RestConfig              *rest.Config
+	ConfigmapNamespace      string
+	JobDestinationNamespace string
+}
+
+// ClusterSet holds one Client per cluster and routes alerts to them
+type ClusterSet struct {
+	clusters    map[string]*Client
+	defaultName string
+	alertLabel  string
+	mutex       sync.RWMutex
+}
+
+// NewClusterSet creates a cluster set with the given default client.
+// Alerts without the alertLabel are dispatched to the default client.
+func NewClusterSet(defaultClient *Client, alertLabel string) *ClusterSet {
+	if alertLabel == "" {
+		alertLabel = DefaultClusterLabel
+	}
+	set := &ClusterSet{
+		clusters:    make(map[string]*Client),
+		defaultName: defaultClient.Name,
+		alertLabel:  alertLabel,
+	}
+	set.Add(defaultClient)
+	return set
+}
+
+// Add registers a client under its name, replacing any existing client with the same name
+func (s *ClusterSet) Add(client *Client) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.clusters[client.Name] = client
+}
+
+// Get returns the client for the named cluster
+func (s *ClusterSet) Get(name string) (*Client, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	client, ok := s.clusters[name]
+	return client, ok
+}
+
+// Default returns the client of the cluster OpenFero runs in
+func (s *ClusterSet) Default() *Client {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.clusters[s.defaultName]
+}
+
+// List returns all clients sorted by cluster name
+func (s *ClusterSet) List() []*Client {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	clients := make([]*Client, 0, len(s.clusters))
+	for _, client := range s.clusters {
+		clients = append(clients, client)
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].Name < clients[j].Name
+	})
+	return clients
+}
+
+// ForAlert selects the client for an alert based on its cluster label.
+// An alert without the label goes to the default cluster, an alert naming
+// an unknown cluster is rejected.
+func (s *ClusterSet) ForAlert(labels map[string]string) (*Client, error) {
+	name, ok := labels[s.alertLabel]
+	if !ok || name == "" {
+		return s.Default(), nil
+	}
+	client, exists := s.Get(name)
+	if !exists {
+		return nil, fmt.Errorf("no credentials loaded for cluster %s", name)
+	}
+	return client, nil
+}
+
+// LoadClusterConfigs reads kubeconfig Secrets matching the label selector and
+// builds a ClusterConfig for each of them. Secrets without a usable kubeconfig
+// are skipped with an error log.
+func LoadClusterConfigs(clientset kubernetes.Interface, namespace string, secretSelector string, configmapNamespace string, jobDestinationNamespace string) ([]ClusterConfig, error) {
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: secretSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list cluster secrets: %w", err)
+	}
+
+	configs := make([]ClusterConfig, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		name := secret.Name
+		if override := secret.Annotations[ClusterNameAnnotation]; override != "" {
+			name = override
+		}
+
+		kubeconfig, ok := secret.Data[KubeconfigSecretKey]
+		if !ok {
+			log.Error("Cluster secret has no kubeconfig",
+				zap.String("secret", secret.Name),
+				zap.String("key", KubeconfigSecretKey))
+			continue
+		}
+
+		restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+		if err != nil {
+			log.Error("Could not parse kubeconfig from cluster secret",
+				zap.String("secret", secret.Name),
+				zap.Error(err))
+			continue
+		}
+
+		config := ClusterConfig{
+			Name:                    name,
+			RestConfig:              restConfig,
+			ConfigmapNamespace:      configmapNamespace,
+			JobDestinationNamespace: jobDestinationNamespace,
+		}
+		if ns := secret.Annotations[ConfigMapNamespaceAnnotation]; ns != "" {
+			config.ConfigmapNamespace = ns
+		}
+		if ns := secret.Annotations[JobNamespaceAnnotation]; ns != "" {
+			config.JobDestinationNamespace = ns
+		}
+
+		log.Debug("Loaded cluster credentials",
+			zap.String("cluster", config.Name),
+			zap.String("secret", secret.Name),
+			zap.String("host", restConfig.Host))
+		configs = append(configs, config)
+	}
+
+	return configs, nil
+}
+
+// NewClient creates a Client for the named cluster and starts its ConfigMap and Job informers.
+// The job handlers are notified about all job events of the cluster. An error is returned if
+// the informer caches do not sync before ctx is done.
+func NewClient(ctx context.Context, name string, clientset kubernetes.Interface, configmapNamespace string, jobDestinationNamespace string, labelSelector *metav1.LabelSelector, jobHandlers ...cache.ResourceEventHandler) (*Client, error) {
+	client := &Client{
+		Name:                    name,
+		Clientset:               clientset,
+		JobDestinationNamespace: jobDestinationNamespace,
+		ConfigmapNamespace:      configmapNamespace,
+		LabelSelector:           labelSelector,
+		Recorder:                NewEventRecorder(clientset),
+	}
+	jobHandlers = append(jobHandlers, jobFailureEventHandler(client))
+	var err error
+	client.ConfigMapStore, err = InitConfigMapInformer(ctx, clientset, configmapNamespace, labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %w", name, err)
+	}
+	client.JobStore, err = InitJobInformer(ctx, clientset, name, jobDestinationNamespace, labelSelector, jobHandlers...)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %w", name, err)
+	}
+	return client, nil
+}
@@ -0,0 +1,206 @@
+package kubernetes
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestMain(m *testing.M) {
+	// Initialize logger before running tests
+	if err := log.SetConfig(zap.NewDevelopmentConfig()); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+// newFakeClient creates a client backed by a fake clientset and plain stores
+func newFakeClient(name string) *Client {
+	return &Client{
+		Name:                    name,
+		Clientset:               fake.NewClientset(),
+		JobDestinationNamespace: "openfero",
+		ConfigmapNamespace:      "openfero",
+		ConfigMapStore:          cache.NewStore(cache.MetaNamespaceKeyFunc),
+		JobStore:                cache.NewStore(cache.MetaNamespaceKeyFunc),
+		LabelSelector:           &metav1.LabelSelector{MatchLabels: map[string]string{"app": "openfero"}},
+	}
+}
+
+func TestClusterSetForAlert(t *testing.T) {
+	local := newFakeClient("local")
+	prod := newFakeClient("prod")
+	clusters := NewClusterSet(local, "cluster")
+	clusters.Add(prod)
+
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "Alert without cluster label goes to default cluster",
+			labels:   map[string]string{"alertname": "Test"},
+			expected: "local",
+		},
+		{
+			name:     "Alert with known cluster label goes to that cluster",
+			labels:   map[string]string{"alertname": "Test", "cluster": "prod"},
+			expected: "prod",
+		},
+		{
+			name:        "Alert with unknown cluster label is rejected",
+			labels:      map[string]string{"alertname": "Test", "cluster": "staging"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := clusters.ForAlert(tt.labels)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ForAlert(%v) expected error, got cluster %s", tt.labels, client.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ForAlert(%v) unexpected error: %v", tt.labels, err)
+			}
+			if client.Name != tt.expected {
+				t.Errorf("ForAlert(%v) = %s; want %s", tt.labels, client.Name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCreateRemediationJobUsesClusterClientset(t *testing.T) {
+	local := newFakeClient("local")
+	prod := newFakeClient("prod")
+	clusters := NewClusterSet(local, "cluster")
+	clusters.Add(prod)
+
+	client, err := clusters.ForAlert(map[string]string{"cluster": "prod"})
+	if err != nil {
+		t.Fatalf("ForAlert failed: %v", err)
+	}
+
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "remediation-abcde"}}
+	if _, err := client.CreateRemediationJob(job); err != nil {
+		t.Fatalf("CreateRemediationJob failed: %v", err)
+	}
+
+	if _, err := prod.Clientset.BatchV1().Jobs("openfero").Get(context.TODO(), job.Name, metav1.GetOptions{}); err != nil {
+		t.Errorf("Job not created in prod cluster: %v", err)
+	}
+	if _, err := local.Clientset.BatchV1().Jobs("openfero").Get(context.TODO(), job.Name, metav1.GetOptions{}); err == nil {
+		t.Errorf("Job unexpectedly created in local cluster")
+	}
+}
+
+func TestLoadClusterConfigs(t *testing.T) {
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: openfero
+current-context: prod
+users:
+- name: openfero
+  user:
+    token: secret-token
+`
+	clientset := fake.NewClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster-prod",
+				Namespace:   "openfero",
+				Labels:      map[string]string{"openfero.io/kubeconfig": "true"},
+				Annotations: map[string]string{ClusterNameAnnotation: "prod", JobNamespaceAnnotation: "remediation"},
+			},
+			Data: map[string][]byte{KubeconfigSecretKey: []byte(kubeconfig)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-broken",
+				Namespace: "openfero",
+				Labels:    map[string]string{"openfero.io/kubeconfig": "true"},
+			},
+			Data: map[string][]byte{"other": []byte("data")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "unrelated",
+				Namespace: "openfero",
+			},
+			Data: map[string][]byte{KubeconfigSecretKey: []byte(kubeconfig)},
+		},
+	)
+
+	configs, err := LoadClusterConfigs(clientset, "openfero", "openfero.io/kubeconfig=true", "openfero", "openfero")
+	if err != nil {
+		t.Fatalf("LoadClusterConfigs failed: %v", err)
+	}
+
+	if len(configs) != 1 {
+		t.Fatalf("LoadClusterConfigs returned %d configs; want 1", len(configs))
+	}
+	if configs[0].Name != "prod" {
+		t.Errorf("cluster name = %s; want prod", configs[0].Name)
+	}
+	if configs[0].RestConfig.Host != "https://prod.example.com:6443" {
+		t.Errorf("cluster host = %s; want https://prod.example.com:6443", configs[0].RestConfig.Host)
+	}
+	if configs[0].JobDestinationNamespace != "remediation" {
+		t.Errorf("job namespace = %s; want remediation", configs[0].JobDestinationNamespace)
+	}
+	if configs[0].ConfigmapNamespace != "openfero" {
+		t.Errorf("configmap namespace = %s; want openfero", configs[0].ConfigmapNamespace)
+	}
+}
+
+func TestNewClientFailsIfCacheDoesNotSync(t *testing.T) {
+	clientset := fake.NewClientset()
+	// The API server of the cluster never answers
+	release := make(chan struct{})
+	defer close(release)
+	clientset.PrependReactor("list", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		<-release
+		return true, nil, context.Canceled
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := NewClient(ctx, "remote", clientset, "openfero", "openfero", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "openfero"}})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Errorf("NewClient succeeded for a cluster that never answers")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("NewClient did not return after its context expired")
+	}
+}
@@ -0,0 +1,112 @@
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"text/template"
+
+	"github.com/OpenFero/openfero/pkg/models"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// ConcurrencyPolicyAllow starts a job even if another job of the same scope is running
+	ConcurrencyPolicyAllow = "Allow"
+	// ConcurrencyPolicyForbid skips the job while another job of the same scope is running
+	ConcurrencyPolicyForbid = "Forbid"
+	// ConcurrencyPolicyReplace deletes the running jobs of the same scope before starting the job
+	ConcurrencyPolicyReplace = "Replace"
+
+	// DefinitionLabel holds the definition ConfigMap a job was created from, hashed if it is not a valid label value
+	DefinitionLabel = "openfero.io/definition"
+	// LockKeyLabel holds the hash of the rendered lock key of a job
+	LockKeyLabel = "openfero.io/lock-key-hash"
+	// FingerprintLabel holds the fingerprint of the alert that triggered a job
+	FingerprintLabel = "openfero.io/fingerprint"
+)
+
+// parseLockKey parses a lock key template, missing alert labels are an error
+func parseLockKey(lockKey string) (*template.Template, error) {
+	return template.New("lockKey").Option("missingkey=error").Parse(lockKey)
+}
+
+// RenderLockKey renders a lock key template such as {{ .Labels.namespace }}/{{ .Labels.deployment }} with the alert
+func RenderLockKey(lockKey string, alert models.Alert) (string, error) {
+	tmpl, err := parseLockKey(lockKey)
+	if err != nil {
+		return "", err
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, alert); err != nil {
+		return "", err
+	}
+	return rendered.String(), nil
+}
+
+// LabelValue returns the value itself if it is a valid label value, otherwise a hash of it
+func LabelValue(value string) string {
+	if len(validation.IsValidLabelValue(value)) == 0 {
+		return value
+	}
+	return hashLabelValue(value)
+}
+
+// hashLabelValue returns a label-safe hash of a value
+func hashLabelValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:40]
+}
+
+// AddProvenanceLabels labels the job with its definition, alert fingerprint and lock key so running jobs can be selected
+func AddProvenanceLabels(jobObject *batchv1.Job, configMapName string, fingerprint string, lockKey string) {
+	if jobObject.Labels == nil {
+		jobObject.Labels = make(map[string]string)
+	}
+	jobObject.Labels[DefinitionLabel] = LabelValue(configMapName)
+	jobObject.Labels[FingerprintLabel] = LabelValue(fingerprint)
+	if lockKey != "" {
+		jobObject.Labels[LockKeyLabel] = hashLabelValue(lockKey)
+		if jobObject.Annotations == nil {
+			jobObject.Annotations = make(map[string]string)
+		}
+		jobObject.Annotations[LockKeyAnnotation] = lockKey
+	}
+}
+
+// ConcurrencyScope returns the label set of jobs that must not run concurrently with a job of the
+// definition. With a lock key the scope spans all definitions, otherwise it is the definition itself.
+func ConcurrencyScope(configMapName string, lockKey string) labels.Set {
+	if lockKey != "" {
+		return labels.Set{LockKeyLabel: hashLabelValue(lockKey)}
+	}
+	return labels.Set{DefinitionLabel: LabelValue(configMapName)}
+}
+
+// AlertScope returns the label set of the jobs started by a definition for one alert
+func AlertScope(configMapName string, fingerprint string) labels.Set {
+	return labels.Set{DefinitionLabel: LabelValue(configMapName), FingerprintLabel: LabelValue(fingerprint)}
+}
+
+// ListRunningJobs returns the unfinished jobs in the job store matching the label set.
+// Jobs waiting for approval count as running, jobs being deleted do not.
+func (c *Client) ListRunningJobs(set labels.Set) []*batchv1.Job {
+	selector := set.AsSelector()
+	var running []*batchv1.Job
+	for _, obj := range c.JobStore.List() {
+		job, ok := obj.(*batchv1.Job)
+		if !ok || job.Namespace != c.JobDestinationNamespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(job.Labels)) {
+			continue
+		}
+		if finished, _ := GetJobFinished(job); finished || job.DeletionTimestamp != nil {
+			continue
+		}
+		running = append(running, job)
+	}
+	return running
+}
@@ -0,0 +1,76 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/OpenFero/openfero/pkg/models"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestRenderLockKey(t *testing.T) {
+	alert := models.Alert{Labels: map[string]string{"namespace": "shop", "deployment": "cart"}}
+
+	key, err := RenderLockKey("{{ .Labels.namespace }}/{{ .Labels.deployment }}", alert)
+	if err != nil || key != "shop/cart" {
+		t.Errorf("RenderLockKey = (%q, %v); want shop/cart", key, err)
+	}
+
+	if _, err := RenderLockKey("{{ .Labels.pod }}", alert); err == nil {
+		t.Errorf("RenderLockKey with a missing label returned no error")
+	}
+}
+
+func TestGetDefinitionOptionsConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		policy      string
+		wantErr     bool
+	}{
+		{name: "default", annotations: nil, policy: ConcurrencyPolicyAllow},
+		{name: "replace", annotations: map[string]string{ConcurrencyPolicyAnnotation: "replace"}, policy: ConcurrencyPolicyReplace},
+		{name: "lock key defaults to forbid", annotations: map[string]string{LockKeyAnnotation: "{{ .Labels.namespace }}"}, policy: ConcurrencyPolicyForbid},
+		{name: "invalid policy", annotations: map[string]string{ConcurrencyPolicyAnnotation: "Sometimes"}, wantErr: true},
+		{name: "invalid lock key", annotations: map[string]string{LockKeyAnnotation: "{{ .Labels"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := GetDefinitionOptions(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDefinitionOptions error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && options.ConcurrencyPolicy != tt.policy {
+				t.Errorf("ConcurrencyPolicy = %q; want %q", options.ConcurrencyPolicy, tt.policy)
+			}
+		})
+	}
+}
+
+func TestListRunningJobs(t *testing.T) {
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	client := &Client{Name: "local", JobDestinationNamespace: "openfero", JobStore: store}
+
+	running := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "openfero"}}
+	AddProvenanceLabels(running, "openfero-testalert-firing", "a1", "shop/cart")
+	finished := running.DeepCopy()
+	finished.Name = "finished"
+	finished.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	other := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "openfero"}}
+	AddProvenanceLabels(other, "openfero-testalert-firing", "b2", "shop/checkout")
+	for _, job := range []*batchv1.Job{running, finished, other} {
+		if err := store.Add(job); err != nil {
+			t.Fatalf("Failed to add job to store: %v", err)
+		}
+	}
+
+	jobs := client.ListRunningJobs(ConcurrencyScope("openfero-otheralert-firing", "shop/cart"))
+	if len(jobs) != 1 || jobs[0].Name != "running" {
+		t.Errorf("running jobs by lock key = %v; want [running]", jobs)
+	}
+	if jobs := client.ListRunningJobs(ConcurrencyScope("openfero-testalert-firing", "")); len(jobs) != 2 {
+		t.Errorf("running jobs by definition = %d; want 2", len(jobs))
+	}
+}
@@ -0,0 +1,150 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/models"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// EpisodeAnnotation holds the start time of the alert episode a job was created for
+	EpisodeAnnotation = "openfero.io/episode"
+	// EpisodeClaimLabel marks the Leases that claim an alert episode for its job
+	EpisodeClaimLabel = "openfero.io/episode-claim"
+	// DefaultEpisodeClaimRetention is how long an episode claim outlives the notifications of its episode
+	DefaultEpisodeClaimRetention = 7 * 24 * time.Hour
+
+	// maxJobNameLength keeps the job-name label set by the Job controller valid
+	maxJobNameLength = 63
+	// episodeHashLength is the length of the episode hash appended to the job name
+	episodeHashLength = 10
+)
+
+// EpisodeKey identifies the notifications of one alert episode for one definition. All Alertmanager
+// peers send the same fingerprint and start time for an episode. Alerts without a start time have no key.
+func EpisodeKey(configMapName string, alert models.Alert) string {
+	if alert.StartsAt == "" {
+		return ""
+	}
+	return configMapName + "/" + alert.GetFingerprint() + "/" + alert.StartsAt
+}
+
+// SetEpisodeJobName names the job after the alert episode, so every replica that receives a
+// notification of the episode creates a job with the same name and only the first create succeeds
+func SetEpisodeJobName(jobObject *batchv1.Job, episodeKey string, startsAt string) {
+	sum := sha256.Sum256([]byte(episodeKey))
+	suffix := hex.EncodeToString(sum[:])[:episodeHashLength]
+
+	baseName := jobObject.Name
+	if maxBase := maxJobNameLength - episodeHashLength - 1; len(baseName) > maxBase {
+		baseName = strings.TrimRight(baseName[:maxBase], "-.")
+	}
+	originalName := jobObject.Name
+	jobObject.SetName(baseName + "-" + suffix)
+
+	if jobObject.Annotations == nil {
+		jobObject.Annotations = make(map[string]string)
+	}
+	jobObject.Annotations[EpisodeAnnotation] = startsAt
+
+	log.Debug("Generated job name for alert episode",
+		zap.String("originalName", originalName),
+		zap.String("generatedName", jobObject.Name))
+}
+
+// ClaimEpisode creates a Lease named after the episode job. It returns false if another replica
+// or an earlier notification already claimed the episode. Unlike the job, the claim is not removed
+// by ttlSecondsAfterFinished, so repeated notifications of the episode do not start the job again.
+func (c *Client) ClaimEpisode(jobName string, startsAt string, now time.Time) (bool, error) {
+	holder, _ := os.Hostname()
+	acquireTime := metav1.NewMicroTime(now)
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        jobName,
+			Namespace:   c.JobDestinationNamespace,
+			Labels:      map[string]string{EpisodeClaimLabel: "true"},
+			Annotations: map[string]string{EpisodeAnnotation: startsAt},
+		},
+		Spec: coordinationv1.LeaseSpec{HolderIdentity: &holder, AcquireTime: &acquireTime},
+	}
+	_, err := c.Clientset.CoordinationV1().Leases(c.JobDestinationNamespace).Create(context.TODO(), lease, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EpisodeClaimed asks the API server whether the episode of a job was claimed
+func (c *Client) EpisodeClaimed(jobName string) (bool, error) {
+	_, err := c.Clientset.CoordinationV1().Leases(c.JobDestinationNamespace).Get(context.TODO(), jobName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseEpisodeClaim deletes the claim of an episode whose job could not be created,
+// so that a later notification of the episode can try again
+func (c *Client) ReleaseEpisodeClaim(jobName string) {
+	err := c.Clientset.CoordinationV1().Leases(c.JobDestinationNamespace).Delete(context.TODO(), jobName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Warn("Could not release episode claim", zap.String("job", jobName), zap.String("cluster", c.Name), zap.Error(err))
+	}
+}
+
+// ExpireEpisodeClaims deletes the episode claims acquired before now minus retention and returns their number
+func (c *Client) ExpireEpisodeClaims(retention time.Duration, now time.Time) int {
+	leases, err := c.Clientset.CoordinationV1().Leases(c.JobDestinationNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: EpisodeClaimLabel + "=true",
+	})
+	if err != nil {
+		log.Warn("Could not list episode claims", zap.String("cluster", c.Name), zap.Error(err))
+		return 0
+	}
+	expired := 0
+	for _, lease := range leases.Items {
+		acquiredAt := lease.CreationTimestamp.Time
+		if lease.Spec.AcquireTime != nil {
+			acquiredAt = lease.Spec.AcquireTime.Time
+		}
+		if now.Sub(acquiredAt) < retention {
+			continue
+		}
+		err := c.Clientset.CoordinationV1().Leases(c.JobDestinationNamespace).Delete(context.TODO(), lease.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Warn("Could not delete expired episode claim", zap.String("lease", lease.Name), zap.String("cluster", c.Name), zap.Error(err))
+			continue
+		}
+		expired++
+	}
+	return expired
+}
+
+// JobExists asks the API server whether a job exists, bypassing the job informer
+func (c *Client) JobExists(jobName string) (bool, error) {
+	_, err := c.Clientset.BatchV1().Jobs(c.JobDestinationNamespace).Get(context.TODO(), jobName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,220 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ShadowAnnotation marks a definition ConfigMap whose jobs are only validated, never created
+	ShadowAnnotation = "openfero.io/shadow"
+	// RequiresApprovalAnnotation marks a definition ConfigMap whose jobs are held until a person approves them
+	RequiresApprovalAnnotation = "openfero.io/requires-approval"
+	// ApprovalTimeoutAnnotation sets how long a job waits for approval before it expires
+	ApprovalTimeoutAnnotation = "openfero.io/approval-timeout"
+
+	// RateLimitAnnotation limits how often a definition runs, in the format <runs>/<duration>, e.g. 3/1h.
+	// Rate limits and circuit breakers are tracked per replica, so N replicas allow N times the runs.
+	RateLimitAnnotation = "openfero.io/rate-limit"
+	// CircuitBreakerThresholdAnnotation sets the number of consecutive failed jobs after which a definition stops running
+	CircuitBreakerThresholdAnnotation = "openfero.io/circuit-breaker-threshold"
+	// CircuitBreakerCooldownAnnotation sets how long an open circuit breaker stays open
+	CircuitBreakerCooldownAnnotation = "openfero.io/circuit-breaker-cooldown"
+
+	// ConcurrencyPolicyAnnotation decides what happens when a job of the definition is already running: Allow, Forbid or Replace
+	ConcurrencyPolicyAnnotation = "openfero.io/concurrency-policy"
+	// LockKeyAnnotation holds a template rendered with the alert, only one job per rendered key runs in a cluster
+	LockKeyAnnotation = "openfero.io/lock-key"
+
+	// CancelOnResolveAnnotation marks a firing definition whose running job is deleted when the alert resolves
+	CancelOnResolveAnnotation = "openfero.io/cancel-on-resolve"
+
+	// DefaultCircuitBreakerCooldown is used when a circuit breaker has no cooldown
+	DefaultCircuitBreakerCooldown = time.Hour
+
+	// DefaultApprovalTimeout is used when a definition requires approval without a timeout
+	DefaultApprovalTimeout = time.Hour
+)
+
+// DefinitionOptions holds the per-definition behaviour configured through
+// annotations on the definition ConfigMap
+type DefinitionOptions struct {
+	// Shadow records the rendered job instead of creating it
+	Shadow bool
+	// RequiresApproval creates the job suspended until it is approved
+	RequiresApproval bool
+	// ApprovalTimeout is the time after which an unapproved job is removed
+	ApprovalTimeout time.Duration
+	// RateLimit is the number of runs allowed per RateLimitPeriod, 0 is unlimited
+	RateLimit       int
+	RateLimitPeriod time.Duration
+	// CircuitBreakerThreshold is the number of consecutive failures that open the breaker, 0 disables it
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is the time after which an open breaker lets a run through again
+	CircuitBreakerCooldown time.Duration
+	// ConcurrencyPolicy is Allow, Forbid or Replace
+	ConcurrencyPolicy string
+	// LockKey is the unrendered lock key template, empty means the definition itself is the lock scope
+	LockKey string
+	// CancelOnResolve deletes the running job of an alert when the alert resolves
+	CancelOnResolve bool
+}
+
+// GetDefinitionOptions parses the OpenFero annotations of a definition ConfigMap
+func GetDefinitionOptions(configMap *corev1.ConfigMap) (DefinitionOptions, error) {
+	options := DefinitionOptions{}
+
+	shadow, err := parseBoolAnnotation(configMap, ShadowAnnotation)
+	if err != nil {
+		return options, err
+	}
+	options.Shadow = shadow
+
+	requiresApproval, err := parseBoolAnnotation(configMap, RequiresApprovalAnnotation)
+	if err != nil {
+		return options, err
+	}
+	options.RequiresApproval = requiresApproval
+
+	approvalTimeout, err := parseDurationAnnotation(configMap, ApprovalTimeoutAnnotation, DefaultApprovalTimeout)
+	if err != nil {
+		return options, err
+	}
+	options.ApprovalTimeout = approvalTimeout
+
+	rateLimit, rateLimitPeriod, err := parseRateAnnotation(configMap, RateLimitAnnotation)
+	if err != nil {
+		return options, err
+	}
+	options.RateLimit = rateLimit
+	options.RateLimitPeriod = rateLimitPeriod
+
+	threshold, err := parseIntAnnotation(configMap, CircuitBreakerThresholdAnnotation)
+	if err != nil {
+		return options, err
+	}
+	options.CircuitBreakerThreshold = threshold
+
+	cooldown, err := parseDurationAnnotation(configMap, CircuitBreakerCooldownAnnotation, DefaultCircuitBreakerCooldown)
+	if err != nil {
+		return options, err
+	}
+	options.CircuitBreakerCooldown = cooldown
+
+	cancelOnResolve, err := parseBoolAnnotation(configMap, CancelOnResolveAnnotation)
+	if err != nil {
+		return options, err
+	}
+	options.CancelOnResolve = cancelOnResolve
+
+	policy, err := parseConcurrencyPolicyAnnotation(configMap, ConcurrencyPolicyAnnotation)
+	if err != nil {
+		return options, err
+	}
+	options.ConcurrencyPolicy = policy
+
+	options.LockKey = configMap.Annotations[LockKeyAnnotation]
+	if options.LockKey != "" {
+		if _, err := parseLockKey(options.LockKey); err != nil {
+			return options, fmt.Errorf("invalid value %q for annotation %s: %w", options.LockKey, LockKeyAnnotation, err)
+		}
+		// A lock key only makes sense if concurrent jobs are not allowed
+		if _, ok := configMap.Annotations[ConcurrencyPolicyAnnotation]; !ok {
+			options.ConcurrencyPolicy = ConcurrencyPolicyForbid
+		}
+	}
+
+	return options, nil
+}
+
+// FormatRate formats a rate limit in the annotation format
+func FormatRate(runs int, period time.Duration) string {
+	if runs <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%s", runs, period)
+}
+
+// parseBoolAnnotation reads a boolean annotation, a missing annotation is false
+func parseBoolAnnotation(configMap *corev1.ConfigMap, key string) (bool, error) {
+	value, ok := configMap.Annotations[key]
+	if !ok || value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q for annotation %s: %w", value, key, err)
+	}
+	return parsed, nil
+}
+
+// parseDurationAnnotation reads a positive duration annotation, a missing annotation returns the fallback
+func parseDurationAnnotation(configMap *corev1.ConfigMap, key string, fallback time.Duration) (time.Duration, error) {
+	value, ok := configMap.Annotations[key]
+	if !ok || value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for annotation %s: %w", value, key, err)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("invalid value %q for annotation %s: must be positive", value, key)
+	}
+	return parsed, nil
+}
+
+// parseIntAnnotation reads a non-negative integer annotation, a missing annotation is 0
+func parseIntAnnotation(configMap *corev1.ConfigMap, key string) (int, error) {
+	value, ok := configMap.Annotations[key]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for annotation %s: %w", value, key, err)
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("invalid value %q for annotation %s: must not be negative", value, key)
+	}
+	return parsed, nil
+}
+
+// parseConcurrencyPolicyAnnotation reads a concurrency policy annotation, a missing annotation is Allow
+func parseConcurrencyPolicyAnnotation(configMap *corev1.ConfigMap, key string) (string, error) {
+	value, ok := configMap.Annotations[key]
+	if !ok || value == "" {
+		return ConcurrencyPolicyAllow, nil
+	}
+	for _, policy := range []string{ConcurrencyPolicyAllow, ConcurrencyPolicyForbid, ConcurrencyPolicyReplace} {
+		if strings.EqualFold(value, policy) {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("invalid value %q for annotation %s: expected Allow, Forbid or Replace", value, key)
+}
+
+// parseRateAnnotation reads a rate annotation in the format <runs>/<duration>
+func parseRateAnnotation(configMap *corev1.ConfigMap, key string) (int, time.Duration, error) {
+	value, ok := configMap.Annotations[key]
+	if !ok || value == "" {
+		return 0, 0, nil
+	}
+	runsValue, periodValue, found := strings.Cut(value, "/")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid value %q for annotation %s: expected <runs>/<duration>", value, key)
+	}
+	runs, err := strconv.Atoi(strings.TrimSpace(runsValue))
+	if err != nil || runs <= 0 {
+		return 0, 0, fmt.Errorf("invalid value %q for annotation %s: runs must be a positive number", value, key)
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(periodValue))
+	if err != nil || period <= 0 {
+		return 0, 0, fmt.Errorf("invalid value %q for annotation %s: period must be a positive duration", value, key)
+	}
+	return runs, period, nil
+}
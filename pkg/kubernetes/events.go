@@ -0,0 +1,80 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// EventComponent is the source component of the events emitted by OpenFero
+	EventComponent = "openfero"
+
+	// EventReasonJobCreated is emitted on the definition and the job when a job was created
+	EventReasonJobCreated = "JobCreated"
+	// EventReasonDefinitionMissing is emitted on the expected definition ConfigMap of a firing alert that has none
+	EventReasonDefinitionMissing = "DefinitionMissing"
+	// EventReasonDefinitionInvalid is emitted on a definition that cannot be turned into a job
+	EventReasonDefinitionInvalid = "DefinitionInvalid"
+	// EventReasonDispatchSuppressed is emitted on a definition whose job was not created because of a dispatch limit
+	EventReasonDispatchSuppressed = "DispatchSuppressed"
+	// EventReasonRemediationFailed is emitted when a job could not be created or failed
+	EventReasonRemediationFailed = "RemediationFailed"
+)
+
+// NewEventRecorder creates an event recorder writing events through the clientset
+func NewEventRecorder(clientset kubernetes.Interface) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: EventComponent})
+}
+
+// RecordEvent emits an event on a definition ConfigMap or a job, it does nothing without a recorder
+func (c *Client) RecordEvent(object runtime.Object, eventType string, reason string, messageFmt string, args ...interface{}) {
+	if c.Recorder == nil || object == nil {
+		return
+	}
+	c.Recorder.Eventf(object, eventType, reason, messageFmt, args...)
+}
+
+// ConfigMapReference refers to a ConfigMap that may not exist, so events can be emitted on it
+func ConfigMapReference(namespace string, name string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: namespace, Name: name}
+}
+
+// AlertEventMessage formats the alert part of an event message
+func AlertEventMessage(alertname string, fingerprint string) string {
+	return fmt.Sprintf("alert %s (fingerprint %s)", alertname, fingerprint)
+}
+
+// jobFailureEventHandler emits RemediationFailed on jobs created by OpenFero when they fail
+func jobFailureEventHandler(client *Client) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldJob, okOld := old.(*batchv1.Job)
+			newJob, okNew := new.(*batchv1.Job)
+			if !okOld || !okNew || newJob.Annotations[DefinitionAnnotation] == "" {
+				return
+			}
+			if finished, _ := GetJobFinished(oldJob); finished {
+				return
+			}
+			if finished, succeeded := GetJobFinished(newJob); finished && !succeeded {
+				log.Debug("Emitting failure event for job", zap.String("job", newJob.Name), zap.String("cluster", client.Name))
+				client.RecordEvent(newJob, corev1.EventTypeWarning, EventReasonRemediationFailed,
+					"Remediation job of %s from definition %s failed",
+					AlertEventMessage(newJob.Annotations[AlertnameAnnotation], newJob.Labels[FingerprintLabel]),
+					newJob.Annotations[DefinitionAnnotation])
+			}
+		},
+	}
+}
@@ -0,0 +1,40 @@
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestJobFailureEventHandler(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	client := &Client{Name: "local", Recorder: recorder}
+	handler := jobFailureEventHandler(client)
+
+	running := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:        "testalert-firing-abcde",
+		Namespace:   "openfero",
+		Annotations: map[string]string{DefinitionAnnotation: "openfero-testalert-firing", AlertnameAnnotation: "TestAlert"},
+		Labels:      map[string]string{FingerprintLabel: "c0ffee"},
+	}}
+	failed := running.DeepCopy()
+	failed.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	succeeded := running.DeepCopy()
+	succeeded.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+
+	handler.OnUpdate(running, succeeded)
+	handler.OnUpdate(running, failed)
+	handler.OnUpdate(failed, failed)
+
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(recorder.Events))
+	}
+	event := <-recorder.Events
+	if !strings.HasPrefix(event, "Warning RemediationFailed") || !strings.Contains(event, "TestAlert (fingerprint c0ffee)") {
+		t.Errorf("event = %q", event)
+	}
+}
@@ -13,33 +13,79 @@ import (
 	"go.uber.org/zap"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// CreateRemediationJob creates a new job in the specified namespace
-func (c *Client) CreateRemediationJob(jobObject *batchv1.Job) error {
+const (
+	// DefinitionAnnotation holds the name of the definition ConfigMap a job was created from
+	DefinitionAnnotation = "openfero.io/definition"
+	// AlertnameAnnotation holds the name of the alert that triggered a job
+	AlertnameAnnotation = "openfero.io/alertname"
+)
+
+// CreateRemediationJob creates a new job in the specified namespace and returns the created job
+func (c *Client) CreateRemediationJob(jobObject *batchv1.Job) (*batchv1.Job, error) {
 	// Check if job already exists
 	_, exists, err := c.JobStore.GetByKey(c.JobDestinationNamespace + "/" + jobObject.Name)
 	if err != nil {
-		log.Error("Error checking job existence", zap.String("job", jobObject.Name), zap.String("namespace", c.JobDestinationNamespace), zap.Error(err))
-		return err
+		log.Error("Error checking job existence", zap.String("job", jobObject.Name), zap.String("namespace", c.JobDestinationNamespace), zap.String("cluster", c.Name), zap.Error(err))
+		return nil, err
 	}
 	if exists {
-		return fmt.Errorf("job %s already exists", jobObject.Name)
+		return nil, apierrors.NewAlreadyExists(batchv1.Resource("jobs"), jobObject.Name)
 	}
 
 	// Create job
 	jobsClient := c.Clientset.BatchV1().Jobs(c.JobDestinationNamespace)
-	log.Info("Creating job", zap.String("job", jobObject.Name), zap.String("namespace", c.JobDestinationNamespace))
-	_, err = jobsClient.Create(context.TODO(), jobObject, metav1.CreateOptions{})
+	log.Info("Creating job", zap.String("job", jobObject.Name), zap.String("namespace", c.JobDestinationNamespace), zap.String("cluster", c.Name))
+	createdJob, err := jobsClient.Create(context.TODO(), jobObject, metav1.CreateOptions{})
+	if err != nil {
+		log.Error("Error creating job", zap.String("job", jobObject.Name), zap.String("namespace", c.JobDestinationNamespace), zap.String("cluster", c.Name), zap.Error(err))
+		return nil, err
+	}
+	log.Info("Job created successfully", zap.String("job", jobObject.Name), zap.String("namespace", c.JobDestinationNamespace), zap.String("cluster", c.Name))
+	return createdJob, nil
+}
+
+// DeleteJob deletes a job, the propagation policy decides how its pods are removed
+func (c *Client) DeleteJob(jobName string, propagation metav1.DeletionPropagation) error {
+	err := c.Clientset.BatchV1().Jobs(c.JobDestinationNamespace).Delete(context.TODO(), jobName, metav1.DeleteOptions{PropagationPolicy: &propagation})
 	if err != nil {
-		log.Error("Error creating job", zap.String("job", jobObject.Name), zap.String("namespace", c.JobDestinationNamespace), zap.Error(err))
+		log.Error("Error deleting job", zap.String("job", jobName), zap.String("namespace", c.JobDestinationNamespace), zap.String("cluster", c.Name), zap.Error(err))
 		return err
 	}
-	log.Info("Job created successfully", zap.String("job", jobObject.Name), zap.String("namespace", c.JobDestinationNamespace))
+	log.Info("Job deleted", zap.String("job", jobName), zap.String("namespace", c.JobDestinationNamespace), zap.String("cluster", c.Name), zap.String("propagation", string(propagation)))
 	return nil
 }
 
+// DryRunRemediationJob submits the job with a server-side dry-run and returns
+// the job as the API server would have persisted it
+func (c *Client) DryRunRemediationJob(jobObject *batchv1.Job) (*batchv1.Job, error) {
+	jobsClient := c.Clientset.BatchV1().Jobs(c.JobDestinationNamespace)
+	log.Info("Dry-run creating job", zap.String("job", jobObject.Name), zap.String("namespace", c.JobDestinationNamespace), zap.String("cluster", c.Name))
+	renderedJob, err := jobsClient.Create(context.TODO(), jobObject, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		log.Error("Error dry-run creating job", zap.String("job", jobObject.Name), zap.String("namespace", c.JobDestinationNamespace), zap.String("cluster", c.Name), zap.Error(err))
+		return nil, err
+	}
+	return renderedJob, nil
+}
+
+// RenderJobManifest renders a job as YAML manifest without server-managed metadata
+func RenderJobManifest(jobObject *batchv1.Job) (string, error) {
+	manifest := jobObject.DeepCopy()
+	manifest.APIVersion = batchv1.SchemeGroupVersion.String()
+	manifest.Kind = "Job"
+	manifest.ManagedFields = nil
+
+	yamlBytes, err := yaml.Marshal(manifest)
+	if err != nil {
+		return "", fmt.Errorf("error while rendering job manifest: %v", err)
+	}
+	return string(yamlBytes), nil
+}
+
 // AddLabelsAsEnvVars adds alert labels as environment variables to the job
 func AddLabelsAsEnvVars(jobObject *batchv1.Job, alert models.Alert) {
 	log.Debug("Adding labels as environment variables", zap.String("job", jobObject.Name), zap.Int("labelCount", len(alert.Labels)))
@@ -51,6 +97,31 @@ func AddLabelsAsEnvVars(jobObject *batchv1.Job, alert models.Alert) {
 	}
 }
 
+// AddProvenanceAnnotations records on the job which definition and alert it was created for
+func AddProvenanceAnnotations(jobObject *batchv1.Job, configMapName string, alertname string) {
+	if jobObject.Annotations == nil {
+		jobObject.Annotations = make(map[string]string)
+	}
+	jobObject.Annotations[DefinitionAnnotation] = configMapName
+	jobObject.Annotations[AlertnameAnnotation] = alertname
+}
+
+// GetJobFinished reports whether a job has finished and whether it succeeded
+func GetJobFinished(jobObject *batchv1.Job) (finished bool, succeeded bool) {
+	for _, condition := range jobObject.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, true
+		case batchv1.JobFailed:
+			return true, false
+		}
+	}
+	return false, false
+}
+
 // CheckJobTTL checks if TTL is set for the job
 func CheckJobTTL(jobObject *batchv1.Job) bool {
 	return jobObject.Spec.TTLSecondsAfterFinished != nil
@@ -80,8 +151,26 @@ func AddJobLabels(jobObject *batchv1.Job, labelSelector *metav1.LabelSelector) {
 	}
 }
 
-// GetJobFromConfigMap extracts a job definition from a ConfigMap
+// GetJobFromConfigMap extracts a job definition from a ConfigMap and gives it a random name suffix
 func GetJobFromConfigMap(configMap *corev1.ConfigMap, alertname string) (*batchv1.Job, error) {
+	jobObject, err := ParseJobFromConfigMap(configMap, alertname)
+	if err != nil {
+		return nil, err
+	}
+
+	// Adding randomString to avoid name conflict
+	randomstring := utils.StringWithCharset(5, utils.Charset)
+	originalName := jobObject.Name
+	jobObject.SetName(jobObject.Name + "-" + randomstring)
+	log.Debug("Generated job name with random suffix",
+		zap.String("originalName", originalName),
+		zap.String("generatedName", jobObject.Name))
+
+	return jobObject, nil
+}
+
+// ParseJobFromConfigMap extracts a job definition from a ConfigMap with the name given in the definition
+func ParseJobFromConfigMap(configMap *corev1.ConfigMap, alertname string) (*batchv1.Job, error) {
 	jobDefinition := configMap.Data[alertname]
 
 	if jobDefinition == "" {
@@ -103,13 +192,5 @@ func GetJobFromConfigMap(configMap *corev1.ConfigMap, alertname string) (*batchv
 		return nil, fmt.Errorf("error while using unmarshal on received job: %v", err)
 	}
 
-	// Adding randomString to avoid name conflict
-	randomstring := utils.StringWithCharset(5, utils.Charset)
-	originalName := jobObject.Name
-	jobObject.SetName(jobObject.Name + "-" + randomstring)
-	log.Debug("Generated job name with random suffix",
-		zap.String("originalName", originalName),
-		zap.String("generatedName", jobObject.Name))
-
 	return jobObject, nil
 }
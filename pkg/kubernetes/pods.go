@@ -0,0 +1,35 @@
+package kubernetes
+
+import (
+	"context"
+	"sort"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListJobPods returns the pods of the jobs in the job namespace by job name, oldest first.
+// With a job name only the pods of that job are listed.
+func (c *Client) ListJobPods(ctx context.Context, jobName string) (map[string][]corev1.Pod, error) {
+	selector := batchv1.JobNameLabel
+	if jobName != "" {
+		selector += "=" + jobName
+	}
+	pods, err := c.Clientset.CoreV1().Pods(c.JobDestinationNamespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	byJob := make(map[string][]corev1.Pod)
+	for _, pod := range pods.Items {
+		name := pod.Labels[batchv1.JobNameLabel]
+		byJob[name] = append(byJob[name], pod)
+	}
+	for _, jobPods := range byJob {
+		sort.SliceStable(jobPods, func(i, j int) bool {
+			return jobPods[i].CreationTimestamp.Before(&jobPods[j].CreationTimestamp)
+		})
+	}
+	return byJob, nil
+}
@@ -19,25 +19,125 @@ const (
 )
 
 var (
-	JobsCreatedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	JobsCreatedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 
 		Name: "openfero_jobs_created_total",
 
 		Help: "Total number of jobs created",
-	})
+	}, []string{"cluster"})
 
-	JobsSucceededTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	JobsSucceededTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 
 		Name: "openfero_jobs_succeeded_total",
 
 		Help: "Total number of jobs succeeded",
-	})
+	}, []string{"cluster"})
 
-	JobsFailedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	JobsFailedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 
 		Name: "openfero_jobs_failed_total",
 
 		Help: "Total number of jobs failed",
+	}, []string{"cluster"})
+
+	JobsDryRunTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+
+		Name: "openfero_jobs_dry_run_total",
+
+		Help: "Total number of jobs validated with a dry-run instead of being created",
+	}, []string{"cluster"})
+
+	ApprovalsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+
+		Name: "openfero_approvals_total",
+
+		Help: "Total number of approval decisions on jobs by decision",
+	}, []string{"cluster", "decision"})
+
+	JobsSkippedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+
+		Name: "openfero_jobs_skipped_total",
+
+		Help: "Total number of jobs not created because of a dispatch limit by reason",
+	}, []string{"cluster", "reason"})
+
+	JobsReplacedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+
+		Name: "openfero_jobs_replaced_total",
+
+		Help: "Total number of running jobs deleted by the Replace concurrency policy",
+	}, []string{"cluster"})
+
+	JobsCancelledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+
+		Name: "openfero_jobs_cancelled_total",
+
+		Help: "Total number of running jobs deleted because their alert resolved",
+	}, []string{"cluster"})
+
+	JobCreateRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+
+		Name: "openfero_job_create_retries_total",
+
+		Help: "Total number of retried job creations by Kubernetes status reason",
+	}, []string{"cluster", "reason"})
+
+	JobsDeduplicatedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+
+		Name: "openfero_jobs_deduplicated_total",
+
+		Help: "Total number of notifications ignored because the job of their alert episode already exists",
+	}, []string{"cluster"})
+
+	AlertStoreEvictionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+
+		Name: "openfero_alert_store_evictions_total",
+
+		Help: "Total number of alert store entries evicted by retention rule",
+	}, []string{"rule"})
+
+	AlertStoreEventsDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+
+		Name: "openfero_alert_store_events_dropped_total",
+
+		Help: "Total number of alert store events dropped for subscribers that fell behind",
+	})
+
+	CircuitBreakerOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+
+		Name: "openfero_circuit_breaker_open",
+
+		Help: "Whether the circuit breaker of a definition is open (1) or closed (0)",
+	}, []string{"cluster", "configmap"})
+
+	MemberlistMembers = prometheus.NewGauge(prometheus.GaugeOpts{
+
+		Name: "openfero_memberlist_members",
+
+		Help: "Number of alive members of the memberlist cluster, including this node",
+	})
+
+	MemberlistHealthScore = prometheus.NewGauge(prometheus.GaugeOpts{
+
+		Name: "openfero_memberlist_health_score",
+
+		Help: "Memberlist awareness score of this node, 0 is healthy and higher values mean missed probes",
+	})
+
+	MemberlistBroadcastQueueLength = prometheus.NewGauge(prometheus.GaugeOpts{
+
+		Name: "openfero_memberlist_broadcast_queue_length",
+
+		Help: "Number of alert broadcasts waiting to be gossiped",
+	})
+
+	MemberlistProbeRTTSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+
+		Name: "openfero_memberlist_probe_rtt_seconds",
+
+		Help: "Round trip time of successful memberlist probes of other nodes",
+
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
 	})
 )
 
@@ -96,6 +196,20 @@ func AddMetricsToPrometheusRegistry() {
 	prometheus.MustRegister(JobsCreatedTotal)
 	prometheus.MustRegister(JobsSucceededTotal)
 	prometheus.MustRegister(JobsFailedTotal)
+	prometheus.MustRegister(JobsDryRunTotal)
+	prometheus.MustRegister(ApprovalsTotal)
+	prometheus.MustRegister(JobsSkippedTotal)
+	prometheus.MustRegister(CircuitBreakerOpen)
+	prometheus.MustRegister(JobsReplacedTotal)
+	prometheus.MustRegister(JobsCancelledTotal)
+	prometheus.MustRegister(JobCreateRetriesTotal)
+	prometheus.MustRegister(JobsDeduplicatedTotal)
+	prometheus.MustRegister(AlertStoreEvictionsTotal)
+	prometheus.MustRegister(AlertStoreEventsDroppedTotal)
+	prometheus.MustRegister(MemberlistMembers)
+	prometheus.MustRegister(MemberlistHealthScore)
+	prometheus.MustRegister(MemberlistBroadcastQueueLength)
+	prometheus.MustRegister(MemberlistProbeRTTSeconds)
 	// Get descriptions for all supported metrics.
 	metricsMeta := metrics.All()
 	// Register metrics and retrieve the values in prometheus client
@@ -38,6 +38,8 @@ type Alert struct {
 	StartsAt string `json:"startsAt,omitempty"`
 	// Time when the alert ended
 	EndsAt string `json:"EndsAt,omitempty"`
+	// Alertmanager fingerprint identifying the alert by its labels
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
 
 // AlertStoreEntry represents a stored alert with status and timestamp
@@ -56,6 +58,38 @@ type JobInfo struct {
 	JobName string `json:"jobName"`
 	// Container image used by the job
 	Image string `json:"image"`
+	// Cluster the job is defined in or was created in
+	Cluster string `json:"cluster,omitempty"`
+	// Job was only validated with a server-side dry-run
+	DryRun bool `json:"dryRun,omitempty"`
+	// Rendered job manifest of a dry-run
+	Manifest string `json:"manifest,omitempty"`
+	// Approval state of a job that requires approval
+	Approval *alertstore.Approval `json:"approval,omitempty"`
+	// Why no job was created, e.g. rate-limited
+	SkipReason string `json:"skipReason,omitempty"`
+	// Rendered lock key of the job
+	LockKey string `json:"lockKey,omitempty"`
+	// Running jobs deleted to make room for this job
+	ReplacedJobs []string `json:"replacedJobs,omitempty"`
+	// Time the job was deleted because its alert resolved
+	CancelledAt time.Time `json:"cancelledAt,omitzero"`
+	// Attempts to create the job
+	CreateAttempts []alertstore.CreateAttempt `json:"createAttempts,omitempty"`
+	// Kubernetes status reason if the job could not be created
+	FailureReason string `json:"failureReason,omitempty"`
+	// Concurrency policy of the definition
+	ConcurrencyPolicy string `json:"concurrencyPolicy,omitempty"`
+	// Rate limit of the definition in the format <runs>/<duration>
+	RateLimit string `json:"rateLimit,omitempty"`
+	// Number of consecutive failed jobs after which the circuit breaker opens
+	BreakerThreshold int `json:"breakerThreshold,omitempty"`
+	// Number of consecutive failed jobs of the definition
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+	// Circuit breaker of the definition is open
+	BreakerOpen bool `json:"breakerOpen,omitempty"`
+	// Time at which an open circuit breaker lets a run through again
+	BreakerClosesAt time.Time `json:"breakerClosesAt,omitzero"`
 }
 
 // ToAlertStoreAlert converts an Alert to alertstore.Alert
@@ -65,5 +99,15 @@ func (a *Alert) ToAlertStoreAlert() alertstore.Alert {
 		Annotations: a.Annotations,
 		StartsAt:    a.StartsAt,
 		EndsAt:      a.EndsAt,
+		Fingerprint: a.GetFingerprint(),
 	}
 }
+
+// GetFingerprint returns the Alertmanager fingerprint of the alert. Alerts
+// without one get a fingerprint computed from their sorted labels.
+func (a *Alert) GetFingerprint() string {
+	if a.Fingerprint != "" {
+		return a.Fingerprint
+	}
+	return alertstore.LabelsFingerprint(a.Labels)
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// APIError is the body of all error responses of the /api/v1 endpoints
+type APIError struct {
+	// HTTP status code
+	Status int `json:"status" example:"404"`
+	// HTTP status text
+	Error string `json:"error" example:"Not Found"`
+	// What went wrong
+	Message string `json:"message,omitempty" example:"job not found"`
+}
+
+// Definition is a job definition with its parsed and rendered job
+type Definition struct {
+	JobInfo
+	// Job parsed from the definition
+	Job *batchv1.Job `json:"job"`
+	// Job manifest as OpenFero creates it for an alert, without the random name suffix
+	Manifest string `json:"manifest"`
+}
+
+// Job is a job created by OpenFero
+type Job struct {
+	// Name of the job
+	Name string `json:"name"`
+	// Namespace of the job
+	Namespace string `json:"namespace"`
+	// Cluster the job runs in
+	Cluster string `json:"cluster"`
+	// Name of the ConfigMap of the job definition
+	Definition string `json:"definition,omitempty"`
+	// Name of the alert that triggered the job
+	Alertname string `json:"alertname,omitempty"`
+	// State of the job: pending, suspended, running, succeeded or failed
+	State string `json:"state" enum:"pending,suspended,running,succeeded,failed"`
+	// Time the job was created
+	CreatedAt time.Time `json:"createdAt"`
+	// Time the job started running
+	StartedAt time.Time `json:"startedAt,omitzero"`
+	// Time the job succeeded or failed
+	CompletedAt time.Time `json:"completedAt,omitzero"`
+	// Number of running pods
+	Active int32 `json:"active"`
+	// Number of succeeded pods
+	Succeeded int32 `json:"succeeded"`
+	// Number of failed pods
+	Failed int32 `json:"failed"`
+	// Time from the start to the completion of the job, or to now while it runs
+	DurationSeconds int64 `json:"durationSeconds"`
+	// Alertmanager fingerprint of the alert that triggered the job
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Conditions of the job
+	Conditions []JobCondition `json:"conditions,omitempty"`
+	// Pods of the job, oldest first
+	Pods []JobPod `json:"pods,omitempty"`
+	// Alert store entry of the notification that triggered the job
+	Alert *alertstore.AlertEntry `json:"alert,omitempty"`
+}
+
+// JobCondition is a condition of a job
+type JobCondition struct {
+	// Type of the condition, e.g. Complete or Failed
+	Type string `json:"type"`
+	// Status of the condition: True, False or Unknown
+	Status string `json:"status"`
+	// Reason of the last transition, e.g. BackoffLimitExceeded
+	Reason string `json:"reason,omitempty"`
+	// Message of the last transition
+	Message string `json:"message,omitempty"`
+	// Time of the last transition
+	LastTransitionTime time.Time `json:"lastTransitionTime,omitzero"`
+}
+
+// JobPod is a pod of a job
+type JobPod struct {
+	// Name of the pod
+	Name string `json:"name"`
+	// Phase of the pod, e.g. Running or Failed
+	Phase string `json:"phase"`
+	// Reason of the phase, e.g. Evicted
+	Reason string `json:"reason,omitempty"`
+	// Node the pod is scheduled to
+	Node string `json:"node,omitempty"`
+	// Time the pod was started by the kubelet
+	StartedAt time.Time `json:"startedAt,omitzero"`
+	// Containers of the pod, init containers first
+	Containers []ContainerState `json:"containers"`
+}
+
+// ContainerState is the state of a container of a pod
+type ContainerState struct {
+	// Name of the container
+	Name string `json:"name"`
+	// Image the container runs
+	Image string `json:"image,omitempty"`
+	// Container is an init container
+	Init bool `json:"init,omitempty"`
+	// State of the container: waiting, running or terminated
+	State string `json:"state" enum:"waiting,running,terminated"`
+	// Reason of a waiting or terminated state, e.g. ImagePullBackOff or Completed
+	Reason string `json:"reason,omitempty"`
+	// Message of a waiting or terminated state
+	Message string `json:"message,omitempty"`
+	// Exit code of a terminated container
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Number of restarts of the container
+	RestartCount int32 `json:"restartCount"`
+	// Time the container started running
+	StartedAt time.Time `json:"startedAt,omitzero"`
+	// Time a terminated container finished
+	FinishedAt time.Time `json:"finishedAt,omitzero"`
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/OpenFero/openfero/pkg/alertstore"
 	"github.com/OpenFero/openfero/pkg/kubernetes"
@@ -10,7 +12,9 @@ import (
 	"github.com/OpenFero/openfero/pkg/models"
 	"github.com/OpenFero/openfero/pkg/utils"
 	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 // CheckAlertStatus checks if alert status is valid
@@ -57,7 +61,14 @@ func SaveAlertWithJobInfo(alertStore alertstore.Store, alert models.Alert, statu
 func CreateResponseJob(client *kubernetes.Client, alertStore alertstore.Store, alert models.Alert, status string) {
 	alertname := utils.SanitizeInput(alert.Labels["alertname"])
 	responsesConfigmap := strings.ToLower("openfero-" + alertname + "-" + status)
+
+	// Stop the firing remediation first, the problem is gone
+	if status == "resolved" {
+		CancelFiringJobs(client, alertStore, alert)
+	}
+
 	log.Debug("Loading alert response configmap",
+		zap.String("cluster", client.Name),
 		zap.String("configmap", responsesConfigmap),
 		zap.String("alertname", alertname),
 		zap.String("status", status))
@@ -75,28 +86,241 @@ func CreateResponseJob(client *kubernetes.Client, alertStore alertstore.Store, a
 	}
 	if !exists {
 		log.Error("Configmap not found in store",
+			zap.String("cluster", client.Name),
 			zap.String("configmap", responsesConfigmap),
 			zap.String("namespace", client.ConfigmapNamespace),
 			zap.String("alertname", alertname))
+		// Most alerts have no remediation when they resolve, only a missing firing definition is a problem
+		if status == "firing" {
+			client.RecordEvent(kubernetes.ConfigMapReference(client.ConfigmapNamespace, responsesConfigmap), corev1.EventTypeWarning,
+				kubernetes.EventReasonDefinitionMissing, "No job created for %s, ConfigMap %s not found",
+				kubernetes.AlertEventMessage(alertname, alert.GetFingerprint()), responsesConfigmap)
+		}
 		// Save alert without job info since the configmap doesn't exist
 		SaveAlert(alertStore, alert, status)
 		return
 	}
 
 	configMap := obj.(*corev1.ConfigMap)
+	alertMessage := kubernetes.AlertEventMessage(alertname, alert.GetFingerprint())
+
+	// Get per-definition options from the configmap annotations
+	options, err := kubernetes.GetDefinitionOptions(configMap)
+	if err != nil {
+		log.Error("Invalid definition options in configmap",
+			zap.String("configmap", responsesConfigmap),
+			zap.String("alertname", alertname),
+			zap.Error(err))
+		client.RecordEvent(configMap, corev1.EventTypeWarning, kubernetes.EventReasonDefinitionInvalid,
+			"No job created for %s: %v", alertMessage, err)
+		// Save alert without job info since the definition is invalid
+		SaveAlert(alertStore, alert, status)
+		return
+	}
 
-	// Get job from configmap
-	jobObject, err := kubernetes.GetJobFromConfigMap(configMap, alertname)
+	// Render the lock key with the alert
+	var lockKey string
+	if options.LockKey != "" {
+		lockKey, err = kubernetes.RenderLockKey(options.LockKey, alert)
+		if err != nil {
+			log.Error("Failed to render lock key",
+				zap.String("configmap", responsesConfigmap),
+				zap.String("alertname", alertname),
+				zap.Error(err))
+			client.RecordEvent(configMap, corev1.EventTypeWarning, kubernetes.EventReasonDefinitionInvalid,
+				"No job created for %s, lock key could not be rendered: %v", alertMessage, err)
+			// Save alert without job info since the lock key is required to run the job safely
+			SaveAlert(alertStore, alert, status)
+			return
+		}
+	}
+
+	// Get job from configmap, named after the alert episode if jobs are deduplicated
+	var episodeKey string
+	if deduplicateJobs {
+		episodeKey = kubernetes.EpisodeKey(responsesConfigmap, alert)
+	}
+	var jobObject *batchv1.Job
+	if episodeKey != "" {
+		jobObject, err = kubernetes.ParseJobFromConfigMap(configMap, alertname)
+		if err == nil {
+			kubernetes.SetEpisodeJobName(jobObject, episodeKey, alert.StartsAt)
+		}
+	} else {
+		jobObject, err = kubernetes.GetJobFromConfigMap(configMap, alertname)
+	}
 	if err != nil {
 		log.Error("Failed to get job from configmap",
 			zap.String("configmap", responsesConfigmap),
 			zap.String("alertname", alertname),
 			zap.Error(err))
+		client.RecordEvent(configMap, corev1.EventTypeWarning, kubernetes.EventReasonDefinitionInvalid,
+			"No job created for %s: %v", alertMessage, err)
 		// Save alert without job info since we couldn't get the job
 		SaveAlert(alertStore, alert, status)
 		return
 	}
 
+	prepareJob(client, jobObject, alert, responsesConfigmap, alertname, lockKey)
+
+	// Only record the rendered job in dry-run and shadow mode
+	if client.DryRun || options.Shadow {
+		dryRunResponseJob(client, alertStore, alert, status, configMap, jobObject)
+		return
+	}
+
+	// Hold the job until a person approves it
+	var approval *alertstore.Approval
+	if options.RequiresApproval {
+		expiresAt := time.Now().Add(options.ApprovalTimeout)
+		kubernetes.AddApprovalGate(jobObject, expiresAt)
+		approval = &alertstore.Approval{State: kubernetes.ApprovalPending, ExpiresAt: expiresAt}
+		log.Debug("Job requires approval, creating it suspended",
+			zap.String("job", jobObject.Name),
+			zap.Time("expiresAt", expiresAt))
+	}
+
+	// Another replica or an earlier delivery already created the job of this episode
+	if episodeKey != "" && episodeJobExists(client, jobObject.Name, false) {
+		recordDuplicate(client, alertname, jobObject.Name)
+		return
+	}
+
+	// Apply the concurrency policy to the jobs of the same definition or lock key
+	ok, running := concurrency.acquire(client, responsesConfigmap, lockKey, options.ConcurrencyPolicy, jobObject.Name)
+	if episodeKey != "" && slices.Contains(running, jobObject.Name) {
+		// The running job is the job of this episode, never replace or count it against itself
+		if ok {
+			concurrency.release(client, responsesConfigmap, lockKey, jobObject.Name)
+		}
+		recordDuplicate(client, alertname, jobObject.Name)
+		return
+	}
+	if !ok && episodeKey != "" && episodeJobExists(client, jobObject.Name, true) {
+		recordDuplicate(client, alertname, jobObject.Name)
+		return
+	}
+	if !ok {
+		log.Warn("Skipping remediation job, another job of the same scope is running",
+			zap.String("cluster", client.Name),
+			zap.String("configmap", responsesConfigmap),
+			zap.String("lockKey", lockKey),
+			zap.Strings("runningJobs", running))
+		skipResponseJob(client, alertStore, alert, status, configMap, lockKey, SkipReasonConcurrencyForbidden)
+		return
+	}
+
+	// Enforce rate limits, circuit breakers and the global job cap
+	if dispatchLimits != nil {
+		if reason, ok := dispatchLimits.Acquire(client, responsesConfigmap, jobObject.Name, options); !ok {
+			log.Warn("Skipping remediation job",
+				zap.String("cluster", client.Name),
+				zap.String("configmap", responsesConfigmap),
+				zap.String("alertname", alertname),
+				zap.String("reason", reason))
+			concurrency.release(client, responsesConfigmap, lockKey, jobObject.Name)
+			if episodeKey != "" && episodeJobExists(client, jobObject.Name, true) {
+				recordDuplicate(client, alertname, jobObject.Name)
+				return
+			}
+			skipResponseJob(client, alertStore, alert, status, configMap, lockKey, reason)
+			return
+		}
+	}
+
+	// Claim the episode, the claim outlives the job
+	if episodeKey != "" && !claimEpisode(client, jobObject.Name, alert.StartsAt) {
+		if dispatchLimits != nil {
+			dispatchLimits.Release(client, jobObject.Name)
+		}
+		concurrency.release(client, responsesConfigmap, lockKey, jobObject.Name)
+		recordDuplicate(client, alertname, jobObject.Name)
+		return
+	}
+
+	// Replace the running jobs of the same scope
+	var replaced []string
+	if len(running) > 0 {
+		replaced = replaceJobs(client, running, jobObject)
+		metadata.JobsReplacedTotal.WithLabelValues(client.Name).Add(float64(len(replaced)))
+	}
+
+	// Create the job, retrying transient API errors
+	attempts, createdJob, err := createJobWithRetry(client, jobObject)
+	if err != nil && episodeKey != "" && apierrors.IsAlreadyExists(err) {
+		if dispatchLimits != nil {
+			dispatchLimits.Release(client, jobObject.Name)
+		}
+		concurrency.release(client, responsesConfigmap, lockKey, jobObject.Name)
+		recordDuplicate(client, alertname, jobObject.Name)
+		return
+	}
+	if err != nil {
+		log.Error("Failed to create remediation job",
+			zap.String("job", jobObject.Name),
+			zap.String("alertname", alertname),
+			zap.Int("attempts", len(attempts)),
+			zap.Error(err))
+		if episodeKey != "" {
+			client.ReleaseEpisodeClaim(jobObject.Name)
+		}
+		if dispatchLimits != nil {
+			dispatchLimits.Release(client, jobObject.Name)
+		}
+		concurrency.release(client, responsesConfigmap, lockKey, jobObject.Name)
+		metadata.JobsFailedTotal.WithLabelValues(client.Name).Inc()
+		reason := kubernetes.EventReasonRemediationFailed
+		if apierrors.IsInvalid(err) {
+			reason = kubernetes.EventReasonDefinitionInvalid
+		}
+		client.RecordEvent(configMap, corev1.EventTypeWarning, reason,
+			"Job %s for %s could not be created after %d attempts: %v", jobObject.Name, alertMessage, len(attempts), err)
+		// Save the attempts so the failure reason is visible on the alert
+		SaveAlertWithJobInfo(alertStore, alert, status, &alertstore.JobInfo{
+			ConfigMapName:  responsesConfigmap,
+			JobName:        jobObject.Name,
+			Image:          jobObject.Spec.Template.Spec.Containers[0].Image,
+			Cluster:        client.Name,
+			LockKey:        lockKey,
+			CreateAttempts: attempts,
+			FailureReason:  kubernetes.ErrorReason(err),
+		})
+		return
+	}
+
+	if dispatchLimits != nil {
+		dispatchLimits.Commit(client, jobObject.Name)
+	}
+
+	log.Info("Successfully created remediation job",
+		zap.String("cluster", client.Name),
+		zap.String("job", jobObject.Name),
+		zap.String("alertname", alertname),
+		zap.String("status", status))
+	metadata.JobsCreatedTotal.WithLabelValues(client.Name).Inc()
+	client.RecordEvent(configMap, corev1.EventTypeNormal, kubernetes.EventReasonJobCreated,
+		"Created job %s for %s", jobObject.Name, alertMessage)
+	client.RecordEvent(createdJob, corev1.EventTypeNormal, kubernetes.EventReasonJobCreated,
+		"Created from definition %s for %s", configMap.Name, alertMessage)
+
+	// Create job info for the alert
+	jobInfo := &alertstore.JobInfo{
+		ConfigMapName:  responsesConfigmap,
+		JobName:        jobObject.Name,
+		Image:          jobObject.Spec.Template.Spec.Containers[0].Image,
+		Cluster:        client.Name,
+		Approval:       approval,
+		LockKey:        lockKey,
+		ReplacedJobs:   replaced,
+		CreateAttempts: attempts,
+	}
+
+	// Save the alert with job info
+	SaveAlertWithJobInfo(alertStore, alert, status, jobInfo)
+}
+
+// prepareJob adds the alert labels, the TTL, the labels of the label selector and the provenance to a job
+func prepareJob(client *kubernetes.Client, jobObject *batchv1.Job, alert models.Alert, configMapName string, alertname string, lockKey string) {
 	// Adding alert labels to job
 	kubernetes.AddLabelsAsEnvVars(jobObject, alert)
 	log.Debug("Added alert labels as environment variables to job",
@@ -117,32 +341,89 @@ func CreateResponseJob(client *kubernetes.Client, alertStore alertstore.Store, a
 			zap.Any("labelSelector", client.LabelSelector))
 	}
 
-	// Create the job
-	err = client.CreateRemediationJob(jobObject)
+	// Record where the job comes from so that finished jobs can be attributed to their definition
+	kubernetes.AddProvenanceAnnotations(jobObject, configMapName, alertname)
+	kubernetes.AddProvenanceLabels(jobObject, configMapName, alert.GetFingerprint(), lockKey)
+}
+
+// RenderJob renders the job the key of a definition ConfigMap creates for an alert,
+// without the random name suffix and the approval gate
+func RenderJob(client *kubernetes.Client, configMap *corev1.ConfigMap, key string, alert models.Alert) (*batchv1.Job, error) {
+	options, err := kubernetes.GetDefinitionOptions(configMap)
+	if err != nil {
+		return nil, err
+	}
+	var lockKey string
+	if options.LockKey != "" {
+		if lockKey, err = kubernetes.RenderLockKey(options.LockKey, alert); err != nil {
+			return nil, err
+		}
+	}
+	jobObject, err := kubernetes.ParseJobFromConfigMap(configMap, key)
+	if err != nil {
+		return nil, err
+	}
+	prepareJob(client, jobObject, alert, configMap.Name, key, lockKey)
+	return jobObject, nil
+}
+
+// skipResponseJob records an alert whose job was not created because of a dispatch limit
+func skipResponseJob(client *kubernetes.Client, alertStore alertstore.Store, alert models.Alert, status string, configMap *corev1.ConfigMap, lockKey string, reason string) {
+	metadata.JobsSkippedTotal.WithLabelValues(client.Name, reason).Inc()
+	client.RecordEvent(configMap, corev1.EventTypeWarning, kubernetes.EventReasonDispatchSuppressed,
+		"No job created for %s: %s", kubernetes.AlertEventMessage(utils.SanitizeInput(alert.Labels["alertname"]), alert.GetFingerprint()), reason)
+	SaveAlertWithJobInfo(alertStore, alert, status, &alertstore.JobInfo{
+		ConfigMapName: configMap.Name,
+		Cluster:       client.Name,
+		LockKey:       lockKey,
+		SkipReason:    reason,
+	})
+}
+
+// dryRunResponseJob validates a fully rendered job with a server-side dry-run
+// and records the resulting manifest in the alert store instead of creating it
+func dryRunResponseJob(client *kubernetes.Client, alertStore alertstore.Store, alert models.Alert, status string, configMap *corev1.ConfigMap, jobObject *batchv1.Job) {
+	alertname := utils.SanitizeInput(alert.Labels["alertname"])
+	responsesConfigmap := configMap.Name
+
+	renderedJob, err := client.DryRunRemediationJob(jobObject)
 	if err != nil {
-		log.Error("Failed to create remediation job",
+		log.Error("Failed to dry-run remediation job",
 			zap.String("job", jobObject.Name),
 			zap.String("alertname", alertname),
 			zap.Error(err))
-		metadata.JobsFailedTotal.Inc()
-		// Save alert without job info since job creation failed
+		client.RecordEvent(configMap, corev1.EventTypeWarning, kubernetes.EventReasonDefinitionInvalid,
+			"Dry-run of job %s for %s was rejected: %v",
+			jobObject.Name, kubernetes.AlertEventMessage(alertname, alert.GetFingerprint()), err)
+		metadata.JobsFailedTotal.WithLabelValues(client.Name).Inc()
+		// Save alert without job info since the job was rejected
 		SaveAlert(alertStore, alert, status)
 		return
 	}
 
-	log.Info("Successfully created remediation job",
+	manifest, err := kubernetes.RenderJobManifest(renderedJob)
+	if err != nil {
+		log.Error("Failed to render job manifest",
+			zap.String("job", jobObject.Name),
+			zap.String("alertname", alertname),
+			zap.Error(err))
+	}
+
+	log.Info("Recorded dry-run of remediation job",
+		zap.String("cluster", client.Name),
 		zap.String("job", jobObject.Name),
 		zap.String("alertname", alertname),
 		zap.String("status", status))
-	metadata.JobsCreatedTotal.Inc()
+	metadata.JobsDryRunTotal.WithLabelValues(client.Name).Inc()
 
-	// Create job info for the alert
 	jobInfo := &alertstore.JobInfo{
 		ConfigMapName: responsesConfigmap,
 		JobName:       jobObject.Name,
 		Image:         jobObject.Spec.Template.Spec.Containers[0].Image,
+		Cluster:       client.Name,
+		DryRun:        true,
+		Manifest:      manifest,
 	}
 
-	// Save the alert with job info
 	SaveAlertWithJobInfo(alertStore, alert, status, jobInfo)
 }
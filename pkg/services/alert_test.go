@@ -0,0 +1,528 @@
+package services
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore/memory"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/models"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+)
+
+const testJobDefinition = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: testalert-firing
+spec:
+  template:
+    spec:
+      containers:
+        - name: remediation
+          image: busybox:latest
+      restartPolicy: Never
+`
+
+func TestMain(m *testing.M) {
+	// Initialize logger before running tests
+	if err := log.SetConfig(zap.NewDevelopmentConfig()); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+// newTestClient creates a client with a fake clientset and the given definition configmap
+func newTestClient(t *testing.T, annotations map[string]string) (*kubernetes.Client, *fake.Clientset) {
+	t.Helper()
+	clientset := fake.NewClientset()
+	configMapStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	err := configMapStore.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "openfero-testalert-firing",
+			Namespace:   "openfero",
+			Annotations: annotations,
+		},
+		Data: map[string]string{"TestAlert": testJobDefinition},
+	})
+	if err != nil {
+		t.Fatalf("Failed to add configmap to store: %v", err)
+	}
+
+	client := &kubernetes.Client{
+		Name:                    "local",
+		Clientset:               clientset,
+		JobDestinationNamespace: "openfero",
+		ConfigmapNamespace:      "openfero",
+		ConfigMapStore:          configMapStore,
+		JobStore:                cache.NewStore(cache.MetaNamespaceKeyFunc),
+		LabelSelector:           &metav1.LabelSelector{MatchLabels: map[string]string{"app": "openfero"}},
+	}
+	return client, clientset
+}
+
+// createActions returns all job create actions recorded by the fake clientset
+func createActions(clientset *fake.Clientset) []k8stesting.CreateActionImpl {
+	var actions []k8stesting.CreateActionImpl
+	for _, action := range clientset.Actions() {
+		if create, ok := action.(k8stesting.CreateActionImpl); ok && action.GetResource().Resource == "jobs" {
+			actions = append(actions, create)
+		}
+	}
+	return actions
+}
+
+func TestCreateResponseJobDryRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		dryRun      bool
+		expectDry   bool
+	}{
+		{
+			name:      "Live definition creates the job",
+			expectDry: false,
+		},
+		{
+			name:      "Global dry-run only records the job",
+			dryRun:    true,
+			expectDry: true,
+		},
+		{
+			name:        "Shadow definition only records the job",
+			annotations: map[string]string{kubernetes.ShadowAnnotation: "true"},
+			expectDry:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, clientset := newTestClient(t, tt.annotations)
+			client.DryRun = tt.dryRun
+			store := memory.NewMemoryStore(10)
+			alert := models.Alert{Labels: map[string]string{"alertname": "TestAlert"}}
+
+			CreateResponseJob(client, store, alert, "firing")
+
+			actions := createActions(clientset)
+			if len(actions) != 1 {
+				t.Fatalf("expected 1 job create action, got %d", len(actions))
+			}
+			isDryRun := len(actions[0].GetCreateOptions().DryRun) > 0
+			if isDryRun != tt.expectDry {
+				t.Errorf("create dry-run = %v; want %v", isDryRun, tt.expectDry)
+			}
+
+			entries, err := store.GetAlerts("", 0)
+			if err != nil {
+				t.Fatalf("Failed to get alerts: %v", err)
+			}
+			if len(entries) != 1 || entries[0].JobInfo == nil {
+				t.Fatalf("expected 1 alert with job info, got %+v", entries)
+			}
+			jobInfo := entries[0].JobInfo
+			if jobInfo.DryRun != tt.expectDry {
+				t.Errorf("jobInfo.DryRun = %v; want %v", jobInfo.DryRun, tt.expectDry)
+			}
+			if tt.expectDry && !strings.Contains(jobInfo.Manifest, "image: busybox:latest") {
+				t.Errorf("manifest does not contain rendered job:\n%s", jobInfo.Manifest)
+			}
+		})
+	}
+}
+
+func TestCreateResponseJobRequiresApproval(t *testing.T) {
+	client, clientset := newTestClient(t, map[string]string{
+		kubernetes.RequiresApprovalAnnotation: "true",
+		kubernetes.ApprovalTimeoutAnnotation:  "30m",
+	})
+	store := memory.NewMemoryStore(10)
+	alert := models.Alert{Labels: map[string]string{"alertname": "TestAlert"}}
+
+	CreateResponseJob(client, store, alert, "firing")
+
+	jobs, err := clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(jobs.Items) != 1 {
+		t.Fatalf("expected 1 job, got %v (err %v)", len(jobs.Items), err)
+	}
+	job := jobs.Items[0]
+	if job.Spec.Suspend == nil || !*job.Spec.Suspend {
+		t.Fatalf("job requiring approval was not created suspended")
+	}
+	if job.Annotations[kubernetes.ApprovalStateAnnotation] != kubernetes.ApprovalPending {
+		t.Errorf("approval annotation = %q; want %q", job.Annotations[kubernetes.ApprovalStateAnnotation], kubernetes.ApprovalPending)
+	}
+
+	// The job informer is not running in tests, feed the store directly
+	if err := client.JobStore.Add(&job); err != nil {
+		t.Fatalf("Failed to add job to store: %v", err)
+	}
+
+	if err := ApproveJob(client, store, job.Name, "jane"); err != nil {
+		t.Fatalf("ApproveJob failed: %v", err)
+	}
+
+	approved, err := clientset.BatchV1().Jobs("openfero").Get(context.TODO(), job.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if approved.Spec.Suspend == nil || *approved.Spec.Suspend {
+		t.Errorf("approved job is still suspended")
+	}
+	if approved.Annotations[kubernetes.ApprovalDecidedByAnnotation] != "jane" {
+		t.Errorf("decided-by annotation = %q; want jane", approved.Annotations[kubernetes.ApprovalDecidedByAnnotation])
+	}
+
+	entries, err := store.GetAlerts("", 0)
+	if err != nil {
+		t.Fatalf("Failed to get alerts: %v", err)
+	}
+	approval := entries[0].JobInfo.Approval
+	if approval == nil || approval.State != kubernetes.ApprovalApproved || approval.DecidedBy != "jane" || approval.DecidedAt.IsZero() {
+		t.Errorf("alert store approval = %+v; want approved by jane", approval)
+	}
+	if approval != nil && approval.ExpiresAt.IsZero() {
+		t.Errorf("alert store approval lost its expiry")
+	}
+}
+
+func TestApprovalDecisionsAreConditional(t *testing.T) {
+	client, clientset := newTestClient(t, map[string]string{kubernetes.RequiresApprovalAnnotation: "true"})
+	store := memory.NewMemoryStore(10)
+	alert := models.Alert{Labels: map[string]string{"alertname": "TestAlert"}}
+
+	CreateResponseJob(client, store, alert, "firing")
+
+	jobs, err := clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(jobs.Items) != 1 {
+		t.Fatalf("expected 1 job, got %v (err %v)", len(jobs.Items), err)
+	}
+	job := jobs.Items[0]
+	job.ResourceVersion = "1"
+	if err := client.JobStore.Add(&job); err != nil {
+		t.Fatalf("Failed to add job to store: %v", err)
+	}
+
+	// The fake clientset ignores resource versions, check them like the API server
+	resourceVersion := "1"
+	clientset.PrependReactor("update", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		updated := action.(k8stesting.UpdateAction).GetObject().(*batchv1.Job)
+		if updated.ResourceVersion != resourceVersion {
+			return true, nil, apierrors.NewConflict(batchv1.Resource("jobs"), updated.Name, nil)
+		}
+		resourceVersion = "2"
+		return false, nil, nil
+	})
+	clientset.PrependReactor("delete", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		preconditions := action.(k8stesting.DeleteAction).GetDeleteOptions().Preconditions
+		if preconditions == nil || preconditions.ResourceVersion == nil || *preconditions.ResourceVersion != resourceVersion {
+			return true, nil, apierrors.NewConflict(batchv1.Resource("jobs"), job.Name, nil)
+		}
+		return false, nil, nil
+	})
+
+	if err := ApproveJob(client, store, job.Name, "jane"); err != nil {
+		t.Fatalf("ApproveJob failed: %v", err)
+	}
+	// The job store still lists the job as pending, as if the informer lagged behind
+	if err := ApproveJob(client, store, job.Name, "john"); !apierrors.IsConflict(err) {
+		t.Errorf("second ApproveJob error = %v; want a conflict", err)
+	}
+	if err := RejectJob(client, store, job.Name, "john"); !apierrors.IsConflict(err) {
+		t.Errorf("RejectJob after approval error = %v; want a conflict", err)
+	}
+
+	approved, err := clientset.BatchV1().Jobs("openfero").Get(context.TODO(), job.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if approved.Annotations[kubernetes.ApprovalDecidedByAnnotation] != "jane" {
+		t.Errorf("decided-by annotation = %q; want jane", approved.Annotations[kubernetes.ApprovalDecidedByAnnotation])
+	}
+	entries, err := store.GetAlerts("", 0)
+	if err != nil {
+		t.Fatalf("Failed to get alerts: %v", err)
+	}
+	if approval := entries[0].JobInfo.Approval; approval == nil || approval.DecidedBy != "jane" {
+		t.Errorf("alert store approval = %+v; want approved by jane", approval)
+	}
+}
+
+func TestExpirePendingApprovals(t *testing.T) {
+	client, clientset := newTestClient(t, map[string]string{kubernetes.RequiresApprovalAnnotation: "true"})
+	store := memory.NewMemoryStore(10)
+	alert := models.Alert{Labels: map[string]string{"alertname": "TestAlert"}}
+
+	CreateResponseJob(client, store, alert, "firing")
+
+	jobs, err := clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(jobs.Items) != 1 {
+		t.Fatalf("expected 1 job, got %v (err %v)", len(jobs.Items), err)
+	}
+	job := jobs.Items[0]
+	job.Annotations[kubernetes.ApprovalExpiresAnnotation] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	if err := client.JobStore.Add(&job); err != nil {
+		t.Fatalf("Failed to add job to store: %v", err)
+	}
+
+	if err := ApproveJob(client, store, job.Name, "jane"); err == nil {
+		t.Errorf("ApproveJob succeeded for an expired approval")
+	}
+
+	ExpirePendingApprovals(kubernetes.NewClusterSet(client, ""), store)
+
+	if _, err := clientset.BatchV1().Jobs("openfero").Get(context.TODO(), job.Name, metav1.GetOptions{}); err == nil {
+		t.Errorf("expired job was not deleted")
+	}
+	entries, err := store.GetAlerts("", 0)
+	if err != nil {
+		t.Fatalf("Failed to get alerts: %v", err)
+	}
+	if approval := entries[0].JobInfo.Approval; approval == nil || approval.State != kubernetes.ApprovalExpired {
+		t.Errorf("alert store approval = %+v; want expired", approval)
+	}
+}
+
+func TestExpirePendingApprovalsKeepsApprovedJob(t *testing.T) {
+	client, clientset := newTestClient(t, map[string]string{kubernetes.RequiresApprovalAnnotation: "true"})
+	store := memory.NewMemoryStore(10)
+	alert := models.Alert{Labels: map[string]string{"alertname": "TestAlert"}}
+
+	CreateResponseJob(client, store, alert, "firing")
+
+	jobs, err := clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(jobs.Items) != 1 {
+		t.Fatalf("expected 1 job, got %v (err %v)", len(jobs.Items), err)
+	}
+	job := jobs.Items[0]
+	job.ResourceVersion = "1"
+	job.Annotations[kubernetes.ApprovalExpiresAnnotation] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	if err := client.JobStore.Add(&job); err != nil {
+		t.Fatalf("Failed to add job to store: %v", err)
+	}
+
+	// The job was approved after the job store listed it as pending
+	clientset.PrependReactor("delete", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		preconditions := action.(k8stesting.DeleteAction).GetDeleteOptions().Preconditions
+		if preconditions == nil || preconditions.ResourceVersion == nil || *preconditions.ResourceVersion != "2" {
+			return true, nil, apierrors.NewConflict(batchv1.Resource("jobs"), job.Name, nil)
+		}
+		return false, nil, nil
+	})
+
+	ExpirePendingApprovals(kubernetes.NewClusterSet(client, ""), store)
+
+	if _, err := clientset.BatchV1().Jobs("openfero").Get(context.TODO(), job.Name, metav1.GetOptions{}); err != nil {
+		t.Errorf("approved job was deleted: %v", err)
+	}
+	entries, err := store.GetAlerts("", 0)
+	if err != nil {
+		t.Fatalf("Failed to get alerts: %v", err)
+	}
+	if approval := entries[0].JobInfo.Approval; approval != nil && approval.State == kubernetes.ApprovalExpired {
+		t.Errorf("alert store approval = %+v; want not expired", approval)
+	}
+}
+
+func TestCreateResponseJobConcurrencyPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     string
+		wantJobs   int
+		wantReason string
+	}{
+		{name: "allow", policy: kubernetes.ConcurrencyPolicyAllow, wantJobs: 2},
+		{name: "forbid", policy: kubernetes.ConcurrencyPolicyForbid, wantJobs: 1, wantReason: SkipReasonConcurrencyForbidden},
+		{name: "replace", policy: kubernetes.ConcurrencyPolicyReplace, wantJobs: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, clientset := newTestClient(t, map[string]string{
+				kubernetes.ConcurrencyPolicyAnnotation: tt.policy,
+				kubernetes.LockKeyAnnotation:           "{{ .Labels.namespace }}",
+			})
+			store := memory.NewMemoryStore(10)
+			alert := models.Alert{Labels: map[string]string{"alertname": "TestAlert", "namespace": "shop"}}
+
+			CreateResponseJob(client, store, alert, "firing")
+
+			// The job informer is not running in tests, feed the store directly
+			jobs, err := clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{})
+			if err != nil || len(jobs.Items) != 1 {
+				t.Fatalf("expected 1 job, got %v (err %v)", len(jobs.Items), err)
+			}
+			first := jobs.Items[0]
+			if err := client.JobStore.Add(&first); err != nil {
+				t.Fatalf("Failed to add job to store: %v", err)
+			}
+
+			CreateResponseJob(client, store, alert, "firing")
+
+			jobs, err = clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{})
+			if err != nil || len(jobs.Items) != tt.wantJobs {
+				t.Fatalf("expected %d jobs, got %v (err %v)", tt.wantJobs, len(jobs.Items), err)
+			}
+			if tt.policy == kubernetes.ConcurrencyPolicyReplace && jobs.Items[0].Name == first.Name {
+				t.Errorf("running job %s was not replaced", first.Name)
+			}
+
+			entries, err := store.GetAlerts("", 0)
+			if err != nil {
+				t.Fatalf("Failed to get alerts: %v", err)
+			}
+			latest := entries[0].JobInfo
+			if latest == nil || latest.SkipReason != tt.wantReason || latest.LockKey != "shop" {
+				t.Errorf("latest job info = %+v; want skip reason %q and lock key shop", latest, tt.wantReason)
+			}
+			if tt.policy == kubernetes.ConcurrencyPolicyReplace && (len(latest.ReplacedJobs) != 1 || latest.ReplacedJobs[0] != first.Name) {
+				t.Errorf("replaced jobs = %v; want [%s]", latest.ReplacedJobs, first.Name)
+			}
+		})
+	}
+}
+
+func TestCreateResponseJobCancelOnResolve(t *testing.T) {
+	client, clientset := newTestClient(t, map[string]string{
+		kubernetes.CancelOnResolveAnnotation: "true",
+	})
+	store := memory.NewMemoryStore(10)
+	alert := models.Alert{Labels: map[string]string{"alertname": "TestAlert"}, Fingerprint: "c0ffee"}
+	otherAlert := models.Alert{Labels: map[string]string{"alertname": "TestAlert", "instance": "b"}, Fingerprint: "beef"}
+
+	CreateResponseJob(client, store, alert, "firing")
+	CreateResponseJob(client, store, otherAlert, "firing")
+
+	// The job informer is not running in tests, feed the store directly
+	jobs, err := clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(jobs.Items) != 2 {
+		t.Fatalf("expected 2 jobs, got %v (err %v)", len(jobs.Items), err)
+	}
+	var firingJob string
+	for i := range jobs.Items {
+		if jobs.Items[i].Labels[kubernetes.FingerprintLabel] == "c0ffee" {
+			firingJob = jobs.Items[i].Name
+		}
+		if err := client.JobStore.Add(&jobs.Items[i]); err != nil {
+			t.Fatalf("Failed to add job to store: %v", err)
+		}
+	}
+
+	clientset.ClearActions()
+	CreateResponseJob(client, store, alert, "resolved")
+
+	var deleted []string
+	for _, action := range clientset.Actions() {
+		if deleteAction, ok := action.(k8stesting.DeleteActionImpl); ok {
+			deleted = append(deleted, deleteAction.GetName())
+			policy := deleteAction.GetDeleteOptions().PropagationPolicy
+			if policy == nil || *policy != metav1.DeletePropagationForeground {
+				t.Errorf("job deleted with propagation %v; want Foreground", policy)
+			}
+		}
+	}
+	if len(deleted) != 1 || deleted[0] != firingJob {
+		t.Fatalf("deleted jobs = %v; want [%s]", deleted, firingJob)
+	}
+
+	entries, err := store.GetAlerts("", 0)
+	if err != nil {
+		t.Fatalf("Failed to get alerts: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.JobInfo == nil {
+			continue
+		}
+		cancelled := !entry.JobInfo.CancelledAt.IsZero()
+		if cancelled != (entry.JobInfo.JobName == firingJob) {
+			t.Errorf("job %s cancelled = %v", entry.JobInfo.JobName, cancelled)
+		}
+	}
+}
+
+func TestCreateResponseJobDeduplication(t *testing.T) {
+	SetDeduplication(true)
+	defer SetDeduplication(false)
+
+	// Two replicas share the cluster but have their own job informer
+	replicaA, clientset := newTestClient(t, nil)
+	replicaB := *replicaA
+	replicaB.JobStore = cache.NewStore(cache.MetaNamespaceKeyFunc)
+	store := memory.NewMemoryStore(10)
+
+	alert := models.Alert{
+		Labels:      map[string]string{"alertname": "TestAlert"},
+		StartsAt:    "2026-01-02T03:04:05Z",
+		Fingerprint: "c0ffee",
+	}
+
+	// Every Alertmanager peer delivers the notification to both replicas
+	for i := 0; i < 3; i++ {
+		CreateResponseJob(replicaA, store, alert, "firing")
+		CreateResponseJob(&replicaB, store, alert, "firing")
+	}
+
+	jobs, err := clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(jobs.Items) != 1 {
+		t.Fatalf("expected 1 job for the episode, got %v (err %v)", len(jobs.Items), err)
+	}
+	if name := jobs.Items[0].Name; !strings.HasPrefix(name, "testalert-firing-") || len(name) > 63 {
+		t.Errorf("episode job name = %q", name)
+	}
+
+	entries, err := store.GetAlerts("", 0)
+	if err != nil {
+		t.Fatalf("Failed to get alerts: %v", err)
+	}
+	if len(entries) != 1 || entries[0].JobInfo == nil || entries[0].JobInfo.SkipReason != "" {
+		t.Errorf("expected 1 alert entry with the created job, got %+v", entries)
+	}
+
+	// The claim outlives the job, a repeated notification after its TTL does not start it again
+	jobName := jobs.Items[0].Name
+	if err := clientset.BatchV1().Jobs("openfero").Delete(context.TODO(), jobName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Failed to delete job: %v", err)
+	}
+	CreateResponseJob(&replicaB, store, alert, "firing")
+	if jobs, err := clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{}); err != nil || len(jobs.Items) != 0 {
+		t.Fatalf("expected no job after a repeated notification, got %v (err %v)", len(jobs.Items), err)
+	}
+	if entries, _ := store.GetAlerts("", 0); len(entries) != 1 {
+		t.Errorf("expected the repeated notification to be ignored, got %d entries", len(entries))
+	}
+
+	// Expired claims are deleted
+	if expired := replicaA.ExpireEpisodeClaims(time.Hour, time.Now().Add(2*time.Hour)); expired != 1 {
+		t.Errorf("expired %d claims; want 1", expired)
+	}
+	if claimed, err := replicaA.EpisodeClaimed(jobName); err != nil || claimed {
+		t.Errorf("EpisodeClaimed = %v, %v after expiry; want false", claimed, err)
+	}
+
+	// A new episode of the same alert gets its own job
+	jobA := jobs.Items[0]
+	if err := replicaA.JobStore.Add(&jobA); err != nil {
+		t.Fatalf("Failed to add job to store: %v", err)
+	}
+	jobA.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	if err := replicaA.JobStore.Update(&jobA); err != nil {
+		t.Fatalf("Failed to update job in store: %v", err)
+	}
+	alert.StartsAt = "2026-01-02T05:00:00Z"
+	CreateResponseJob(replicaA, store, alert, "firing")
+
+	jobs, err = clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(jobs.Items) != 1 || jobs.Items[0].Name == jobName {
+		t.Fatalf("expected a new job after a new episode, got %v (err %v)", jobs.Items, err)
+	}
+}
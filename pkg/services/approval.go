@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/metadata"
+	"go.uber.org/zap"
+)
+
+// ApproveJob unsuspends a job waiting for approval and records the decision in the alert store
+func ApproveJob(client *kubernetes.Client, alertStore alertstore.Store, jobName string, approver string) error {
+	now := time.Now()
+	if err := client.ApproveJob(jobName, approver, now); err != nil {
+		return err
+	}
+	metadata.ApprovalsTotal.WithLabelValues(client.Name, kubernetes.ApprovalApproved).Inc()
+	recordApprovalDecision(alertStore, jobName, kubernetes.ApprovalApproved, approver, now)
+	return nil
+}
+
+// RejectJob deletes a job waiting for approval and records the decision in the alert store
+func RejectJob(client *kubernetes.Client, alertStore alertstore.Store, jobName string, approver string) error {
+	now := time.Now()
+	if err := client.RejectJob(jobName, approver); err != nil {
+		return err
+	}
+	metadata.ApprovalsTotal.WithLabelValues(client.Name, kubernetes.ApprovalRejected).Inc()
+	recordApprovalDecision(alertStore, jobName, kubernetes.ApprovalRejected, approver, now)
+	return nil
+}
+
+// ExpirePendingApprovals deletes jobs in all clusters whose approval expired
+func ExpirePendingApprovals(clusters *kubernetes.ClusterSet, alertStore alertstore.Store) {
+	now := time.Now()
+	for _, client := range clusters.List() {
+		for _, jobName := range client.ExpirePendingApprovals(now) {
+			metadata.ApprovalsTotal.WithLabelValues(client.Name, kubernetes.ApprovalExpired).Inc()
+			recordApprovalDecision(alertStore, jobName, kubernetes.ApprovalExpired, "", now)
+		}
+	}
+}
+
+// recordApprovalDecision stores the approval decision on the alert entry that triggered the job
+func recordApprovalDecision(alertStore alertstore.Store, jobName string, state string, decidedBy string, decidedAt time.Time) {
+	err := alertStore.UpdateJobInfo(jobName, func(jobInfo *alertstore.JobInfo) {
+		approval := &alertstore.Approval{State: state, DecidedBy: decidedBy, DecidedAt: decidedAt}
+		if jobInfo.Approval != nil {
+			approval.ExpiresAt = jobInfo.Approval.ExpiresAt
+		}
+		jobInfo.Approval = approval
+	})
+	if errors.Is(err, alertstore.ErrNotFound) {
+		log.Warn("No alert entry found for job, approval decision not recorded",
+			zap.String("job", jobName),
+			zap.String("decision", state))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to record approval decision",
+			zap.String("job", jobName),
+			zap.String("decision", state),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/metadata"
+	"github.com/OpenFero/openfero/pkg/models"
+	"github.com/OpenFero/openfero/pkg/utils"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CancelFiringJobs deletes the running jobs started for the firing alert if its
+// definition has cancelOnResolve set, and returns the names of the cancelled jobs
+func CancelFiringJobs(client *kubernetes.Client, alertStore alertstore.Store, alert models.Alert) []string {
+	alertname := utils.SanitizeInput(alert.Labels["alertname"])
+	firingConfigmap := strings.ToLower("openfero-" + alertname + "-firing")
+
+	obj, exists, err := client.ConfigMapStore.GetByKey(client.ConfigmapNamespace + "/" + firingConfigmap)
+	if err != nil || !exists {
+		return nil
+	}
+	options, err := kubernetes.GetDefinitionOptions(obj.(*corev1.ConfigMap))
+	if err != nil || !options.CancelOnResolve {
+		return nil
+	}
+
+	fingerprint := alert.GetFingerprint()
+	var cancelled []string
+	for _, job := range client.ListRunningJobs(kubernetes.AlertScope(firingConfigmap, fingerprint)) {
+		log.Info("Alert resolved, cancelling running job",
+			zap.String("cluster", client.Name),
+			zap.String("job", job.Name),
+			zap.String("alertname", alertname),
+			zap.String("fingerprint", fingerprint))
+		if err := client.DeleteJob(job.Name, metav1.DeletePropagationForeground); err != nil {
+			continue
+		}
+		metadata.JobsCancelledTotal.WithLabelValues(client.Name).Inc()
+		recordCancellation(alertStore, job.Name, time.Now())
+		cancelled = append(cancelled, job.Name)
+	}
+	return cancelled
+}
+
+// recordCancellation stores the cancellation on the alert entry that triggered the job
+func recordCancellation(alertStore alertstore.Store, jobName string, cancelledAt time.Time) {
+	err := alertStore.UpdateJobInfo(jobName, func(jobInfo *alertstore.JobInfo) {
+		jobInfo.CancelledAt = cancelledAt
+	})
+	if errors.Is(err, alertstore.ErrNotFound) {
+		log.Warn("No alert entry found for job, cancellation not recorded", zap.String("job", jobName))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to record cancellation", zap.String("job", jobName), zap.Error(err))
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SkipReasonConcurrencyForbidden = "concurrency-forbidden"
+
+	// reservationTimeout bounds how long a created job is remembered before the job informer must have seen it
+	reservationTimeout = time.Minute
+)
+
+// reservation is a job that was just created but may not be in the job store yet
+type reservation struct {
+	jobName   string
+	createdAt time.Time
+}
+
+// concurrencyGuard closes the gap between creating a job and the job informer seeing it,
+// so that two alerts handled at the same time cannot both pass the concurrency check
+type concurrencyGuard struct {
+	mutex    sync.Mutex
+	reserved map[string]reservation
+	now      func() time.Time
+}
+
+var concurrency = &concurrencyGuard{
+	reserved: make(map[string]reservation),
+	now:      time.Now,
+}
+
+// acquire applies the concurrency policy of a definition to the scope of the job. It returns false
+// if the job must be skipped, and the names of running jobs that must be replaced.
+func (g *concurrencyGuard) acquire(client *kubernetes.Client, configMapName string, lockKey string, policy string, jobName string) (bool, []string) {
+	if policy == kubernetes.ConcurrencyPolicyAllow {
+		return true, nil
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	scope := kubernetes.ConcurrencyScope(configMapName, lockKey)
+	key := client.Name + "/" + scope.String()
+
+	var running []string
+	for _, job := range client.ListRunningJobs(scope) {
+		running = append(running, job.Name)
+	}
+	if pending, ok := g.reserved[key]; ok {
+		if g.inJobStore(client, pending.jobName) || g.now().Sub(pending.createdAt) > reservationTimeout {
+			delete(g.reserved, key)
+		} else {
+			running = append(running, pending.jobName)
+		}
+	}
+
+	if len(running) > 0 && policy != kubernetes.ConcurrencyPolicyReplace {
+		return false, running
+	}
+
+	g.reserved[key] = reservation{jobName: jobName, createdAt: g.now()}
+	return true, running
+}
+
+// release drops the reservation of a job that could not be created
+func (g *concurrencyGuard) release(client *kubernetes.Client, configMapName string, lockKey string, jobName string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	key := client.Name + "/" + kubernetes.ConcurrencyScope(configMapName, lockKey).String()
+	if pending, ok := g.reserved[key]; ok && pending.jobName == jobName {
+		delete(g.reserved, key)
+	}
+}
+
+// inJobStore reports whether the job informer has seen the job
+func (g *concurrencyGuard) inJobStore(client *kubernetes.Client, jobName string) bool {
+	_, exists, err := client.JobStore.GetByKey(client.JobDestinationNamespace + "/" + jobName)
+	return err == nil && exists
+}
+
+// replaceJobs deletes the running jobs replaced by a new job, jobs that are already gone are ignored
+func replaceJobs(client *kubernetes.Client, jobNames []string, replacement *batchv1.Job) []string {
+	var replaced []string
+	for _, jobName := range jobNames {
+		log.Info("Replacing running job",
+			zap.String("cluster", client.Name),
+			zap.String("job", jobName),
+			zap.String("replacement", replacement.Name))
+		err := client.DeleteJob(jobName, metav1.DeletePropagationBackground)
+		if err != nil && !apierrors.IsNotFound(err) {
+			continue
+		}
+		replaced = append(replaced, jobName)
+	}
+	return replaced
+}
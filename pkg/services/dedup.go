@@ -0,0 +1,83 @@
+package services
+
+import (
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/metadata"
+	"go.uber.org/zap"
+)
+
+// deduplicateJobs names jobs after their alert episode and claims the episode with a Lease,
+// so that notifications delivered to several replicas or by several Alertmanager peers create one job
+var deduplicateJobs bool
+
+// SetDeduplication enables or disables the deduplication of response jobs
+func SetDeduplication(enabled bool) {
+	deduplicateJobs = enabled
+}
+
+// episodeJobExists reports whether the job of an alert episode was already created by this
+// or another replica, even if it was removed since. The job store is checked first, the
+// episode claim and the job on the API server only if asked to.
+func episodeJobExists(client *kubernetes.Client, jobName string, checkAPI bool) bool {
+	if _, exists, err := client.JobStore.GetByKey(client.JobDestinationNamespace + "/" + jobName); err == nil && exists {
+		return true
+	}
+	if !checkAPI {
+		return false
+	}
+	claimed, err := client.EpisodeClaimed(jobName)
+	if err == nil && claimed {
+		return true
+	}
+	if err != nil {
+		log.Warn("Could not check for a claim of the alert episode",
+			zap.String("cluster", client.Name),
+			zap.String("job", jobName),
+			zap.Error(err))
+	}
+	exists, err := client.JobExists(jobName)
+	if err != nil {
+		log.Warn("Could not check for an existing job of the alert episode",
+			zap.String("cluster", client.Name),
+			zap.String("job", jobName),
+			zap.Error(err))
+		return false
+	}
+	return exists
+}
+
+// claimEpisode claims the alert episode of a job before it is created. If the claim cannot be
+// created, e.g. because the Lease permission is missing, the episode job name alone deduplicates.
+func claimEpisode(client *kubernetes.Client, jobName string, startsAt string) bool {
+	claimed, err := client.ClaimEpisode(jobName, startsAt, time.Now())
+	if err != nil {
+		log.Warn("Could not claim the alert episode, relying on the job name",
+			zap.String("cluster", client.Name),
+			zap.String("job", jobName),
+			zap.Error(err))
+		return true
+	}
+	return claimed
+}
+
+// ExpireEpisodeClaims deletes the episode claims in all clusters that are older than retention
+func ExpireEpisodeClaims(clusters *kubernetes.ClusterSet, retention time.Duration) {
+	now := time.Now()
+	for _, client := range clusters.List() {
+		if expired := client.ExpireEpisodeClaims(retention, now); expired > 0 {
+			log.Debug("Expired episode claims", zap.String("cluster", client.Name), zap.Int("count", expired))
+		}
+	}
+}
+
+// recordDuplicate logs and counts a notification whose job already exists
+func recordDuplicate(client *kubernetes.Client, alertname string, jobName string) {
+	log.Info("Job of alert episode already exists, ignoring duplicate notification",
+		zap.String("cluster", client.Name),
+		zap.String("job", jobName),
+		zap.String("alertname", alertname))
+	metadata.JobsDeduplicatedTotal.WithLabelValues(client.Name).Inc()
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/OpenFero/openfero/pkg/alertstore/memory"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	"github.com/OpenFero/openfero/pkg/models"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+)
+
+// recordedEvents drains the events of a fake recorder
+func recordedEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestCreateResponseJobEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		alertname   string
+		createErr   error
+		runs        int
+		want        []string
+	}{
+		{
+			name:      "definition missing",
+			alertname: "UnknownAlert",
+			runs:      1,
+			want:      []string{"Warning DefinitionMissing No job created for alert UnknownAlert (fingerprint c0ffee), ConfigMap openfero-unknownalert-firing not found"},
+		},
+		{
+			name: "job created",
+			runs: 1,
+			want: []string{"Normal JobCreated Created job", "Normal JobCreated Created from definition openfero-testalert-firing"},
+		},
+		{
+			name:        "invalid definition",
+			annotations: map[string]string{kubernetes.RateLimitAnnotation: "often"},
+			runs:        1,
+			want:        []string{"Warning DefinitionInvalid No job created for alert TestAlert (fingerprint c0ffee)"},
+		},
+		{
+			name:        "dispatch suppressed",
+			annotations: map[string]string{kubernetes.RateLimitAnnotation: "1/1h"},
+			runs:        2,
+			want:        []string{"Normal JobCreated", "Normal JobCreated", "Warning DispatchSuppressed No job created for alert TestAlert (fingerprint c0ffee): rate-limited"},
+		},
+		{
+			name:      "creation forbidden",
+			createErr: apierrors.NewForbidden(schema.GroupResource{Group: "batch", Resource: "jobs"}, "job", nil),
+			runs:      1,
+			want:      []string{"Warning RemediationFailed Job testalert-firing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, clientset := newTestClient(t, tt.annotations)
+			recorder := record.NewFakeRecorder(10)
+			client.Recorder = recorder
+			if tt.createErr != nil {
+				failCreates(clientset, 1, tt.createErr, false)
+			}
+			SetLimits(NewLimits(0))
+			defer SetLimits(nil)
+
+			store := memory.NewMemoryStore(10)
+			alertname := tt.alertname
+			if alertname == "" {
+				alertname = "TestAlert"
+			}
+			alert := models.Alert{Labels: map[string]string{"alertname": alertname}, Fingerprint: "c0ffee"}
+			for i := 0; i < tt.runs; i++ {
+				CreateResponseJob(client, store, alert, "firing")
+			}
+
+			events := recordedEvents(recorder)
+			if len(events) != len(tt.want) {
+				t.Fatalf("events = %q; want %d events", events, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(events[i], want) {
+					t.Errorf("event %d = %q; want prefix %q", i, events[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/metadata"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	SkipReasonRateLimited    = "rate-limited"
+	SkipReasonCircuitOpen    = "circuit-open"
+	SkipReasonConcurrencyCap = "concurrency-cap"
+)
+
+// Limits enforces per-definition rate limits, per-definition circuit breakers
+// and a global cap on running jobs. It learns about running and finished jobs
+// from the job informers of all clusters. Suspended jobs waiting for approval
+// do not count as running.
+type Limits struct {
+	maxConcurrentJobs int
+	mutex             sync.Mutex
+	running           map[string]struct{}
+	reservations      map[string]*definitionState // definitions of jobs being created
+	definitions       map[string]*definitionState
+	now               func() time.Time
+}
+
+// definitionState tracks the rate limiter and consecutive failures of a definition
+type definitionState struct {
+	rateLimit           string
+	limiter             *rate.Limiter
+	reserved            int // tokens held back for jobs being created
+	threshold           int
+	cooldown            time.Duration
+	consecutiveFailures int
+	lastFailure         time.Time
+	openedAt            time.Time
+}
+
+// DefinitionState describes the limit and breaker state of a definition
+type DefinitionState struct {
+	Cluster             string
+	ConfigMapName       string
+	RateLimit           string
+	ConsecutiveFailures int
+	BreakerThreshold    int
+	BreakerOpen         bool
+	BreakerOpenedAt     time.Time
+	BreakerClosesAt     time.Time
+}
+
+// dispatchLimits is used by CreateResponseJob, nil means no limits are enforced
+var dispatchLimits *Limits
+
+// SetLimits sets the limits enforced when creating response jobs
+func SetLimits(limits *Limits) {
+	dispatchLimits = limits
+}
+
+// NewLimits creates limits with a global cap of maxConcurrentJobs running jobs, 0 is unlimited
+func NewLimits(maxConcurrentJobs int) *Limits {
+	return &Limits{
+		maxConcurrentJobs: maxConcurrentJobs,
+		running:           make(map[string]struct{}),
+		reservations:      make(map[string]*definitionState),
+		definitions:       make(map[string]*definitionState),
+		now:               time.Now,
+	}
+}
+
+// definitionKey identifies a definition across clusters
+func definitionKey(cluster string, configMapName string) string {
+	return cluster + "/" + configMapName
+}
+
+// jobKey identifies a job across clusters
+func jobKey(cluster string, namespace string, jobName string) string {
+	return cluster + "/" + namespace + "/" + jobName
+}
+
+// Acquire checks whether a job of the definition may be started and reserves a
+// running slot and a rate limit token for it. It returns the reason if the job
+// must be skipped. Each successful Acquire is followed by Commit or Release.
+func (l *Limits) Acquire(client *kubernetes.Client, configMapName string, jobName string, options kubernetes.DefinitionOptions) (string, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	key := definitionKey(client.Name, configMapName)
+	now := l.now()
+	definition := l.definition(key)
+
+	// Circuit breaker
+	definition.threshold = options.CircuitBreakerThreshold
+	definition.cooldown = options.CircuitBreakerCooldown
+	definition.halfOpen(now)
+	open := definition.isOpen(now)
+	metadata.CircuitBreakerOpen.WithLabelValues(client.Name, configMapName).Set(boolToFloat(open))
+	if open {
+		return SkipReasonCircuitOpen, false
+	}
+
+	// Global cap on running jobs
+	if l.maxConcurrentJobs > 0 && len(l.running) >= l.maxConcurrentJobs {
+		return SkipReasonConcurrencyCap, false
+	}
+
+	// Per-definition token bucket, recreated when the annotation changes
+	rateLimit := kubernetes.FormatRate(options.RateLimit, options.RateLimitPeriod)
+	if rateLimit != definition.rateLimit {
+		definition.rateLimit = rateLimit
+		definition.limiter = nil
+		if options.RateLimit > 0 {
+			definition.limiter = rate.NewLimiter(rate.Every(options.RateLimitPeriod/time.Duration(options.RateLimit)), options.RateLimit)
+		}
+	}
+	// The token is only taken once the job is created, jobs being created hold it back
+	if definition.limiter != nil && definition.limiter.TokensAt(now)-float64(definition.reserved) < 1 {
+		return SkipReasonRateLimited, false
+	}
+	job := jobKey(client.Name, client.JobDestinationNamespace, jobName)
+	definition.reserved++
+	l.reservations[job] = definition
+
+	// Jobs waiting for approval count once they are approved
+	if !options.RequiresApproval {
+		l.running[job] = struct{}{}
+	}
+	return "", true
+}
+
+// definition returns the state of a definition, creating it if needed
+func (l *Limits) definition(key string) *definitionState {
+	definition := l.definitions[key]
+	if definition == nil {
+		definition = &definitionState{}
+		l.definitions[key] = definition
+	}
+	return definition
+}
+
+// Commit takes the rate limit token of a job that was created
+func (l *Limits) Commit(client *kubernetes.Client, jobName string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if definition := l.unreserve(jobKey(client.Name, client.JobDestinationNamespace, jobName)); definition != nil && definition.limiter != nil {
+		definition.limiter.AllowN(l.now(), 1)
+	}
+}
+
+// Release frees the running slot and the rate limit token of a job that could not be created
+func (l *Limits) Release(client *kubernetes.Client, jobName string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	key := jobKey(client.Name, client.JobDestinationNamespace, jobName)
+	delete(l.running, key)
+	l.unreserve(key)
+}
+
+// unreserve drops the token held back for a job and returns its definition, nil if it holds none
+func (l *Limits) unreserve(key string) *definitionState {
+	definition, ok := l.reservations[key]
+	if !ok {
+		return nil
+	}
+	delete(l.reservations, key)
+	definition.reserved--
+	return definition
+}
+
+// ResetBreaker closes the circuit breaker of a definition
+func (l *Limits) ResetBreaker(cluster string, configMapName string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	definition, ok := l.definitions[definitionKey(cluster, configMapName)]
+	if !ok {
+		return fmt.Errorf("no circuit breaker for definition %s in cluster %s", configMapName, cluster)
+	}
+	definition.consecutiveFailures = 0
+	definition.openedAt = time.Time{}
+	metadata.CircuitBreakerOpen.WithLabelValues(cluster, configMapName).Set(0)
+	log.Info("Circuit breaker reset", zap.String("cluster", cluster), zap.String("configmap", configMapName))
+	return nil
+}
+
+// States returns the limit and breaker state of all known definitions
+func (l *Limits) States() []DefinitionState {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	states := make([]DefinitionState, 0, len(l.definitions))
+	for key, definition := range l.definitions {
+		cluster, configMapName, _ := strings.Cut(key, "/")
+		states = append(states, definition.state(cluster, configMapName, now))
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Cluster != states[j].Cluster {
+			return states[i].Cluster < states[j].Cluster
+		}
+		return states[i].ConfigMapName < states[j].ConfigMapName
+	})
+	return states
+}
+
+// State returns the limit and breaker state of a single definition
+func (l *Limits) State(cluster string, configMapName string) (DefinitionState, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	definition, ok := l.definitions[definitionKey(cluster, configMapName)]
+	if !ok {
+		return DefinitionState{}, false
+	}
+	return definition.state(cluster, configMapName, l.now()), true
+}
+
+// JobEventHandler returns an informer event handler tracking the jobs of a cluster
+func (l *Limits) JobEventHandler(cluster string) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			job, ok := obj.(*batchv1.Job)
+			if !ok {
+				return
+			}
+			if finished, _ := kubernetes.GetJobFinished(job); !finished && !isSuspended(job) {
+				l.mutex.Lock()
+				l.running[jobKey(cluster, job.Namespace, job.Name)] = struct{}{}
+				l.mutex.Unlock()
+			}
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldJob, okOld := old.(*batchv1.Job)
+			newJob, okNew := new.(*batchv1.Job)
+			if !okOld || !okNew {
+				return
+			}
+			wasFinished, _ := kubernetes.GetJobFinished(oldJob)
+			finished, succeeded := kubernetes.GetJobFinished(newJob)
+			if finished && !wasFinished {
+				l.jobFinished(cluster, newJob, succeeded)
+				return
+			}
+			if !finished && isSuspended(oldJob) && !isSuspended(newJob) {
+				// The job was approved and starts now
+				l.mutex.Lock()
+				l.running[jobKey(cluster, newJob.Namespace, newJob.Name)] = struct{}{}
+				l.mutex.Unlock()
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			job, ok := obj.(*batchv1.Job)
+			if !ok {
+				return
+			}
+			l.mutex.Lock()
+			delete(l.running, jobKey(cluster, job.Namespace, job.Name))
+			l.mutex.Unlock()
+		},
+	}
+}
+
+// jobFinished frees the running slot of a job and feeds its outcome to the circuit breaker
+func (l *Limits) jobFinished(cluster string, job *batchv1.Job, succeeded bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.running, jobKey(cluster, job.Namespace, job.Name))
+
+	configMapName := job.Annotations[kubernetes.DefinitionAnnotation]
+	if configMapName == "" {
+		return
+	}
+	definition := l.definition(definitionKey(cluster, configMapName))
+
+	if succeeded {
+		definition.consecutiveFailures = 0
+		definition.openedAt = time.Time{}
+		metadata.CircuitBreakerOpen.WithLabelValues(cluster, configMapName).Set(0)
+		return
+	}
+
+	definition.consecutiveFailures++
+	definition.lastFailure = l.now()
+	if definition.tripped() && definition.openedAt.IsZero() {
+		definition.openedAt = definition.lastFailure
+		log.Warn("Circuit breaker opened",
+			zap.String("cluster", cluster),
+			zap.String("configmap", configMapName),
+			zap.Int("consecutiveFailures", definition.consecutiveFailures))
+		metadata.CircuitBreakerOpen.WithLabelValues(cluster, configMapName).Set(1)
+	}
+}
+
+// isSuspended reports whether a job is suspended, e.g. while it waits for approval
+func isSuspended(job *batchv1.Job) bool {
+	return job.Spec.Suspend != nil && *job.Spec.Suspend
+}
+
+// tripped reports whether enough jobs failed in a row to open the breaker
+func (b *definitionState) tripped() bool {
+	return b.threshold > 0 && b.consecutiveFailures >= b.threshold
+}
+
+// isOpen reports whether the breaker stops runs at the given time. It does not
+// change the breaker, so it is safe to call from read-only paths.
+func (b *definitionState) isOpen(now time.Time) bool {
+	return b.tripped() && now.Sub(b.openedAt) < b.cooldown
+}
+
+// halfOpen lets one run through once the cooldown of an open breaker elapsed;
+// another failure opens it again. It is only called when a job is acquired.
+func (b *definitionState) halfOpen(now time.Time) {
+	if !b.tripped() {
+		return
+	}
+	if b.openedAt.IsZero() {
+		// The threshold was lowered below the failures seen so far
+		b.openedAt = b.lastFailure
+	}
+	if now.Sub(b.openedAt) >= b.cooldown {
+		b.consecutiveFailures = b.threshold - 1
+		b.openedAt = time.Time{}
+	}
+}
+
+// state returns the limit and breaker state of the definition at the given time
+func (b *definitionState) state(cluster string, configMapName string, now time.Time) DefinitionState {
+	state := DefinitionState{
+		Cluster:             cluster,
+		ConfigMapName:       configMapName,
+		RateLimit:           b.rateLimit,
+		ConsecutiveFailures: b.consecutiveFailures,
+		BreakerThreshold:    b.threshold,
+		BreakerOpen:         b.isOpen(now),
+	}
+	if state.BreakerOpen {
+		state.BreakerOpenedAt = b.openedAt
+		state.BreakerClosesAt = b.openedAt.Add(b.cooldown)
+	}
+	return state
+}
+
+// boolToFloat converts a bool to a gauge value
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore/memory"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	"github.com/OpenFero/openfero/pkg/models"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// finishedJob returns a job of the test definition with a Complete or Failed condition
+func finishedJob(name string, succeeded bool) (*batchv1.Job, *batchv1.Job) {
+	running := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "openfero",
+			Annotations: map[string]string{kubernetes.DefinitionAnnotation: "openfero-testalert-firing"},
+		},
+	}
+	finished := running.DeepCopy()
+	conditionType := batchv1.JobFailed
+	if succeeded {
+		conditionType = batchv1.JobComplete
+	}
+	finished.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue}}
+	return running, finished
+}
+
+func TestLimitsRateLimit(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	limits := NewLimits(0)
+	options := kubernetes.DefinitionOptions{RateLimit: 2, RateLimitPeriod: time.Hour}
+
+	for i, name := range []string{"job-1", "job-2"} {
+		if reason, ok := limits.Acquire(client, "openfero-testalert-firing", name, options); !ok {
+			t.Fatalf("run %d was skipped with reason %s", i+1, reason)
+		}
+		limits.Commit(client, name)
+	}
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-3", options); ok || reason != SkipReasonRateLimited {
+		t.Errorf("third run = (%q, %v); want (%q, false)", reason, ok, SkipReasonRateLimited)
+	}
+
+	// A token is refilled after half of the period
+	limits.now = func() time.Time { return time.Now().Add(31 * time.Minute) }
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-4", options); !ok {
+		t.Errorf("run after refill was skipped with reason %s", reason)
+	}
+}
+
+func TestLimitsReleaseReturnsToken(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	limits := NewLimits(0)
+	options := kubernetes.DefinitionOptions{RateLimit: 1, RateLimitPeriod: time.Hour}
+
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-1", options); !ok {
+		t.Fatalf("first run was skipped with reason %s", reason)
+	}
+	// The job could not be created, so the run did not happen
+	limits.Release(client, "job-1")
+
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-2", options); !ok {
+		t.Fatalf("run after a failed creation was skipped with reason %s", reason)
+	}
+	limits.Commit(client, "job-2")
+	limits.Release(client, "job-2")
+
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-3", options); ok || reason != SkipReasonRateLimited {
+		t.Errorf("run after a created job = (%q, %v); want (%q, false)", reason, ok, SkipReasonRateLimited)
+	}
+}
+
+func TestLimitsConcurrencyCapIgnoresSuspendedJobs(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	limits := NewLimits(1)
+	handler := limits.JobEventHandler(client.Name)
+
+	if _, ok := limits.Acquire(client, "openfero-testalert-firing", "job-1", kubernetes.DefinitionOptions{RequiresApproval: true}); !ok {
+		t.Fatalf("first run was skipped")
+	}
+	pending, _ := finishedJob("job-1", true)
+	suspend := true
+	pending.Spec.Suspend = &suspend
+	handler.OnAdd(pending, false)
+
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-2", kubernetes.DefinitionOptions{}); !ok {
+		t.Fatalf("run next to a pending job was skipped with reason %s", reason)
+	}
+
+	// Once approved, the pending job counts as running
+	handler.OnAdd(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job-2", Namespace: "openfero"}}, false)
+	limits.Release(client, "job-2")
+	approved := pending.DeepCopy()
+	resume := false
+	approved.Spec.Suspend = &resume
+	handler.OnUpdate(pending, approved)
+
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-3", kubernetes.DefinitionOptions{}); ok || reason != SkipReasonConcurrencyCap {
+		t.Errorf("run next to an approved job = (%q, %v); want (%q, false)", reason, ok, SkipReasonConcurrencyCap)
+	}
+}
+
+func TestLimitsConcurrencyCap(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	limits := NewLimits(1)
+	handler := limits.JobEventHandler(client.Name)
+
+	if _, ok := limits.Acquire(client, "openfero-testalert-firing", "job-1", kubernetes.DefinitionOptions{}); !ok {
+		t.Fatalf("first run was skipped")
+	}
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-2", kubernetes.DefinitionOptions{}); ok || reason != SkipReasonConcurrencyCap {
+		t.Errorf("second run = (%q, %v); want (%q, false)", reason, ok, SkipReasonConcurrencyCap)
+	}
+
+	running, finished := finishedJob("job-1", true)
+	handler.OnUpdate(running, finished)
+
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-2", kubernetes.DefinitionOptions{}); !ok {
+		t.Errorf("run after finished job was skipped with reason %s", reason)
+	}
+}
+
+func TestLimitsCircuitBreaker(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	limits := NewLimits(0)
+	handler := limits.JobEventHandler(client.Name)
+	options := kubernetes.DefinitionOptions{CircuitBreakerThreshold: 2, CircuitBreakerCooldown: time.Hour}
+	now := time.Now()
+	limits.now = func() time.Time { return now }
+
+	for _, name := range []string{"job-1", "job-2"} {
+		if _, ok := limits.Acquire(client, "openfero-testalert-firing", name, options); !ok {
+			t.Fatalf("run %s was skipped", name)
+		}
+		running, failed := finishedJob(name, false)
+		handler.OnUpdate(running, failed)
+	}
+
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-3", options); ok || reason != SkipReasonCircuitOpen {
+		t.Fatalf("run with open breaker = (%q, %v); want (%q, false)", reason, ok, SkipReasonCircuitOpen)
+	}
+	state, ok := limits.State(client.Name, "openfero-testalert-firing")
+	if !ok || !state.BreakerOpen || !state.BreakerClosesAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("state = %+v; want open until %s", state, now.Add(time.Hour))
+	}
+
+	// After the cooldown one run goes through, another failure opens the breaker again
+	now = now.Add(time.Hour)
+	limits.States()
+	if state, _ := limits.State(client.Name, "openfero-testalert-firing"); state.ConsecutiveFailures != 2 {
+		t.Errorf("state after cooldown = %+v; want reads to leave the breaker unchanged", state)
+	}
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-4", options); !ok {
+		t.Fatalf("run after cooldown was skipped with reason %s", reason)
+	}
+	running, failed := finishedJob("job-4", false)
+	handler.OnUpdate(running, failed)
+	if _, ok := limits.Acquire(client, "openfero-testalert-firing", "job-5", options); ok {
+		t.Fatalf("breaker did not open again after a failed half-open run")
+	}
+
+	if err := limits.ResetBreaker(client.Name, "openfero-testalert-firing"); err != nil {
+		t.Fatalf("ResetBreaker failed: %v", err)
+	}
+	if reason, ok := limits.Acquire(client, "openfero-testalert-firing", "job-6", options); !ok {
+		t.Errorf("run after reset was skipped with reason %s", reason)
+	}
+	if err := limits.ResetBreaker(client.Name, "unknown"); err == nil {
+		t.Errorf("ResetBreaker of an unknown definition returned no error")
+	}
+}
+
+func TestCreateResponseJobSkipped(t *testing.T) {
+	client, clientset := newTestClient(t, map[string]string{
+		kubernetes.RateLimitAnnotation: "1/1h",
+	})
+	store := memory.NewMemoryStore(10)
+	alert := models.Alert{Labels: map[string]string{"alertname": "TestAlert"}}
+
+	SetLimits(NewLimits(0))
+	defer SetLimits(nil)
+
+	CreateResponseJob(client, store, alert, "firing")
+	CreateResponseJob(client, store, alert, "firing")
+
+	jobs, err := clientset.BatchV1().Jobs("openfero").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(jobs.Items) != 1 {
+		t.Fatalf("expected 1 job, got %v (err %v)", len(jobs.Items), err)
+	}
+	if jobs.Items[0].Annotations[kubernetes.DefinitionAnnotation] != "openfero-testalert-firing" {
+		t.Errorf("job has no definition annotation: %v", jobs.Items[0].Annotations)
+	}
+
+	entries, err := store.GetAlerts("", 0)
+	if err != nil {
+		t.Fatalf("Failed to get alerts: %v", err)
+	}
+	skipped := 0
+	for _, entry := range entries {
+		if entry.JobInfo != nil && entry.JobInfo.SkipReason == SkipReasonRateLimited {
+			skipped++
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("expected 1 rate-limited alert, got %d", skipped)
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// JobOutcomeEventHandler returns an informer event handler that records when the jobs of a
+// cluster started and finished, and whether they succeeded, on the alert entries that triggered them
+func JobOutcomeEventHandler(cluster string, alertStore alertstore.Store) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldJob, okOld := old.(*batchv1.Job)
+			newJob, okNew := new.(*batchv1.Job)
+			if !okOld || !okNew {
+				return
+			}
+			if finished, _ := kubernetes.GetJobFinished(oldJob); finished {
+				return
+			}
+			if finished, succeeded := kubernetes.GetJobFinished(newJob); finished {
+				recordJobOutcome(cluster, alertStore, newJob, succeeded)
+			}
+		},
+	}
+}
+
+// recordJobOutcome stores the start, completion and outcome of a finished job
+func recordJobOutcome(cluster string, alertStore alertstore.Store, job *batchv1.Job, succeeded bool) {
+	outcome := alertstore.JobOutcomeFailed
+	if succeeded {
+		outcome = alertstore.JobOutcomeSucceeded
+	}
+	completedAt := jobCompletionTime(job)
+
+	err := alertStore.UpdateJobInfo(job.Name, func(jobInfo *alertstore.JobInfo) {
+		if job.Status.StartTime != nil {
+			jobInfo.StartedAt = job.Status.StartTime.Time
+		}
+		jobInfo.CompletedAt = completedAt
+		jobInfo.Outcome = outcome
+	})
+	if errors.Is(err, alertstore.ErrNotFound) {
+		log.Debug("No alert entry found for job, outcome not recorded",
+			zap.String("cluster", cluster),
+			zap.String("job", job.Name))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to record job outcome",
+			zap.String("cluster", cluster),
+			zap.String("job", job.Name),
+			zap.Error(err))
+		return
+	}
+	log.Debug("Recorded job outcome",
+		zap.String("cluster", cluster),
+		zap.String("job", job.Name),
+		zap.String("outcome", outcome))
+}
+
+// jobCompletionTime returns when a finished job completed or failed
+func jobCompletionTime(job *batchv1.Job) time.Time {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status == corev1.ConditionTrue &&
+			(condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) &&
+			!condition.LastTransitionTime.IsZero() {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	if job.Status.CompletionTime != nil {
+		return job.Status.CompletionTime.Time
+	}
+	return time.Now()
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	"github.com/OpenFero/openfero/pkg/alertstore/memory"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJobOutcomeEventHandler(t *testing.T) {
+	store := memory.NewMemoryStore(10)
+	alert := alertstore.Alert{Labels: map[string]string{"alertname": "TestAlert"}}
+	for _, name := range []string{"job-ok", "job-failed"} {
+		if err := store.SaveAlertWithJobInfo(alert, "firing", &alertstore.JobInfo{JobName: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := JobOutcomeEventHandler("local", store)
+
+	startedAt := time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+	running, succeeded := finishedJob("job-ok", true)
+	succeeded.Status.StartTime = &metav1.Time{Time: startedAt}
+	succeeded.Status.Conditions[0].LastTransitionTime = metav1.Time{Time: startedAt.Add(time.Minute)}
+	handler.OnUpdate(running, succeeded)
+	// Later updates of a finished job keep the recorded outcome
+	handler.OnUpdate(succeeded, succeeded)
+	running, failed := finishedJob("job-failed", false)
+	handler.OnUpdate(running, failed)
+	// Jobs without an entry are ignored
+	running, unknown := finishedJob("job-unknown", true)
+	handler.OnUpdate(running, unknown)
+
+	entries, err := store.GetAlerts("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcomes := make(map[string]*alertstore.JobInfo)
+	for _, entry := range entries {
+		outcomes[entry.JobInfo.JobName] = entry.JobInfo
+	}
+	ok := outcomes["job-ok"]
+	if ok.Outcome != alertstore.JobOutcomeSucceeded || !ok.StartedAt.Equal(startedAt) || !ok.CompletedAt.Equal(startedAt.Add(time.Minute)) {
+		t.Errorf("job-ok = %+v; want succeeded after a minute", ok)
+	}
+	if failed := outcomes["job-failed"]; failed.Outcome != alertstore.JobOutcomeFailed || failed.CompletedAt.IsZero() {
+		t.Errorf("job-failed = %+v; want failed with a completion time", failed)
+	}
+}
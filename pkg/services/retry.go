@@ -0,0 +1,118 @@
+package services
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore"
+	"github.com/OpenFero/openfero/pkg/kubernetes"
+	log "github.com/OpenFero/openfero/pkg/logging"
+	"github.com/OpenFero/openfero/pkg/metadata"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RetryPolicy configures how often and how fast a failed job creation is retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, it doubles with every retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy retries a job creation up to 4 times within about 15 seconds
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+}
+
+// retryPolicy is used by CreateResponseJob
+var retryPolicy = DefaultRetryPolicy
+
+// sleep waits between attempts and is replaced in tests
+var sleep = time.Sleep
+
+// SetRetryPolicy sets the retry policy used when creating response jobs
+func SetRetryPolicy(policy RetryPolicy) {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	retryPolicy = policy
+}
+
+// backoff returns the wait before the given retry, doubling from InitialBackoff up to
+// MaxBackoff with equal jitter so that replicas do not retry in lockstep
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < retry && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// createJobWithRetry creates the job, retrying retryable API errors with backoff.
+// It returns every attempt, the created job and the error of the last attempt.
+func createJobWithRetry(client *kubernetes.Client, jobObject *batchv1.Job) ([]alertstore.CreateAttempt, *batchv1.Job, error) {
+	var attempts []alertstore.CreateAttempt
+	for attempt := 1; ; attempt++ {
+		createdJob, err := client.CreateRemediationJob(jobObject)
+
+		// A timed out attempt may have created the job after all
+		if err != nil && attempt > 1 && apierrors.IsAlreadyExists(err) {
+			log.Info("Job was created by an earlier attempt",
+				zap.String("cluster", client.Name),
+				zap.String("job", jobObject.Name))
+			createdJob, err = jobObject, nil
+		}
+
+		record := alertstore.CreateAttempt{Time: time.Now()}
+		if err != nil {
+			record.Error = err.Error()
+			record.Reason = kubernetes.ErrorReason(err)
+		}
+		attempts = append(attempts, record)
+
+		if err == nil {
+			return attempts, createdJob, nil
+		}
+		if !kubernetes.IsRetryableError(err) {
+			log.Error("Job creation failed with a permanent error",
+				zap.String("cluster", client.Name),
+				zap.String("job", jobObject.Name),
+				zap.String("reason", record.Reason),
+				zap.Error(err))
+			return attempts, nil, err
+		}
+		if attempt >= retryPolicy.MaxAttempts {
+			log.Error("Job creation failed, giving up",
+				zap.String("cluster", client.Name),
+				zap.String("job", jobObject.Name),
+				zap.Int("attempts", attempt),
+				zap.String("reason", record.Reason),
+				zap.Error(err))
+			return attempts, nil, err
+		}
+
+		wait := retryPolicy.backoff(attempt)
+		log.Warn("Job creation failed, retrying",
+			zap.String("cluster", client.Name),
+			zap.String("job", jobObject.Name),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", wait),
+			zap.String("reason", record.Reason),
+			zap.Error(err))
+		metadata.JobCreateRetriesTotal.WithLabelValues(client.Name, record.Reason).Inc()
+		sleep(wait)
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OpenFero/openfero/pkg/alertstore/memory"
+	"github.com/OpenFero/openfero/pkg/models"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// failCreates makes the first n job creations of the clientset fail with err.
+// With persist the job is stored anyway, like a create whose response timed out.
+func failCreates(clientset *fake.Clientset, n int, err error, persist bool) *int {
+	calls := 0
+	clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		if calls > n {
+			return false, nil, nil
+		}
+		if persist {
+			createAction := action.(k8stesting.CreateActionImpl)
+			if trackerErr := clientset.Tracker().Create(gvr, createAction.GetObject(), createAction.GetNamespace()); trackerErr != nil {
+				return true, nil, trackerErr
+			}
+		}
+		return true, nil, err
+	})
+	return &calls
+}
+
+var gvr = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	tests := []struct {
+		retry    int
+		min, max time.Duration
+	}{
+		{retry: 1, min: 500 * time.Millisecond, max: time.Second},
+		{retry: 2, min: time.Second, max: 2 * time.Second},
+		{retry: 3, min: 2 * time.Second, max: 4 * time.Second},
+		{retry: 4, min: 2500 * time.Millisecond, max: 5 * time.Second},
+		{retry: 10, min: 2500 * time.Millisecond, max: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if wait := policy.backoff(tt.retry); wait < tt.min || wait > tt.max {
+				t.Fatalf("backoff(%d) = %s; want between %s and %s", tt.retry, wait, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestCreateResponseJobRetries(t *testing.T) {
+	var waits []time.Duration
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	defer func() { sleep = time.Sleep }()
+
+	gr := schema.GroupResource{Group: "batch", Resource: "jobs"}
+	tests := []struct {
+		name          string
+		failures      int
+		err           error
+		wantCalls     int
+		wantAttempts  int
+		wantJob       bool
+		persist       bool
+		failureReason string
+	}{
+		{name: "transient error is retried", failures: 2, err: apierrors.NewTooManyRequests("slow down", 1), wantCalls: 3, wantAttempts: 3, wantJob: true},
+		{name: "attempts are capped", failures: 10, err: apierrors.NewServerTimeout(gr, "create", 1), wantCalls: 5, wantAttempts: 5, failureReason: "ServerTimeout"},
+		{name: "permanent error fails at once", failures: 10, err: apierrors.NewInvalid(schema.GroupKind{Group: "batch", Kind: "Job"}, "job", field.ErrorList{field.Required(field.NewPath("spec"), "")}), wantCalls: 1, wantAttempts: 1, failureReason: "Invalid"},
+		{name: "create that succeeded after a timeout", failures: 1, err: apierrors.NewServerTimeout(gr, "create", 1), wantCalls: 2, wantAttempts: 2, wantJob: true, persist: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waits = nil
+			client, clientset := newTestClient(t, nil)
+			store := memory.NewMemoryStore(10)
+			alert := models.Alert{Labels: map[string]string{"alertname": "TestAlert"}}
+			calls := failCreates(clientset, tt.failures, tt.err, tt.persist)
+
+			CreateResponseJob(client, store, alert, "firing")
+
+			if *calls != tt.wantCalls {
+				t.Errorf("create calls = %d; want %d", *calls, tt.wantCalls)
+			}
+			if len(waits) != tt.wantCalls-1 {
+				t.Errorf("backoff waits = %d; want %d", len(waits), tt.wantCalls-1)
+			}
+			entries, err := store.GetAlerts("", 0)
+			if err != nil || len(entries) != 1 || entries[0].JobInfo == nil {
+				t.Fatalf("expected 1 alert with job info, got %+v (err %v)", entries, err)
+			}
+			jobInfo := entries[0].JobInfo
+			if len(jobInfo.CreateAttempts) != tt.wantAttempts {
+				t.Errorf("recorded attempts = %d; want %d", len(jobInfo.CreateAttempts), tt.wantAttempts)
+			}
+			if jobInfo.FailureReason != tt.failureReason {
+				t.Errorf("failure reason = %q; want %q", jobInfo.FailureReason, tt.failureReason)
+			}
+			if last := jobInfo.CreateAttempts[len(jobInfo.CreateAttempts)-1]; (last.Error == "") != tt.wantJob {
+				t.Errorf("last attempt = %+v; want success %v", last, tt.wantJob)
+			}
+		})
+	}
+}